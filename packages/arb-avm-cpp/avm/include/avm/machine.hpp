@@ -32,8 +32,17 @@ struct Assertion {
     bool didInboxInsn;
 };
 
+struct ProfileEntry {
+    uint64_t stepCount = 0;
+    uint64_t gasCount = 0;
+};
+
 class Machine {
     MachineState machine_state;
+    // Cost of each pc run since the profile was last taken, only kept while
+    // profiling is enabled
+    std::vector<ProfileEntry> profile;
+    bool profiling = false;
 
     friend std::ostream& operator<<(std::ostream&, const Machine&);
     BlockReason runOne();
@@ -47,6 +56,13 @@ class Machine {
                   Tuple messages,
                   std::chrono::seconds wallLimit);
 
+    void setProfiling(bool enabled);
+    // Returns the cost of each pc since the last call and resets the counts
+    std::vector<ProfileEntry> takeProfile();
+    const std::vector<CodePoint>& getCode() const {
+        return machine_state.code;
+    }
+
     Status currentStatus() { return machine_state.state; }
     uint256_t hash() const { return machine_state.hash(); }
     BlockReason isBlocked(uint256_t currentTime, bool newMessages) const {
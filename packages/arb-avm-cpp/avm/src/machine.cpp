@@ -57,7 +57,15 @@ Assertion Machine::run(uint64_t stepCount,
     auto start_time = std::chrono::system_clock::now();
     machine_state.context = AssertionContext{timeBounds, std::move(messages)};
     while (machine_state.context.numSteps < stepCount) {
+        auto pc = machine_state.pc;
+        auto startSteps = machine_state.context.numSteps;
+        auto startGas = machine_state.context.numGas;
         auto blockReason = runOne();
+        if (profiling && pc < profile.size()) {
+            profile[pc].stepCount +=
+                machine_state.context.numSteps - startSteps;
+            profile[pc].gasCount += machine_state.context.numGas - startGas;
+        }
         if (!nonstd::get_if<NotBlocked>(&blockReason)) {
             break;
         }
@@ -75,6 +83,20 @@ Assertion Machine::run(uint64_t stepCount,
             machine_state.context.didInboxInsn};
 }
 
+void Machine::setProfiling(bool enabled) {
+    profiling = enabled;
+    profile.clear();
+    if (profiling) {
+        profile.resize(machine_state.code.size());
+    }
+}
+
+std::vector<ProfileEntry> Machine::takeProfile() {
+    std::vector<ProfileEntry> ret(profiling ? machine_state.code.size() : 0);
+    std::swap(ret, profile);
+    return ret;
+}
+
 bool isErrorCodePoint(const CodePoint& cp) {
     return cp.nextHash == 0 && cp.op == Operation{static_cast<OpCode>(0)};
 }
@@ -71,6 +71,8 @@ void* machineClone(CMachine* m) {
     assert(m);
     Machine* mach = static_cast<Machine*>(m);
     Machine* cloneMach = new Machine(*mach);
+    // The clone starts with empty counts so that no run is counted twice
+    cloneMach->takeProfile();
     return static_cast<void*>(cloneMach);
 }
 
@@ -133,6 +135,38 @@ CBlockReason machineIsBlocked(CMachine* m,
     return nonstd::visit(ReasonConverter{}, blockReason);
 }
 
+void machineSetProfiling(CMachine* m, int enabled) {
+    assert(m);
+    static_cast<Machine*>(m)->setProfiling(enabled != 0);
+}
+
+static void append_big_endian_uint64(uint64_t val,
+                                     std::vector<unsigned char>& buf) {
+    uint64_t beVal = boost::endian::native_to_big(val);
+    auto data = reinterpret_cast<const unsigned char*>(&beVal);
+    buf.insert(buf.end(), data, data + sizeof(beVal));
+}
+
+ByteSlice machineTakeProfile(CMachine* m) {
+    assert(m);
+    Machine* mach = static_cast<Machine*>(m);
+    auto profile = mach->takeProfile();
+    const auto& code = mach->getCode();
+    std::vector<unsigned char> buffer;
+    for (uint64_t pc = 0; pc < profile.size(); pc++) {
+        if (profile[pc].stepCount == 0) {
+            continue;
+        }
+        append_big_endian_uint64(pc, buffer);
+        buffer.push_back(static_cast<unsigned char>(code[pc].op.opcode));
+        append_big_endian_uint64(profile[pc].stepCount, buffer);
+        append_big_endian_uint64(profile[pc].gasCount, buffer);
+    }
+    auto profileData = (unsigned char*)malloc(buffer.size());
+    std::copy(buffer.begin(), buffer.end(), profileData);
+    return {profileData, static_cast<int>(buffer.size())};
+}
+
 ByteSlice machineMarshallForProof(CMachine* m) {
     assert(m);
     Machine* mach = static_cast<Machine*>(m);
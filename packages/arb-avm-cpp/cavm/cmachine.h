@@ -76,6 +76,14 @@ RawAssertion machineExecuteAssertion(CMachine* m,
                                      void* inbox,
                                      uint64_t wallLimit);
 
+// Enables or disables counting the steps and gas used at each pc
+void machineSetProfiling(CMachine* m, int enabled);
+
+// Returns the counts since the last call and resets them. For each pc that
+// was run the data contains a big endian uint64 pc, the opcode byte, and big
+// endian uint64 step and gas counts
+ByteSlice machineTakeProfile(CMachine* m);
+
 ByteSlice machineMarshallForProof(CMachine* m);
 
 void machinePrint(CMachine* m);
@@ -75,7 +75,7 @@ func (checkpoint *CheckpointStorage) GetInitialMachine() (machine.Machine, error
 		return nil, fmt.Errorf("error getting initial machine from checkpointstorage")
 	}
 
-	ret := &Machine{cMachine}
+	ret := &Machine{c: cMachine}
 	runtime.SetFinalizer(ret, cdestroyVM)
 	return ret, nil
 }
@@ -87,7 +87,7 @@ func (checkpoint *CheckpointStorage) GetMachine(machineHash common.Hash) (machin
 		return nil, fmt.Errorf("error getting machine from checkpointstorage")
 	}
 
-	ret := &Machine{cMachine}
+	ret := &Machine{c: cMachine}
 	runtime.SetFinalizer(ret, cdestroyVM)
 	return ret, nil
 }
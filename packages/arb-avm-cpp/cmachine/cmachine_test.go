@@ -18,8 +18,14 @@ package cmachine
 
 import (
 	"log"
+	"math/big"
 	"os"
 	"testing"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/machine"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/protocol"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
 )
 
 func TestMachineCreation(t *testing.T) {
@@ -53,3 +59,34 @@ func TestMachineCreation(t *testing.T) {
 		log.Fatal(err)
 	}
 }
+
+func TestMachineProfile(t *testing.T) {
+	mach, err := New("contract.ao")
+	if err != nil {
+		t.Fatal(err)
+	}
+	profile := machine.NewProfile()
+	mach.SetProfile(profile)
+
+	timeBounds := &protocol.TimeBounds{
+		LowerBoundBlock:     common.NewTimeBlocks(big.NewInt(0)),
+		UpperBoundBlock:     common.NewTimeBlocks(big.NewInt(0)),
+		LowerBoundTimestamp: big.NewInt(0),
+		UpperBoundTimestamp: big.NewInt(0),
+	}
+	assertion, steps := mach.ExecuteAssertion(1000, timeBounds, value.NewEmptyTuple(), 0)
+	// Runs on a clone are added to the same profile
+	assertion2, steps2 := mach.Clone().ExecuteAssertion(1000, timeBounds, value.NewEmptyTuple(), 0)
+
+	var profileSteps, profileGas uint64
+	for _, entry := range profile.Entries() {
+		profileSteps += entry.Steps
+		profileGas += entry.Gas
+	}
+	if profileSteps != steps+steps2 {
+		t.Error("profile counted", profileSteps, "steps instead of", steps+steps2)
+	}
+	if profileGas != assertion.NumGas+assertion2.NumGas {
+		t.Error("profile counted", profileGas, "gas instead of", assertion.NumGas+assertion2.NumGas)
+	}
+}
@@ -28,6 +28,7 @@ import "C"
 
 import (
 	"bytes"
+	"encoding/binary"
 	"fmt"
 	"runtime"
 	"time"
@@ -40,7 +41,8 @@ import (
 )
 
 type Machine struct {
-	c unsafe.Pointer
+	c       unsafe.Pointer
+	profile *machine.Profile
 }
 
 func New(codeFile string) (*Machine, error) {
@@ -50,7 +52,7 @@ func New(codeFile string) (*Machine, error) {
 	if cMachine == nil {
 		return nil, fmt.Errorf("error loading machine %v", codeFile)
 	}
-	ret := &Machine{cMachine}
+	ret := &Machine{c: cMachine}
 	runtime.SetFinalizer(ret, cdestroyVM)
 	C.free(unsafe.Pointer(cFilename))
 	return ret, nil
@@ -67,7 +69,7 @@ func (m *Machine) Hash() (ret common.Hash) {
 
 func (m *Machine) Clone() machine.Machine {
 	cMachine := C.machineClone(m.c)
-	ret := &Machine{cMachine}
+	ret := &Machine{c: cMachine, profile: m.profile}
 	runtime.SetFinalizer(ret, cdestroyVM)
 	return ret
 }
@@ -163,6 +165,10 @@ func (m *Machine) ExecuteAssertion(
 	outMessageVals := bytesArrayToVals(outMessagesRaw, int(assertion.outMessageCount))
 	logVals := bytesArrayToVals(logsRaw, int(assertion.logCount))
 
+	if m.profile != nil {
+		m.profile.Add(m.takeProfile())
+	}
+
 	return protocol.NewExecutionAssertion(
 		m.Hash(),
 		int(assertion.didInboxInsn) != 0,
@@ -172,6 +178,37 @@ func (m *Machine) ExecuteAssertion(
 	), uint64(assertion.numSteps)
 }
 
+// SetProfile records the cost of every instruction run by ExecuteAssertion
+// into p, or stops recording if p is nil. Clones of the machine record into
+// the same profile
+func (m *Machine) SetProfile(p *machine.Profile) {
+	m.profile = p
+	enabled := 0
+	if p != nil {
+		enabled = 1
+	}
+	C.machineSetProfiling(m.c, C.int(enabled))
+}
+
+func (m *Machine) takeProfile() []machine.ProfileEntry {
+	rawProfile := C.machineTakeProfile(m.c)
+	data := C.GoBytes(unsafe.Pointer(rawProfile.data), rawProfile.length)
+	C.free(rawProfile.data)
+
+	const entrySize = 8 + 1 + 8 + 8
+	entries := make([]machine.ProfileEntry, 0, len(data)/entrySize)
+	for len(data) >= entrySize {
+		entries = append(entries, machine.ProfileEntry{
+			PC:     int64(binary.BigEndian.Uint64(data[0:8])),
+			Opcode: value.Opcode(data[8]),
+			Steps:  binary.BigEndian.Uint64(data[9:17]),
+			Gas:    binary.BigEndian.Uint64(data[17:25]),
+		})
+		data = data[entrySize:]
+	}
+	return entries
+}
+
 func (m *Machine) MarshalForProof() ([]byte, error) {
 	rawProof := C.machineMarshallForProof(m.c)
 	return C.GoBytes(unsafe.Pointer(rawProof.data), rawProof.length), nil
@@ -145,3 +145,28 @@ TEST_CASE("Restore checkpoint") {
         restoreCheckpoint(storage, machine, results.storage_key);
     }
 }
+
+TEST_CASE("Profile execution") {
+    TuplePool pool;
+    Machine machine;
+    machine.initializeMachine(test_contract_path);
+    machine.setProfiling(true);
+    auto assertion = machine.run(10000, TimeBounds{0, 0, 0, 0}, Tuple(),
+                                 std::chrono::seconds{0});
+
+    auto profile = machine.takeProfile();
+    REQUIRE(profile.size() == machine.getCode().size());
+    uint64_t stepCount = 0;
+    uint64_t gasCount = 0;
+    for (const auto& entry : profile) {
+        stepCount += entry.stepCount;
+        gasCount += entry.gasCount;
+    }
+    REQUIRE(stepCount == assertion.stepCount);
+    REQUIRE(gasCount == assertion.gasCount);
+
+    auto emptyProfile = machine.takeProfile();
+    for (const auto& entry : emptyProfile) {
+        REQUIRE(entry.stepCount == 0);
+    }
+}
@@ -0,0 +1,146 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package asm
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/goloader"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/protocol"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
+)
+
+func roundTrip(t *testing.T, data []byte) string {
+	ao, err := goloader.ReadAO(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var text bytes.Buffer
+	if err := Disassemble(ao, &text); err != nil {
+		t.Fatal(err)
+	}
+	assembled, err := Assemble(bytes.NewReader(text.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	if err := assembled.Write(&out); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, out.Bytes()) {
+		t.Error("assembled file differs from the original")
+	}
+	return text.String()
+}
+
+func TestRoundTripCompiledFiles(t *testing.T) {
+	files, err := filepath.Glob("../../arb-validator/proofmachine/*.ao")
+	if err != nil {
+		t.Fatal(err)
+	}
+	files = append(files, "../../arb-validator/contract.ao")
+	for _, fileName := range files {
+		t.Run(filepath.Base(fileName), func(t *testing.T) {
+			data, err := ioutil.ReadFile(fileName)
+			if err != nil {
+				t.Fatal(err)
+			}
+			roundTrip(t, data)
+		})
+	}
+}
+
+const testProgram = `
+.version 1
+.extension 7 0x0102
+.code
+  nop (1, @4)            ; push a tuple containing a later code point
+  tget 1
+  jump
+0: halt                  ; label doesn't match and is rejected below
+`
+
+func TestAssembleProgram(t *testing.T) {
+	if _, err := Assemble(strings.NewReader(testProgram + ".static 0\n")); err == nil {
+		t.Error("mismatched label was accepted")
+	}
+
+	program := strings.Replace(testProgram, "0: halt", "3: 0x7f\n4: halt", 1) +
+		".static (0x10000000000000000, @0, codepoint(0, 0x00, 0x" + strings.Repeat("00", 32) + "), hashonly(1, 0x" + strings.Repeat("ab", 32) + "))\n"
+	ao, err := Assemble(strings.NewReader(program))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ao.Code) != 5 || len(ao.Extensions) != 1 || ao.Extensions[0].ID != 7 {
+		t.Fatal("assembled wrong program", ao.Code, ao.Extensions)
+	}
+	codePoints := CodePoints(ao.Code)
+	static := ao.Static.(value.TupleValue)
+	if item, _ := static.GetByInt64(1); !value.Eq(item, codePoints[0]) || item.Hash() != codePoints[0].Hash() {
+		t.Error("static reference didn't resolve to the first code point")
+	}
+	if item, _ := static.GetByInt64(2); !value.Eq(item, value.ErrorCodePoint) {
+		t.Error("explicit code point assembled to", item)
+	}
+
+	var buf bytes.Buffer
+	if err := ao.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	text := roundTrip(t, buf.Bytes())
+	if !strings.Contains(text, "0: nop (1, @4)") || !strings.Contains(text, "3: 0x7f") {
+		t.Error("unexpected disassembly\n", text)
+	}
+
+	// The program jumps over the invalid opcode to the halt
+	m, err := goloader.LoadMachine(bytes.NewReader(buf.Bytes()), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tb := &protocol.TimeBounds{
+		LowerBoundBlock:     common.NewTimeBlocks(big.NewInt(0)),
+		UpperBoundBlock:     common.NewTimeBlocks(big.NewInt(10)),
+		LowerBoundTimestamp: big.NewInt(0),
+		UpperBoundTimestamp: big.NewInt(10),
+	}
+	m.ExecuteAssertion(100, tb, value.NewEmptyTuple(), 0)
+	if !m.IsHalted() {
+		t.Error("program didn't halt")
+	}
+}
+
+func TestAssembleErrors(t *testing.T) {
+	programs := map[string]string{
+		"backward reference": ".code\nnop @0\nhalt\n.static 0\n",
+		"unknown mnemonic":   ".code\nfrob\n.static 0\n",
+		"missing static":     ".code\nhalt\n",
+		"large int":          ".code\nnop 0x1" + strings.Repeat("0", 64) + "\n.static 0\n",
+		"large tuple":        ".code\nhalt\n.static (0, 1, 2, 3, 4, 5, 6, 7, 8)\n",
+		"trailing input":     ".code\nhalt 1 2\n.static 0\n",
+	}
+	for name, program := range programs {
+		if _, err := Assemble(strings.NewReader(program)); err == nil {
+			t.Error(name, "was accepted")
+		}
+	}
+}
@@ -0,0 +1,479 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package asm
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/code"
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/goloader"
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/vm"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
+)
+
+var opcodesByName map[string]value.Opcode
+
+func init() {
+	opcodesByName = make(map[string]value.Opcode, len(code.InstructionNames))
+	for op, name := range code.InstructionNames {
+		opcodesByName[name] = op
+	}
+}
+
+var maxInt = new(big.Int).Lsh(big.NewInt(1), 256)
+
+// expr is a parsed value which may contain references to code points that
+// are only known once the code after them has been assembled
+type expr interface {
+	resolve(codePoints []value.CodePointValue, limit int64) (value.Value, error)
+}
+
+type intExpr struct {
+	val *big.Int
+}
+
+type tupleExpr struct {
+	items []expr
+}
+
+type refExpr struct {
+	pc int64
+}
+
+type codePointExpr struct {
+	pc       int64
+	op       opExpr
+	nextHash common.Hash
+}
+
+type hashOnlyExpr struct {
+	size int64
+	hash common.Hash
+}
+
+type opExpr struct {
+	op        value.Opcode
+	immediate expr
+}
+
+func (e intExpr) resolve([]value.CodePointValue, int64) (value.Value, error) {
+	return value.NewIntValue(e.val), nil
+}
+
+func (e tupleExpr) resolve(codePoints []value.CodePointValue, limit int64) (value.Value, error) {
+	items := make([]value.Value, 0, len(e.items))
+	for _, item := range e.items {
+		val, err := item.resolve(codePoints, limit)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, val)
+	}
+	return value.NewTupleFromSlice(items)
+}
+
+// resolve only allows references to code points at or after limit since
+// the code points before it haven't been assembled yet
+func (e refExpr) resolve(codePoints []value.CodePointValue, limit int64) (value.Value, error) {
+	if e.pc < 0 || e.pc >= int64(len(codePoints)) {
+		return nil, fmt.Errorf("reference @%v is outside of the program", e.pc)
+	}
+	if e.pc < limit {
+		return nil, fmt.Errorf("reference @%v must be to an instruction after %v", e.pc, limit-1)
+	}
+	return codePoints[e.pc], nil
+}
+
+func (e codePointExpr) resolve(codePoints []value.CodePointValue, limit int64) (value.Value, error) {
+	op, err := e.op.resolve(codePoints, limit)
+	if err != nil {
+		return nil, err
+	}
+	return value.CodePointValue{InsnNum: e.pc, Op: op, NextHash: e.nextHash}, nil
+}
+
+func (e hashOnlyExpr) resolve([]value.CodePointValue, int64) (value.Value, error) {
+	return value.NewHashOnlyValue(e.hash, e.size), nil
+}
+
+func (e opExpr) resolve(codePoints []value.CodePointValue, limit int64) (value.Operation, error) {
+	if e.immediate == nil {
+		return value.BasicOperation{Op: e.op}, nil
+	}
+	val, err := e.immediate.resolve(codePoints, limit)
+	if err != nil {
+		return nil, err
+	}
+	return value.ImmediateOperation{Op: e.op, Val: val}, nil
+}
+
+// parser reads the tokens of a single line
+type parser struct {
+	line string
+	pos  int
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c == '-' || ('0' <= c && c <= '9') || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.line) && (p.line[p.pos] == ' ' || p.line[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+func (p *parser) atEnd() bool {
+	p.skipSpace()
+	return p.pos == len(p.line)
+}
+
+func (p *parser) peek() byte {
+	p.skipSpace()
+	if p.pos == len(p.line) {
+		return 0
+	}
+	return p.line[p.pos]
+}
+
+func (p *parser) expect(c byte) error {
+	if p.peek() != c {
+		return fmt.Errorf("expected %q at column %v", c, p.pos+1)
+	}
+	p.pos++
+	return nil
+}
+
+func (p *parser) word() (string, error) {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.line) && isWordByte(p.line[p.pos]) {
+		p.pos++
+	}
+	if start == p.pos {
+		return "", fmt.Errorf("unexpected input at column %v", p.pos+1)
+	}
+	return p.line[start:p.pos], nil
+}
+
+func (p *parser) bigInt() (*big.Int, error) {
+	w, err := p.word()
+	if err != nil {
+		return nil, err
+	}
+	val, ok := new(big.Int).SetString(w, 0)
+	if !ok || val.Sign() < 0 || val.Cmp(maxInt) >= 0 {
+		return nil, fmt.Errorf("invalid integer %v", w)
+	}
+	return val, nil
+}
+
+func (p *parser) int64() (int64, error) {
+	w, err := p.word()
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(w, 0, 64)
+}
+
+func (p *parser) hash() (common.Hash, error) {
+	w, err := p.word()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	data, err := hexutil.Decode(w)
+	if err != nil || len(data) != 32 {
+		return common.Hash{}, fmt.Errorf("invalid hash %v", w)
+	}
+	var ret common.Hash
+	copy(ret[:], data)
+	return ret, nil
+}
+
+func parseOpcode(w string) (value.Opcode, error) {
+	if op, ok := opcodesByName[w]; ok {
+		return op, nil
+	}
+	if strings.HasPrefix(w, "0x") {
+		op, err := strconv.ParseUint(w[2:], 16, 8)
+		if err == nil {
+			return value.Opcode(op), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown instruction %v", w)
+}
+
+// operation reads a mnemonic and its immediate if there is one before the
+// end of the line or a terminating comma or parenthesis
+func (p *parser) operation() (opExpr, error) {
+	w, err := p.word()
+	if err != nil {
+		return opExpr{}, err
+	}
+	op, err := parseOpcode(w)
+	if err != nil {
+		return opExpr{}, err
+	}
+	if c := p.peek(); c == 0 || c == ',' || c == ')' {
+		return opExpr{op: op}, nil
+	}
+	immediate, err := p.value()
+	if err != nil {
+		return opExpr{}, err
+	}
+	return opExpr{op: op, immediate: immediate}, nil
+}
+
+func (p *parser) value() (expr, error) {
+	switch p.peek() {
+	case '(':
+		p.pos++
+		var items []expr
+		if p.peek() == ')' {
+			p.pos++
+			return tupleExpr{items}, nil
+		}
+		for {
+			item, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+			if p.peek() == ')' {
+				p.pos++
+				break
+			}
+			if err := p.expect(','); err != nil {
+				return nil, err
+			}
+		}
+		if len(items) > value.MaxTupleSize {
+			return nil, fmt.Errorf("tuple has %v items but the maximum is %v", len(items), value.MaxTupleSize)
+		}
+		return tupleExpr{items}, nil
+	case '@':
+		p.pos++
+		pc, err := p.int64()
+		if err != nil {
+			return nil, err
+		}
+		return refExpr{pc}, nil
+	}
+
+	p.skipSpace()
+	start := p.pos
+	w, err := p.word()
+	if err != nil {
+		return nil, err
+	}
+	switch w {
+	case "codepoint":
+		if err := p.expect('('); err != nil {
+			return nil, err
+		}
+		pc, err := p.int64()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(','); err != nil {
+			return nil, err
+		}
+		op, err := p.operation()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(','); err != nil {
+			return nil, err
+		}
+		nextHash, err := p.hash()
+		if err != nil {
+			return nil, err
+		}
+		return codePointExpr{pc, op, nextHash}, p.expect(')')
+	case "hashonly":
+		if err := p.expect('('); err != nil {
+			return nil, err
+		}
+		size, err := p.int64()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(','); err != nil {
+			return nil, err
+		}
+		hash, err := p.hash()
+		if err != nil {
+			return nil, err
+		}
+		return hashOnlyExpr{size, hash}, p.expect(')')
+	}
+	p.pos = start
+	val, err := p.bigInt()
+	if err != nil {
+		return nil, err
+	}
+	return intExpr{val}, nil
+}
+
+func stripComment(line string) string {
+	if i := strings.IndexByte(line, ';'); i >= 0 {
+		return line[:i]
+	}
+	return line
+}
+
+// instruction parses a line of the code section, which may start with a label
+// giving the instruction's position
+func (p *parser) instruction(pc int64) (opExpr, error) {
+	p.skipSpace()
+	start := p.pos
+	if w, err := p.word(); err == nil && p.peek() == ':' {
+		label, err := strconv.ParseInt(w, 10, 64)
+		if err != nil {
+			return opExpr{}, fmt.Errorf("invalid label %v", w)
+		}
+		if label != pc {
+			return opExpr{}, fmt.Errorf("label %v on instruction %v", label, pc)
+		}
+		p.pos++
+	} else {
+		p.pos = start
+	}
+	return p.operation()
+}
+
+// Assemble reads assembly in the format written by Disassemble
+func Assemble(r io.Reader) (*goloader.AOFile, error) {
+	ao := &goloader.AOFile{
+		Version:    goloader.CurrentAOVersion,
+		Extensions: make([]goloader.RawExtension, 0),
+	}
+	var ops []opExpr
+	var opLines []int
+	var static expr
+	inCode := false
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<30)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		p := &parser{line: stripComment(scanner.Text())}
+		if p.atEnd() {
+			continue
+		}
+		err := func() error {
+			if static != nil {
+				return errors.New("unexpected input after .static")
+			}
+			if p.peek() != '.' {
+				if !inCode {
+					return errors.New("instruction before .code")
+				}
+				op, err := p.instruction(int64(len(ops)))
+				if err != nil {
+					return err
+				}
+				ops = append(ops, op)
+				opLines = append(opLines, lineNum)
+				return nil
+			}
+			p.pos++
+			directive, err := p.word()
+			if err != nil {
+				return err
+			}
+			switch directive {
+			case "version":
+				version, err := p.int64()
+				if err != nil {
+					return err
+				}
+				ao.Version = uint32(version)
+			case "extension":
+				id, err := p.int64()
+				if err != nil {
+					return err
+				}
+				if id <= 0 || id > 0xffffffff {
+					return fmt.Errorf("invalid extension id %v", id)
+				}
+				dataStr, err := p.word()
+				if err != nil {
+					return err
+				}
+				data, err := hexutil.Decode(dataStr)
+				if err != nil {
+					return fmt.Errorf("invalid extension data: %v", err)
+				}
+				ao.Extensions = append(ao.Extensions, goloader.RawExtension{ID: uint32(id), Data: data})
+			case "code":
+				inCode = true
+			case "static":
+				static, err = p.value()
+				if err != nil {
+					return err
+				}
+			default:
+				return fmt.Errorf("unknown directive .%v", directive)
+			}
+			return nil
+		}()
+		if err == nil && !p.atEnd() {
+			err = fmt.Errorf("unexpected input at column %v", p.pos+1)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %v: %v", lineNum, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if static == nil {
+		return nil, errors.New("missing .static")
+	}
+
+	// Each code point's hash depends on the instructions after it, so the
+	// code is assembled from the end
+	ao.Code = make([]value.Operation, len(ops))
+	codePoints := make([]value.CodePointValue, len(ops))
+	nextHash := vm.HashOfLastInstruction
+	for i := len(ops) - 1; i >= 0; i-- {
+		op, err := ops[i].resolve(codePoints, int64(i)+1)
+		if err != nil {
+			return nil, fmt.Errorf("line %v: %v", opLines[i], err)
+		}
+		ao.Code[i] = op
+		codePoints[i] = value.CodePointValue{InsnNum: int64(i), Op: op, NextHash: nextHash}
+		nextHash = codePoints[i].Hash()
+	}
+	staticVal, err := static.resolve(codePoints, 0)
+	if err != nil {
+		return nil, fmt.Errorf("static: %v", err)
+	}
+	ao.Static = staticVal
+	return ao, nil
+}
@@ -0,0 +1,163 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package asm converts AO files to and from a text assembly format.
+//
+// A program is written as
+//
+//	.version 1
+//	.extension <id> <hex data>
+//	.code
+//	0: <mnemonic> [immediate]
+//	...
+//	.static <value>
+//
+// Instruction labels are optional but must match the instruction's position
+// when given, and everything after a ';' is a comment. Values are written as
+// integers in decimal or 0x prefixed hex, tuples as (a, b, c), references to
+// a code point of the program as @<pc>, other code points as
+// codepoint(<pc>, <mnemonic> [immediate], <next hash>) and hash only values as
+// hashonly(<size>, <hash>). An immediate may only reference code points after
+// its own instruction since a code point's hash covers everything after it
+package asm
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/code"
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/goloader"
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/vm"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
+)
+
+// CodePoints returns the code point of every instruction in insns
+func CodePoints(insns []value.Operation) []value.CodePointValue {
+	codePoints := make([]value.CodePointValue, len(insns))
+	nextHash := vm.HashOfLastInstruction
+	for i := len(insns) - 1; i >= 0; i-- {
+		codePoints[i] = value.CodePointValue{InsnNum: int64(i), Op: insns[i], NextHash: nextHash}
+		nextHash = codePoints[i].Hash()
+	}
+	return codePoints
+}
+
+func mnemonic(op value.Opcode) string {
+	name, ok := code.InstructionNames[op]
+	if !ok {
+		return fmt.Sprintf("0x%02x", byte(op))
+	}
+	return name
+}
+
+type disassembler struct {
+	codePoints []value.CodePointValue
+	// limit is the lowest pc which may be written as a reference
+	limit int64
+}
+
+func encodedEqual(a, b value.Value) bool {
+	var bufA, bufB bytes.Buffer
+	if err := goloader.WriteValue(a, &bufA); err != nil {
+		return false
+	}
+	if err := goloader.WriteValue(b, &bufB); err != nil {
+		return false
+	}
+	return bytes.Equal(bufA.Bytes(), bufB.Bytes())
+}
+
+// isReference returns true if cp can be written as @pc, which requires it to
+// be identical to the program's code point at that pc
+func (d *disassembler) isReference(cp value.CodePointValue) bool {
+	if cp.InsnNum < d.limit || cp.InsnNum >= int64(len(d.codePoints)) {
+		return false
+	}
+	return encodedEqual(cp, d.codePoints[cp.InsnNum])
+}
+
+func (d *disassembler) writeOperation(sb *strings.Builder, op value.Operation) {
+	sb.WriteString(mnemonic(op.GetOp()))
+	if immediate, ok := op.(value.ImmediateOperation); ok {
+		sb.WriteString(" ")
+		d.writeValue(sb, immediate.Val)
+	}
+}
+
+var maxDecimal = new(big.Int).Lsh(big.NewInt(1), 64)
+
+func (d *disassembler) writeValue(sb *strings.Builder, val value.Value) {
+	switch val := val.(type) {
+	case value.IntValue:
+		if val.BigInt().Cmp(maxDecimal) < 0 {
+			sb.WriteString(val.BigInt().String())
+		} else {
+			fmt.Fprintf(sb, "%#x", val.BigInt())
+		}
+	case value.TupleValue:
+		sb.WriteString("(")
+		for i, item := range val.Contents() {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			d.writeValue(sb, item)
+		}
+		sb.WriteString(")")
+	case value.CodePointValue:
+		if d.isReference(val) {
+			fmt.Fprintf(sb, "@%v", val.InsnNum)
+			return
+		}
+		fmt.Fprintf(sb, "codepoint(%v, ", val.InsnNum)
+		d.writeOperation(sb, val.Op)
+		fmt.Fprintf(sb, ", %v)", val.NextHash.String())
+	case value.HashOnlyValue:
+		fmt.Fprintf(sb, "hashonly(%v, %v)", val.Size(), val.Hash().String())
+	default:
+		panic(fmt.Sprintf("unknown value type %T", val))
+	}
+}
+
+// Disassemble writes ao as assembly which Assemble turns back into an
+// identical AO file. Each instruction is followed by a comment containing the
+// hash of its code point
+func Disassemble(ao *goloader.AOFile, w io.Writer) error {
+	wr := bufio.NewWriter(w)
+	d := &disassembler{codePoints: CodePoints(ao.Code)}
+
+	fmt.Fprintf(wr, ".version %v\n", ao.Version)
+	for _, ext := range ao.Extensions {
+		fmt.Fprintf(wr, ".extension %v 0x%x\n", ext.ID, ext.Data)
+	}
+	fmt.Fprintln(wr, ".code")
+	for i, op := range ao.Code {
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "%v: ", i)
+		d.limit = int64(i) + 1
+		d.writeOperation(&sb, op)
+		fmt.Fprintf(wr, "%-40v ; %v\n", sb.String(), d.codePoints[i].Hash().String())
+	}
+
+	var sb strings.Builder
+	d.limit = 0
+	d.writeValue(&sb, ao.Static)
+	fmt.Fprintf(wr, ".static %v\n", sb.String())
+	return wr.Flush()
+}
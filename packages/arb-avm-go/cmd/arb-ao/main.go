@@ -0,0 +1,87 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// arb-ao converts compiled AVM programs to and from assembly text
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/asm"
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/goloader"
+)
+
+const usageString = `usage:
+  arb-ao disasm contract.ao [contract.s]
+  arb-ao asm contract.s contract.ao`
+
+func disassemble(inFile string, out io.Writer) error {
+	ao, err := goloader.ReadAOFromFile(inFile)
+	if err != nil {
+		return err
+	}
+	return asm.Disassemble(ao, out)
+}
+
+func assemble(inFile string, outFile string) error {
+	f, err := os.Open(inFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	ao, err := asm.Assemble(f)
+	if err != nil {
+		return err
+	}
+	return ao.WriteToFile(outFile)
+}
+
+func main() {
+	args := os.Args[1:]
+	if len(args) < 2 {
+		fmt.Println(usageString)
+		os.Exit(2)
+	}
+
+	switch {
+	case args[0] == "disasm" && len(args) == 2:
+		if err := disassemble(args[1], os.Stdout); err != nil {
+			log.Fatalln("Failed to disassemble:", err)
+		}
+	case args[0] == "disasm" && len(args) == 3:
+		out, err := os.Create(args[2])
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := disassemble(args[1], out); err != nil {
+			log.Fatalln("Failed to disassemble:", err)
+		}
+		if err := out.Close(); err != nil {
+			log.Fatal(err)
+		}
+	case args[0] == "asm" && len(args) == 3:
+		if err := assemble(args[1], args[2]); err != nil {
+			log.Fatalln("Failed to assemble:", err)
+		}
+	default:
+		fmt.Println(usageString)
+		os.Exit(2)
+	}
+}
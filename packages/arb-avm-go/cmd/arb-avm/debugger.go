@@ -0,0 +1,366 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/code"
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/vm"
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/vm/stack"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/machine"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/protocol"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/evm"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/message"
+)
+
+const debuggerHelp = `Commands:
+  step [n], s [n]      run n instructions, default 1
+  continue, c          run until a breakpoint, the machine blocks or halts
+  break <pc>, b <pc>   stop before running the instruction at pc
+  delete <pc>          remove the breakpoint at pc
+  breakpoints          list breakpoints
+  where, pc            show the next instruction
+  stack                print the data stack, top first
+  auxstack             print the aux stack, top first
+  register             print the register
+  static               print the static value
+  logs                 print the logs produced so far
+  sends                print the messages sent so far
+  state                print the machine status and totals
+  profile [n]          print the gas and steps used by each opcode and the
+                       n most expensive pcs, default 20, if profiling
+  help                 show this message
+  quit, q              exit`
+
+// debugger runs a machine in small pieces, keeping the inbox and the output
+// across pieces
+type debugger struct {
+	m           *vm.Machine
+	chain       common.Address
+	timeBounds  *protocol.TimeBounds
+	inbox       value.TupleValue
+	breakpoints map[int64]bool
+	out         io.Writer
+
+	steps uint64
+	gas   uint64
+	logs  []value.Value
+	sends []value.Value
+
+	// profile is nil unless profiling was requested
+	profile *machine.Profile
+}
+
+func newDebugger(
+	m *vm.Machine,
+	chain common.Address,
+	timeBounds *protocol.TimeBounds,
+	inbox value.TupleValue,
+	out io.Writer,
+) *debugger {
+	return &debugger{
+		m:           m,
+		chain:       chain,
+		timeBounds:  timeBounds,
+		inbox:       inbox,
+		breakpoints: make(map[int64]bool),
+		out:         out,
+	}
+}
+
+func statusName(status machine.Status) string {
+	switch status {
+	case machine.Extensive:
+		return "running"
+	case machine.ErrorStop:
+		return "errored"
+	case machine.Halt:
+		return "halted"
+	default:
+		return fmt.Sprintf("unknown(%v)", int(status))
+	}
+}
+
+func (d *debugger) stopped() bool {
+	return d.m.IsHalted() || d.m.IsErrored()
+}
+
+// run executes up to maxSteps instructions. If stopAtBreakpoints is set it
+// stops before any instruction at a PC breakpoint other than the first. It
+// returns the reason the machine stopped, or nil if it ran maxSteps
+// instructions
+func (d *debugger) run(maxSteps uint64, stopAtBreakpoints bool) machine.BlockReason {
+	if d.stopped() {
+		return d.m.IsBlocked(d.timeBounds.LowerBoundBlock, false)
+	}
+	assCtx := vm.NewMachineAssertionContext(d.m, d.timeBounds, d.inbox)
+	var profile []machine.ProfileEntry
+	if d.profile != nil {
+		profile = make([]machine.ProfileEntry, len(d.m.GetAllOperations()))
+	}
+	var blocked machine.BlockReason
+	var hitBreakpoint bool
+	for assCtx.StepCount() < maxSteps {
+		if d.stopped() {
+			break
+		}
+		if stopAtBreakpoints && assCtx.StepCount() > 0 && d.breakpoints[d.m.GetPC().InsnNum] {
+			hitBreakpoint = true
+			break
+		}
+		pc, op := d.m.GetPC().InsnNum, d.m.GetOperation()
+		steps, gas := assCtx.StepCount(), assCtx.GasCount()
+		_, blocked = vm.RunInstruction(d.m, op)
+		if profile != nil {
+			entry := &profile[pc]
+			entry.PC = pc
+			entry.Opcode = op.GetOp()
+			entry.Steps += assCtx.StepCount() - steps
+			entry.Gas += assCtx.GasCount() - gas
+		}
+		if blocked != nil {
+			break
+		}
+	}
+	if profile != nil {
+		d.profile.Add(profile)
+	}
+	assertion, steps := assCtx.Finalize(d.m)
+	d.steps += steps
+	d.gas += assertion.NumGas
+	if assertion.DidInboxInsn {
+		d.inbox = value.NewEmptyTuple()
+	}
+	for _, logVal := range assertion.Logs {
+		d.printLog(len(d.logs), logVal)
+		d.logs = append(d.logs, logVal)
+	}
+	for _, sendVal := range assertion.OutMsgs {
+		d.printSend(len(d.sends), sendVal)
+		d.sends = append(d.sends, sendVal)
+	}
+	if hitBreakpoint {
+		fmt.Fprintln(d.out, "Hit breakpoint at pc", d.m.GetPC().InsnNum)
+	}
+	if blocked == nil && d.stopped() {
+		blocked = d.m.IsBlocked(d.timeBounds.LowerBoundBlock, false)
+	}
+	return blocked
+}
+
+func (d *debugger) printLog(index int, logVal value.Value) {
+	result, err := evm.ProcessLog(logVal, d.chain)
+	if err != nil {
+		fmt.Fprintf(d.out, "Log %v (undecoded: %v): %v\n", index, err, logVal)
+		return
+	}
+	fmt.Fprintf(d.out, "Log %v: %v\n", index, result)
+}
+
+func (d *debugger) printSend(index int, sendVal value.Value) {
+	decoded, err := func() (message.ExecutionMessage, error) {
+		tup, ok := sendVal.(value.TupleValue)
+		if !ok || tup.Len() == 0 {
+			return nil, errors.New("send must be a non-empty tuple")
+		}
+		typeVal, _ := tup.GetByInt64(0)
+		typeInt, ok := typeVal.(value.IntValue)
+		if !ok {
+			return nil, errors.New("send type must be an int")
+		}
+		return message.UnmarshalExecuted(message.MessageType(typeInt.BigInt().Uint64()), sendVal, d.chain)
+	}()
+	if err != nil {
+		fmt.Fprintf(d.out, "Send %v (undecoded: %v): %v\n", index, err, sendVal)
+		return
+	}
+	fmt.Fprintf(d.out, "Send %v: %v\n", index, decoded)
+}
+
+func (d *debugger) printWhere() {
+	if d.stopped() {
+		fmt.Fprintln(d.out, "Machine has", statusName(d.m.CurrentStatus()))
+		return
+	}
+	codePoint := d.m.GetPC()
+	op := codePoint.Op
+	name, ok := code.InstructionNames[op.GetOp()]
+	if !ok {
+		name = fmt.Sprintf("invalid(%#x)", op.GetOp())
+	}
+	if immediate, ok := op.(value.ImmediateOperation); ok {
+		fmt.Fprintf(d.out, "pc %v: %v %v\n", codePoint.InsnNum, name, immediate.Val)
+	} else {
+		fmt.Fprintf(d.out, "pc %v: %v\n", codePoint.InsnNum, name)
+	}
+}
+
+func (d *debugger) printStack(s stack.Stack) {
+	s = s.Clone()
+	if s.IsEmpty() {
+		fmt.Fprintln(d.out, "(empty)")
+		return
+	}
+	for i := 0; !s.IsEmpty(); i++ {
+		val, _ := s.Pop()
+		fmt.Fprintf(d.out, "%v: %v\n", i, val)
+	}
+}
+
+func (d *debugger) printState() {
+	fmt.Fprintln(d.out, "status:", statusName(d.m.CurrentStatus()))
+	fmt.Fprintln(d.out, "hash:", d.m.Hash())
+	fmt.Fprintln(d.out, "steps:", d.steps)
+	fmt.Fprintln(d.out, "gas:", d.gas)
+	fmt.Fprintln(d.out, "inbox consumed:", value.Eq(d.inbox, value.NewEmptyTuple()))
+	fmt.Fprintln(d.out, "logs:", len(d.logs), "sends:", len(d.sends))
+}
+
+func (d *debugger) reportStop(blocked machine.BlockReason) {
+	switch blocked.(type) {
+	case nil:
+	case machine.BreakpointBlocked:
+		fmt.Fprintln(d.out, "Stopped at BREAKPOINT instruction")
+	default:
+		fmt.Fprintln(d.out, "Machine blocked:", blocked)
+	}
+	d.printWhere()
+}
+
+func parsePC(args []string) (int64, error) {
+	if len(args) != 1 {
+		return 0, errors.New("expected a pc")
+	}
+	return strconv.ParseInt(args[0], 0, 64)
+}
+
+// execute runs a single debugger command. It returns false if the debugger
+// should exit
+func (d *debugger) execute(line string) (bool, error) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return true, nil
+	}
+	cmd, args := fields[0], fields[1:]
+	switch cmd {
+	case "step", "s":
+		count := uint64(1)
+		if len(args) > 0 {
+			var err error
+			count, err = strconv.ParseUint(args[0], 0, 64)
+			if err != nil {
+				return true, err
+			}
+		}
+		d.reportStop(d.run(count, false))
+	case "continue", "c":
+		d.reportStop(d.run(^uint64(0), true))
+	case "break", "b":
+		pc, err := parsePC(args)
+		if err != nil {
+			return true, err
+		}
+		if pc < 0 || pc >= int64(len(d.m.GetAllOperations())) {
+			return true, fmt.Errorf("pc %v is outside of the program", pc)
+		}
+		d.breakpoints[pc] = true
+	case "delete":
+		pc, err := parsePC(args)
+		if err != nil {
+			return true, err
+		}
+		delete(d.breakpoints, pc)
+	case "breakpoints":
+		pcs := make([]int64, 0, len(d.breakpoints))
+		for pc := range d.breakpoints {
+			pcs = append(pcs, pc)
+		}
+		sort.Slice(pcs, func(i, j int) bool { return pcs[i] < pcs[j] })
+		for _, pc := range pcs {
+			fmt.Fprintln(d.out, pc)
+		}
+	case "where", "pc":
+		d.printWhere()
+	case "stack":
+		d.printStack(d.m.Stack())
+	case "auxstack":
+		d.printStack(d.m.AuxStack())
+	case "register":
+		fmt.Fprintln(d.out, d.m.Register().Get())
+	case "static":
+		fmt.Fprintln(d.out, d.m.Static().Get())
+	case "logs":
+		for i, logVal := range d.logs {
+			d.printLog(i, logVal)
+		}
+	case "sends":
+		for i, sendVal := range d.sends {
+			d.printSend(i, sendVal)
+		}
+	case "state":
+		d.printState()
+	case "profile":
+		if d.profile == nil {
+			return true, errors.New("profiling isn't enabled, pass --profile or --pprof")
+		}
+		top := 20
+		if len(args) > 0 {
+			var err error
+			top, err = strconv.Atoi(args[0])
+			if err != nil {
+				return true, err
+			}
+		}
+		return true, d.profile.WriteReport(d.out, code.InstructionNames, top)
+	case "help":
+		fmt.Fprintln(d.out, debuggerHelp)
+	case "quit", "q":
+		return false, nil
+	default:
+		return true, fmt.Errorf("unknown command %q, try help", cmd)
+	}
+	return true, nil
+}
+
+// repl reads commands from in until it is closed or the quit command is given
+func (d *debugger) repl(in io.Reader) error {
+	scanner := bufio.NewScanner(in)
+	d.printWhere()
+	for {
+		fmt.Fprint(d.out, "(arb-avm) ")
+		if !scanner.Scan() {
+			fmt.Fprintln(d.out)
+			return scanner.Err()
+		}
+		cont, err := d.execute(scanner.Text())
+		if err != nil {
+			fmt.Fprintln(d.out, "Error:", err)
+		}
+		if !cont {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,139 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/code"
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/vm"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/protocol"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/message"
+)
+
+// newTestDebugger returns a debugger for a machine which reads its inbox,
+// adds 1 and 2 and halts
+func newTestDebugger(inbox value.TupleValue) (*debugger, *bytes.Buffer) {
+	insns := []value.Operation{
+		value.ImmediateOperation{Op: code.INBOX, Val: value.NewInt64Value(1000)},
+		value.BasicOperation{Op: code.POP},
+		value.ImmediateOperation{Op: code.NOP, Val: value.NewInt64Value(1)},
+		value.ImmediateOperation{Op: code.ADD, Val: value.NewInt64Value(2)},
+		value.BasicOperation{Op: code.POP},
+		value.BasicOperation{Op: code.HALT},
+	}
+	m := vm.NewMachine(insns, value.NewInt64Value(1), false, 100)
+	timeBounds := &protocol.TimeBounds{
+		LowerBoundBlock:     common.NewTimeBlocks(big.NewInt(0)),
+		UpperBoundBlock:     common.NewTimeBlocks(big.NewInt(0)),
+		LowerBoundTimestamp: big.NewInt(0),
+		UpperBoundTimestamp: big.NewInt(0),
+	}
+	var out bytes.Buffer
+	return newDebugger(m, common.Address{}, timeBounds, inbox, &out), &out
+}
+
+func runCommand(t *testing.T, d *debugger, out *bytes.Buffer, line string) string {
+	t.Helper()
+	out.Reset()
+	cont, err := d.execute(line)
+	if err != nil {
+		t.Fatal(line, "failed:", err)
+	}
+	if !cont {
+		t.Fatal(line, "exited the debugger")
+	}
+	return out.String()
+}
+
+func testInbox() value.TupleValue {
+	return buildInbox([]message.Message{message.DeliveredEth{
+		Eth:        message.Eth{Value: big.NewInt(1)},
+		BlockNum:   common.NewTimeBlocks(big.NewInt(0)),
+		Timestamp:  big.NewInt(0),
+		MessageNum: big.NewInt(0),
+	}})
+}
+
+func TestDebuggerBreakpoints(t *testing.T) {
+	d, out := newTestDebugger(testInbox())
+	runCommand(t, d, out, "break 3")
+	runCommand(t, d, out, "b 4")
+	runCommand(t, d, out, "delete 4")
+	if listed := runCommand(t, d, out, "breakpoints"); listed != "3\n" {
+		t.Errorf("listed breakpoints %q", listed)
+	}
+	if _, err := d.execute("break 6"); err == nil {
+		t.Error("set breakpoint outside of the program")
+	}
+
+	output := runCommand(t, d, out, "continue")
+	if !strings.Contains(output, "Hit breakpoint at pc 3") {
+		t.Errorf("continue printed %q", output)
+	}
+	if pc := d.m.GetPC().InsnNum; pc != 3 {
+		t.Error("stopped at pc", pc, "instead of 3")
+	}
+	if !value.Eq(d.inbox, value.NewEmptyTuple()) {
+		t.Error("inbox wasn't consumed by the inbox instruction")
+	}
+
+	// Continuing from a breakpoint runs past it
+	output = runCommand(t, d, out, "c")
+	if !strings.Contains(output, "Machine has halted") {
+		t.Errorf("continue printed %q", output)
+	}
+	if !d.m.IsHalted() {
+		t.Error("machine didn't halt")
+	}
+}
+
+func TestDebuggerStep(t *testing.T) {
+	d, out := newTestDebugger(testInbox())
+	if output := runCommand(t, d, out, "step 3"); !strings.Contains(output, "pc 3: add 2") {
+		t.Errorf("step printed %q", output)
+	}
+	if d.steps != 3 {
+		t.Error("ran", d.steps, "steps instead of 3")
+	}
+	runCommand(t, d, out, "s")
+	if stack := runCommand(t, d, out, "stack"); stack != "0: 3\n" {
+		t.Errorf("stack was %q after add", stack)
+	}
+	if _, err := d.execute("step many"); err == nil {
+		t.Error("stepped with invalid count")
+	}
+	if cont, _ := d.execute("quit"); cont {
+		t.Error("quit didn't exit")
+	}
+}
+
+func TestDebuggerBlocksOnEmptyInbox(t *testing.T) {
+	d, out := newTestDebugger(value.NewEmptyTuple())
+	output := runCommand(t, d, out, "continue")
+	if !strings.Contains(output, "Machine blocked") {
+		t.Errorf("continue printed %q", output)
+	}
+	if pc := d.m.GetPC().InsnNum; pc != 0 {
+		t.Error("machine ran past inbox instruction to pc", pc)
+	}
+}
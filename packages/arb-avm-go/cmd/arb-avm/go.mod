@@ -0,0 +1,16 @@
+module github.com/offchainlabs/arbitrum/packages/arb-avm-go/cmd/arb-avm
+
+go 1.12
+
+require (
+	github.com/ethereum/go-ethereum v1.9.13
+	github.com/offchainlabs/arbitrum/packages/arb-avm-go v0.5.0
+	github.com/offchainlabs/arbitrum/packages/arb-util v0.5.0
+	github.com/offchainlabs/arbitrum/packages/arb-validator-core v0.5.0
+)
+
+replace github.com/offchainlabs/arbitrum/packages/arb-avm-go => ../..
+
+replace github.com/offchainlabs/arbitrum/packages/arb-util => ../../../arb-util
+
+replace github.com/offchainlabs/arbitrum/packages/arb-validator-core => ../../../arb-validator-core
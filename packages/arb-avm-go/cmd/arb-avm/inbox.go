@@ -0,0 +1,172 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/message"
+)
+
+// inboxMessage is the JSON form of a message in an inbox file. Type is one
+// of call, transaction, eth, erc20, erc721 or contractTransaction and
+// selects which of the other fields are used
+type inboxMessage struct {
+	Type         string            `json:"type"`
+	Chain        ethcommon.Address `json:"chain"`
+	To           ethcommon.Address `json:"to"`
+	From         ethcommon.Address `json:"from"`
+	TokenAddress ethcommon.Address `json:"tokenAddress"`
+	SequenceNum  *big.Int          `json:"sequenceNum"`
+	Value        *big.Int          `json:"value"`
+	Id           *big.Int          `json:"id"`
+	Data         hexutil.Bytes     `json:"data"`
+	BlockNum     *big.Int          `json:"blockNum"`
+	Timestamp    *big.Int          `json:"timestamp"`
+	MessageNum   *big.Int          `json:"messageNum"`
+}
+
+func bigOrZero(val *big.Int) *big.Int {
+	if val == nil {
+		return big.NewInt(0)
+	}
+	return val
+}
+
+func (m inboxMessage) toMessage(chain common.Address) (message.Message, error) {
+	if (m.Chain != ethcommon.Address{}) {
+		chain = common.NewAddressFromEth(m.Chain)
+	}
+	to := common.NewAddressFromEth(m.To)
+	from := common.NewAddressFromEth(m.From)
+	blockNum := common.NewTimeBlocks(bigOrZero(m.BlockNum))
+	timestamp := bigOrZero(m.Timestamp)
+	messageNum := bigOrZero(m.MessageNum)
+
+	switch m.Type {
+	case "call":
+		return message.Call{
+			To:        to,
+			From:      from,
+			Data:      m.Data,
+			BlockNum:  blockNum,
+			Timestamp: timestamp,
+		}, nil
+	case "transaction":
+		return message.DeliveredTransaction{
+			Transaction: message.Transaction{
+				Chain:       chain,
+				To:          to,
+				From:        from,
+				SequenceNum: bigOrZero(m.SequenceNum),
+				Value:       bigOrZero(m.Value),
+				Data:        m.Data,
+			},
+			BlockNum:  blockNum,
+			Timestamp: timestamp,
+		}, nil
+	case "eth":
+		return message.DeliveredEth{
+			Eth: message.Eth{
+				To:    to,
+				From:  from,
+				Value: bigOrZero(m.Value),
+			},
+			BlockNum:   blockNum,
+			Timestamp:  timestamp,
+			MessageNum: messageNum,
+		}, nil
+	case "erc20":
+		return message.DeliveredERC20{
+			ERC20: message.ERC20{
+				To:           to,
+				From:         from,
+				TokenAddress: common.NewAddressFromEth(m.TokenAddress),
+				Value:        bigOrZero(m.Value),
+			},
+			BlockNum:   blockNum,
+			Timestamp:  timestamp,
+			MessageNum: messageNum,
+		}, nil
+	case "erc721":
+		return message.DeliveredERC721{
+			ERC721: message.ERC721{
+				To:           to,
+				From:         from,
+				TokenAddress: common.NewAddressFromEth(m.TokenAddress),
+				Id:           bigOrZero(m.Id),
+			},
+			BlockNum:   blockNum,
+			Timestamp:  timestamp,
+			MessageNum: messageNum,
+		}, nil
+	case "contractTransaction":
+		return message.DeliveredContractTransaction{
+			ContractTransaction: message.ContractTransaction{
+				To:    to,
+				From:  from,
+				Value: bigOrZero(m.Value),
+				Data:  m.Data,
+			},
+			BlockNum:   blockNum,
+			Timestamp:  timestamp,
+			MessageNum: messageNum,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown message type %q", m.Type)
+	}
+}
+
+// buildInbox returns the inbox value containing msgs in order
+func buildInbox(msgs []message.Message) value.TupleValue {
+	inbox := value.NewEmptyTuple()
+	for _, msg := range msgs {
+		inbox = message.AddToPrev(inbox, msg)
+	}
+	return inbox
+}
+
+// loadInboxFile reads a JSON array of messages from fileName
+func loadInboxFile(fileName string, chain common.Address) ([]message.Message, error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var jsonMsgs []inboxMessage
+	if err := json.NewDecoder(f).Decode(&jsonMsgs); err != nil {
+		return nil, err
+	}
+	msgs := make([]message.Message, 0, len(jsonMsgs))
+	for i, jsonMsg := range jsonMsgs {
+		msg, err := jsonMsg.toMessage(chain)
+		if err != nil {
+			return nil, fmt.Errorf("message %v: %v", i, err)
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, nil
+}
@@ -0,0 +1,103 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/message"
+)
+
+func writeInboxFile(t *testing.T, contents string) string {
+	dir, err := ioutil.TempDir("", "arb-avm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	fileName := filepath.Join(dir, "inbox.json")
+	if err := ioutil.WriteFile(fileName, []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return fileName
+}
+
+func TestLoadInboxFile(t *testing.T) {
+	chain := common.Address{9}
+	fileName := writeInboxFile(t, `[
+		{"type": "eth", "to": "0x0000000000000000000000000000000000000001", "value": 5, "messageNum": 1},
+		{"type": "transaction", "chain": "0x0000000000000000000000000000000000000002", "data": "0x1234"},
+		{"type": "call", "from": "0x0000000000000000000000000000000000000003", "blockNum": 7}
+	]`)
+	msgs, err := loadInboxFile(fileName, chain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 3 {
+		t.Fatal("loaded", len(msgs), "messages instead of 3")
+	}
+
+	eth, ok := msgs[0].(message.DeliveredEth)
+	if !ok {
+		t.Fatalf("first message was %T", msgs[0])
+	}
+	if eth.To != (common.Address{19: 1}) || eth.Value.Int64() != 5 || eth.MessageNum.Int64() != 1 {
+		t.Error("eth message loaded as", eth)
+	}
+	if eth.Timestamp.Sign() != 0 || eth.BlockNum.AsInt().Sign() != 0 {
+		t.Error("missing fields weren't zero", eth)
+	}
+
+	tx, ok := msgs[1].(message.DeliveredTransaction)
+	if !ok {
+		t.Fatalf("second message was %T", msgs[1])
+	}
+	if tx.Chain != (common.Address{19: 2}) || string(tx.Data) != "\x12\x34" {
+		t.Error("transaction loaded as", tx)
+	}
+
+	call, ok := msgs[2].(message.Call)
+	if !ok {
+		t.Fatalf("third message was %T", msgs[2])
+	}
+	if call.From != (common.Address{19: 3}) || call.BlockNum.AsInt().Int64() != 7 {
+		t.Error("call loaded as", call)
+	}
+
+	inbox := buildInbox(msgs)
+	if value.Eq(inbox, value.NewEmptyTuple()) {
+		t.Error("inbox built from messages was empty")
+	}
+}
+
+func TestLoadInboxFileErrors(t *testing.T) {
+	fileName := writeInboxFile(t, `[{"type": "eth"}, {"type": "bogus"}]`)
+	_, err := loadInboxFile(fileName, common.Address{})
+	if err == nil || !strings.Contains(err.Error(), "message 1") {
+		t.Error("expected error for message 1 but got", err)
+	}
+
+	fileName = writeInboxFile(t, `{"type": "eth"}`)
+	if _, err := loadInboxFile(fileName, common.Address{}); err == nil {
+		t.Error("loaded inbox that isn't an array")
+	}
+}
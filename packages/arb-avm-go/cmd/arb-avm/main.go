@@ -0,0 +1,144 @@
+/*
+ * Copyright 2019, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// arb-avm runs a compiled AVM program on the Go AVM, either to completion or
+// under an interactive debugger, so that contracts can be debugged without
+// running a rollup. It is a separate module since it uses arb-validator-core
+// to build inbox messages and decode logs, which the AVM itself doesn't need
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/code"
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/goloader"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/machine"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/protocol"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/message"
+)
+
+const usageString = "usage: arb-avm [flags] contract.ao"
+
+func main() {
+	fs := flag.NewFlagSet("arb-avm", flag.ExitOnError)
+	inboxFile := fs.String("inbox", "", "inbox=JSONFile")
+	callTo := fs.String("call-to", "", "call-to=Address")
+	callFrom := fs.String("call-from", "", "call-from=Address")
+	callData := fs.String("call-data", "", "call-data=HexData")
+	chainAddress := fs.String("chain", "", "chain=Address")
+	blockNum := fs.Int64("block", 0, "block=BlockNum")
+	timestamp := fs.Int64("timestamp", time.Now().Unix(), "timestamp=Seconds")
+	warn := fs.Bool("warn", false, "warn")
+	run := fs.Bool("run", false, "run")
+	maxSteps := fs.Uint64("max-steps", 100000000, "max-steps=NumSteps")
+	profileFile := fs.String("profile", "", "profile=ReportFile")
+	pprofFile := fs.String("pprof", "", "pprof=ProfileFile")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), usageString)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(os.Args[1:]); err != nil {
+		log.Fatal(err)
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	chain := common.HexToAddress(*chainAddress)
+
+	var msgs []message.Message
+	if *inboxFile != "" {
+		fileMsgs, err := loadInboxFile(*inboxFile, chain)
+		if err != nil {
+			log.Fatalln("Failed to load inbox:", err)
+		}
+		msgs = append(msgs, fileMsgs...)
+	}
+	if *callTo != "" {
+		data, err := hexutil.Decode(*callData)
+		if err != nil && *callData != "" {
+			log.Fatalln("Invalid call data:", err)
+		}
+		msgs = append(msgs, message.Call{
+			To:        common.NewAddressFromEth(ethcommon.HexToAddress(*callTo)),
+			From:      common.NewAddressFromEth(ethcommon.HexToAddress(*callFrom)),
+			Data:      data,
+			BlockNum:  common.NewTimeBlocks(big.NewInt(*blockNum)),
+			Timestamp: big.NewInt(*timestamp),
+		})
+	}
+
+	m, err := goloader.LoadMachineFromFile(fs.Arg(0), *warn)
+	if err != nil {
+		log.Fatalln("Failed to load machine:", err)
+	}
+
+	timeBounds := &protocol.TimeBounds{
+		LowerBoundBlock:     common.NewTimeBlocks(big.NewInt(*blockNum)),
+		UpperBoundBlock:     common.NewTimeBlocks(big.NewInt(*blockNum)),
+		LowerBoundTimestamp: big.NewInt(*timestamp),
+		UpperBoundTimestamp: big.NewInt(*timestamp),
+	}
+	d := newDebugger(m, chain, timeBounds, buildInbox(msgs), os.Stdout)
+	if *profileFile != "" || *pprofFile != "" {
+		d.profile = machine.NewProfile()
+	}
+
+	if *run {
+		d.reportStop(d.run(*maxSteps, false))
+		d.printState()
+	} else if err := d.repl(os.Stdin); err != nil {
+		log.Fatal(err)
+	}
+
+	if *profileFile != "" {
+		if err := writeProfile(*profileFile, func(f *os.File) error {
+			return d.profile.WriteReport(f, code.InstructionNames, 0)
+		}); err != nil {
+			log.Fatalln("Failed to write profile:", err)
+		}
+	}
+	if *pprofFile != "" {
+		if err := writeProfile(*pprofFile, func(f *os.File) error {
+			return d.profile.WritePprof(f, code.InstructionNames, filepath.Base(fs.Arg(0)))
+		}); err != nil {
+			log.Fatalln("Failed to write profile:", err)
+		}
+	}
+}
+
+func writeProfile(fileName string, write func(f *os.File) error) error {
+	f, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
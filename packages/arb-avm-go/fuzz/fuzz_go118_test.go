@@ -0,0 +1,58 @@
+//go:build go1.18
+// +build go1.18
+
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fuzz
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/goloader"
+)
+
+// The fuzz targets need testing.F, which was added in go 1.18. The other
+// tests in this package still run with older toolchains
+
+func FuzzGoEngine(f *testing.F) {
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		c := Generate(data).Build()
+		if !bytes.Equal(aoBytes(t, c.AO), aoBytes(t, Generate(data).Build().AO)) {
+			t.Fatal("generating the same input twice gave different programs")
+		}
+		ao, err := goloader.ReadAO(bytes.NewReader(aoBytes(t, c.AO)))
+		if err != nil {
+			t.Fatal("generated program can't be loaded:", err)
+		}
+		if !bytes.Equal(aoBytes(t, c.AO), aoBytes(t, ao)) {
+			t.Fatal("generated program changed when loaded")
+		}
+		cfg := DefaultConfig()
+		cfg.MaxSteps = 200
+		d, err := Run([]Engine{GoEngine{}, GoEngine{}}, c, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d != nil {
+			t.Fatal("go machine isn't deterministic:", d)
+		}
+	})
+}
@@ -0,0 +1,149 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fuzz
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/code"
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/goloader"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/machine"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/protocol"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
+)
+
+var seeds = [][]byte{
+	{},
+	[]byte("arbitrum"),
+	{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20},
+	bytes.Repeat([]byte{0x37, 0xc1, 0x05}, 40),
+}
+
+func aoBytes(t *testing.T, ao *goloader.AOFile) []byte {
+	var buf bytes.Buffer
+	if err := ao.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// logGasEngine is a go machine with a bug which charges an extra gas for
+// every step that logs
+type logGasEngine struct{}
+
+func (logGasEngine) Name() string {
+	return "buggy"
+}
+
+func (logGasEngine) NewMachine(c *Case) (machine.Machine, error) {
+	m, err := GoEngine{}.NewMachine(c)
+	return &logGasMachine{m}, err
+}
+
+type logGasMachine struct {
+	machine.Machine
+}
+
+func (m *logGasMachine) ExecuteAssertion(
+	maxSteps uint64,
+	timeBounds *protocol.TimeBounds,
+	inbox value.TupleValue,
+	maxWallTime time.Duration,
+) (*protocol.ExecutionAssertion, uint64) {
+	a, steps := m.Machine.ExecuteAssertion(maxSteps, timeBounds, inbox, maxWallTime)
+	a.NumGas += uint64(len(a.Logs))
+	return a, steps
+}
+
+func TestCheckMinimizesDivergence(t *testing.T) {
+	p := Generate(nil)
+	msg := intValue(7)
+	p.Inbox = []Value{{Kind: KindTuple, Tuple: []Value{msg, msg}}}
+	p.Static = Value{Kind: KindTuple, Tuple: []Value{msg, {Kind: KindCodePoint, Target: 3}}}
+	one, two, big := intValue(1), intValue(2), intValue(1<<40)
+	p.Code = []Insn{
+		{Op: code.NOP, Immediate: &one},
+		{Op: code.ADD, Immediate: &two},
+		{Op: code.NOP, Immediate: &big},
+		{Op: code.POP},
+		{Op: code.LOG},
+		{Op: code.NOP},
+		{Op: code.HALT},
+	}
+
+	engines := []Engine{GoEngine{}, logGasEngine{}}
+	cfg := DefaultConfig()
+	minimized, d, err := Check(engines, p, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d == nil || d.Field != "gas" {
+		t.Fatal("expected a gas divergence but got", d)
+	}
+	// The final HALT is kept
+	if len(minimized.Code) != 3 || minimized.Code[1].Op != code.LOG || minimized.Code[2].Op != code.HALT || len(minimized.Inbox) != 0 {
+		t.Error("program wasn't minimized", minimized.Code, minimized.Inbox)
+	}
+	if minimized.Static.Kind != KindInt || minimized.Static.Int.Sign() != 0 {
+		t.Error("static wasn't simplified", minimized.Static)
+	}
+
+	if _, d, err := Check([]Engine{GoEngine{}, GoEngine{}}, p, cfg); err != nil || d != nil {
+		t.Error("identical engines diverged", d, err)
+	}
+}
+
+func TestReproRoundTrip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "repro")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	c := Generate(seeds[3]).Build()
+	d := &Divergence{Field: "gas", Engines: []string{"a", "b"}, Values: []string{"1", "2"}}
+	if err := WriteRepro(dir, "case", c, d); err != nil {
+		t.Fatal(err)
+	}
+	names, err := ReproNames(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 1 || names[0] != "case" {
+		t.Fatal("wrong repro names", names)
+	}
+	read, err := ReadRepro(dir, "case")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(aoBytes(t, c.AO), aoBytes(t, read.AO)) {
+		t.Error("repro program changed")
+	}
+	if read.Inbox.Hash() != c.Inbox.Hash() {
+		t.Error("repro inbox changed")
+	}
+	if !read.TimeBounds.Equals(c.TimeBounds) {
+		t.Error("repro time bounds changed")
+	}
+	if _, err := os.Stat(dir + "/case.s"); err != nil {
+		t.Error("repro wasn't disassembled", err)
+	}
+}
@@ -0,0 +1,211 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fuzz
+
+import (
+	"math/big"
+	"sort"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/code"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/protocol"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
+)
+
+const (
+	maxInsns        = 64
+	maxMessages     = 4
+	maxValueDepth   = 2
+	maxJumpDistance = 8
+)
+
+// opcodes is every opcode which may be generated, in a fixed order so that
+// the same input always gives the same program. debug is left out since it
+// only prints the machine
+var opcodes []value.Opcode
+
+func init() {
+	for op := range code.InstructionNames {
+		if op != code.DEBUG {
+			opcodes = append(opcodes, op)
+		}
+	}
+	sort.Slice(opcodes, func(i, j int) bool { return opcodes[i] < opcodes[j] })
+}
+
+var interestingInts = []*big.Int{
+	big.NewInt(0),
+	big.NewInt(1),
+	big.NewInt(2),
+	big.NewInt(31),
+	big.NewInt(32),
+	big.NewInt(255),
+	new(big.Int).Lsh(big.NewInt(1), 255),
+	new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(1)),
+	new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1)),
+}
+
+// source turns the fuzzer's input into choices. Once the input runs out
+// every choice is 0, so short inputs give short programs
+type source struct {
+	data []byte
+}
+
+func (s *source) done() bool {
+	return len(s.data) == 0
+}
+
+func (s *source) byte() byte {
+	if len(s.data) == 0 {
+		return 0
+	}
+	b := s.data[0]
+	s.data = s.data[1:]
+	return b
+}
+
+func (s *source) intn(n int) int {
+	return int(s.byte()) % n
+}
+
+type generator struct {
+	s *source
+}
+
+// Generate deterministically turns arbitrary bytes into a program. Most
+// instructions are preceded by pushes of operands of the types they expect,
+// and every code point refers to a later instruction, so most programs run
+// for a while before halting or erroring
+func Generate(data []byte) *Program {
+	g := &generator{&source{data}}
+	p := &Program{TimeBounds: g.timeBounds()}
+	for len(p.Code) < maxInsns && !g.s.done() {
+		p.Code = append(p.Code, g.instruction(len(p.Code))...)
+	}
+	// The go machine doesn't handle running past the end of the code, which
+	// compiled programs never do
+	p.Code = append(p.Code, Insn{Op: code.HALT})
+	p.Static = g.value(maxValueDepth, 0)
+	messageCount := g.s.intn(maxMessages + 1)
+	for i := 0; i < messageCount; i++ {
+		p.Inbox = append(p.Inbox, g.value(maxValueDepth, 0))
+	}
+	return p
+}
+
+func (g *generator) timeBounds() *protocol.TimeBounds {
+	lowerBlock := int64(g.s.intn(4))
+	lowerTime := int64(g.s.intn(4))
+	return &protocol.TimeBounds{
+		LowerBoundBlock:     common.NewTimeBlocks(big.NewInt(lowerBlock)),
+		UpperBoundBlock:     common.NewTimeBlocks(big.NewInt(lowerBlock + int64(g.s.intn(4)))),
+		LowerBoundTimestamp: big.NewInt(lowerTime),
+		UpperBoundTimestamp: big.NewInt(lowerTime + int64(g.s.intn(4))),
+	}
+}
+
+func (g *generator) int() Value {
+	if g.s.intn(4) != 0 {
+		return Value{Kind: KindInt, Int: interestingInts[g.s.intn(len(interestingInts))]}
+	}
+	data := make([]byte, g.s.intn(33))
+	for i := range data {
+		data[i] = g.s.byte()
+	}
+	return Value{Kind: KindInt, Int: new(big.Int).SetBytes(data)}
+}
+
+func (g *generator) tuple(depth int, pc int) Value {
+	items := make([]Value, g.s.intn(value.MaxTupleSize+1))
+	for i := range items {
+		items[i] = g.value(depth-1, pc)
+	}
+	return Value{Kind: KindTuple, Tuple: items}
+}
+
+// codePoint returns a reference to an instruction after pc. Targets past the
+// end of the program are built as 0
+func (g *generator) codePoint(pc int) Value {
+	return Value{Kind: KindCodePoint, Target: pc + 1 + g.s.intn(maxJumpDistance)}
+}
+
+func (g *generator) value(depth int, pc int) Value {
+	switch g.s.intn(4) {
+	case 1:
+		if depth > 0 {
+			return g.tuple(depth, pc)
+		}
+	case 2:
+		return g.codePoint(pc)
+	}
+	return g.int()
+}
+
+// operand returns a value of the type expected by the i'th operand of op
+// which is at pc
+func (g *generator) operand(op value.Opcode, i int, pc int) Value {
+	switch {
+	case (op == code.JUMP || op == code.CJUMP || op == code.ERRSET) && i == 0:
+		return g.codePoint(pc)
+	case op == code.CJUMP:
+		return intValue(int64(g.s.intn(2)))
+	case (op == code.TGET || op == code.TSET) && i == 0:
+		return intValue(int64(g.s.intn(value.MaxTupleSize + 1)))
+	case op == code.TLEN || ((op == code.TGET || op == code.TSET) && i == 1):
+		return g.tuple(maxValueDepth, pc)
+	case op == code.INBOX:
+		return intValue(int64(g.s.intn(4)))
+	case code.InstructionStackPops[op][i] == 1:
+		return g.int()
+	default:
+		return g.value(maxValueDepth, pc)
+	}
+}
+
+// instruction generates an instruction to go at pc, usually along with the
+// pushes of its operands
+func (g *generator) instruction(pc int) []Insn {
+	op := opcodes[g.s.intn(len(opcodes))]
+	pops := len(code.InstructionStackPops[op])
+	var insns []Insn
+	if op == code.AUXPOP && g.s.intn(4) != 0 {
+		val := g.value(maxValueDepth, pc)
+		insns = append(insns, Insn{Op: code.AUXPUSH, Immediate: &val})
+	}
+	switch g.s.intn(4) {
+	case 0:
+		// Use whatever is on the stack
+		return append(insns, Insn{Op: op})
+	case 1:
+		if pops > 0 {
+			// Only give the first operand as an immediate
+			val := g.value(maxValueDepth, pc+len(insns))
+			return append(insns, Insn{Op: op, Immediate: &val})
+		}
+	}
+	// Push the operands other than the first, last operand first, then
+	// give the first as an immediate
+	for i := pops - 1; i > 0; i-- {
+		val := g.operand(op, i, pc+len(insns))
+		insns = append(insns, Insn{Op: code.NOP, Immediate: &val})
+	}
+	if pops == 0 {
+		return append(insns, Insn{Op: op})
+	}
+	val := g.operand(op, 0, pc+len(insns))
+	return append(insns, Insn{Op: op, Immediate: &val})
+}
@@ -0,0 +1,192 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fuzz
+
+import (
+	"math/big"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/code"
+)
+
+// Minimize returns the smallest program it can find for which fails still
+// returns true, by removing instructions, inbox messages and immediates and
+// by simplifying values. fails must return true for p
+func Minimize(p *Program, fails func(p *Program) bool) *Program {
+	m := &minimizer{best: p.clone(), fails: fails}
+	for changed := true; changed; {
+		changed = m.removeInsns()
+		changed = m.removeMessages() || changed
+		changed = m.simplifyInsns() || changed
+		changed = m.simplifyValues() || changed
+	}
+	return m.best
+}
+
+type minimizer struct {
+	best  *Program
+	fails func(p *Program) bool
+}
+
+func (m *minimizer) try(p *Program) bool {
+	if !m.fails(p) {
+		return false
+	}
+	m.best = p
+	return true
+}
+
+// changeableInsns returns the number of instructions at the start of the
+// program which may be removed or simplified. The final HALT is always kept,
+// since the go machine doesn't handle running past the end of the code
+func (m *minimizer) changeableInsns() int {
+	count := len(m.best.Code)
+	if count > 0 && m.best.Code[count-1].Op == code.HALT {
+		count--
+	}
+	return count
+}
+
+// removeInsns removes runs of instructions, starting with half of the program
+// and going down to single instructions
+func (m *minimizer) removeInsns() bool {
+	changed := false
+	for size := m.changeableInsns() / 2; size > 0; size /= 2 {
+		for start := 0; start+size <= m.changeableInsns(); {
+			if m.try(m.best.withoutInsns(start, size)) {
+				changed = true
+			} else {
+				start += size
+			}
+		}
+	}
+	return changed
+}
+
+func (m *minimizer) removeMessages() bool {
+	changed := false
+	for i := 0; i < len(m.best.Inbox); {
+		p := m.best.clone()
+		p.Inbox = append(p.Inbox[:i], p.Inbox[i+1:]...)
+		if m.try(p) {
+			changed = true
+		} else {
+			i++
+		}
+	}
+	return changed
+}
+
+// simplifyInsns removes immediates and replaces instructions with nops
+func (m *minimizer) simplifyInsns() bool {
+	changed := false
+	for i := 0; i < m.changeableInsns(); i++ {
+		if m.best.Code[i].Immediate != nil {
+			p := m.best.clone()
+			p.Code[i].Immediate = nil
+			changed = m.try(p) || changed
+		}
+		if m.best.Code[i].Op != code.NOP {
+			p := m.best.clone()
+			p.Code[i].Op = code.NOP
+			changed = m.try(p) || changed
+		}
+	}
+	return changed
+}
+
+func (m *minimizer) simplifyValues() bool {
+	changed := false
+	for i := 0; ; i++ {
+		// Values are found by index since each try replaces the program
+		var original *Value
+		count := 0
+		m.best.values(func(v *Value) {
+			if count == i {
+				original = v
+			}
+			count++
+		})
+		if original == nil {
+			return changed
+		}
+		for _, simpler := range simplerValues(*original) {
+			p := m.best.clone()
+			count = 0
+			p.values(func(v *Value) {
+				if count == i {
+					*v = simpler
+				}
+				count++
+			})
+			if m.try(p) {
+				changed = true
+				break
+			}
+		}
+	}
+}
+
+// simplerValues returns values which are simpler than v, simplest first
+func simplerValues(v Value) []Value {
+	zero := intValue(0)
+	switch v.Kind {
+	case KindTuple:
+		var ret []Value
+		if len(v.Tuple) > 0 {
+			ret = append(ret, Value{Kind: KindTuple})
+		}
+		ret = append(ret, zero)
+		for i, item := range v.Tuple {
+			ret = append(ret, item)
+			without := v.clone()
+			without.Tuple = append(without.Tuple[:i], without.Tuple[i+1:]...)
+			ret = append(ret, without)
+			for _, simplerItem := range simplerValues(item) {
+				replaced := v.clone()
+				replaced.Tuple[i] = simplerItem
+				ret = append(ret, replaced)
+			}
+		}
+		return ret
+	case KindCodePoint:
+		return []Value{zero}
+	default:
+		if v.Int == nil || v.Int.Sign() == 0 {
+			return nil
+		}
+		ret := []Value{zero}
+		if v.Int.BitLen() > 8 {
+			ret = append(ret, Value{Kind: KindInt, Int: new(big.Int).Rsh(v.Int, uint(v.Int.BitLen()/2))})
+		}
+		return ret
+	}
+}
+
+// Check runs p on the engines and, if they diverge, returns p minimized to
+// a program which diverges in the same field, along with its divergence
+func Check(engines []Engine, p *Program, cfg Config) (*Program, *Divergence, error) {
+	d, err := Run(engines, p.Build(), cfg)
+	if err != nil || d == nil {
+		return nil, nil, err
+	}
+	minimized := Minimize(p, func(q *Program) bool {
+		qd, err := Run(engines, q.Build(), cfg)
+		return err == nil && qd != nil && qd.Field == d.Field
+	})
+	d, err = Run(engines, minimized.Build(), cfg)
+	return minimized, d, err
+}
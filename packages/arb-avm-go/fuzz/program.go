@@ -0,0 +1,210 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package fuzz generates random well-formed AVM programs and inboxes, runs
+// them on several AVM implementations side by side, and minimizes any
+// difference between the implementations into a reproducible .ao file and
+// inbox.
+//
+// Programs are kept in an unbuilt form where code points refer to
+// instructions by index, so that the minimizer can remove instructions
+// without breaking the code point references of the rest of the program
+package fuzz
+
+import (
+	"math/big"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/goloader"
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/vm"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/protocol"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
+)
+
+type ValueKind int
+
+const (
+	KindInt ValueKind = iota
+	KindTuple
+	KindCodePoint
+)
+
+// Value is an immediate, static or inbox value of a Program. A code point
+// value refers to the instruction at index Target and is resolved when the
+// program is built
+type Value struct {
+	Kind   ValueKind
+	Int    *big.Int
+	Tuple  []Value
+	Target int
+}
+
+func intValue(x int64) Value {
+	return Value{Kind: KindInt, Int: big.NewInt(x)}
+}
+
+func (v Value) clone() Value {
+	if v.Kind == KindTuple {
+		tuple := make([]Value, len(v.Tuple))
+		for i, item := range v.Tuple {
+			tuple[i] = item.clone()
+		}
+		v.Tuple = tuple
+	}
+	return v
+}
+
+// resolve builds the value using the code points built so far. A code point
+// can only be built if its target is at least limit, since an immediate can't
+// contain the code point of its own instruction or of an earlier one. Code
+// points which can't be built become 0
+func (v Value) resolve(codePoints []value.CodePointValue, limit int) value.Value {
+	switch v.Kind {
+	case KindTuple:
+		vals := make([]value.Value, 0, len(v.Tuple))
+		for _, item := range v.Tuple {
+			vals = append(vals, item.resolve(codePoints, limit))
+		}
+		if len(vals) > value.MaxTupleSize {
+			vals = vals[:value.MaxTupleSize]
+		}
+		tup, _ := value.NewTupleFromSlice(vals)
+		return tup
+	case KindCodePoint:
+		if v.Target < limit || v.Target >= len(codePoints) {
+			return value.NewInt64Value(0)
+		}
+		return codePoints[v.Target]
+	default:
+		if v.Int == nil {
+			return value.NewInt64Value(0)
+		}
+		return value.NewIntValue(new(big.Int).Set(v.Int))
+	}
+}
+
+// renumber updates the code point targets in v after count instructions
+// starting at start were removed. Targets in the removed range move to the
+// instruction which followed it
+func (v *Value) renumber(start, count int) {
+	switch v.Kind {
+	case KindTuple:
+		for i := range v.Tuple {
+			v.Tuple[i].renumber(start, count)
+		}
+	case KindCodePoint:
+		if v.Target >= start+count {
+			v.Target -= count
+		} else if v.Target > start {
+			v.Target = start
+		}
+	}
+}
+
+// Insn is an instruction with an optional immediate value
+type Insn struct {
+	Op        value.Opcode
+	Immediate *Value
+}
+
+// Program is a generated program and the input it's run with
+type Program struct {
+	Code       []Insn
+	Static     Value
+	Inbox      []Value
+	TimeBounds *protocol.TimeBounds
+}
+
+func (p *Program) clone() *Program {
+	ret := &Program{
+		Code:       make([]Insn, len(p.Code)),
+		Static:     p.Static.clone(),
+		Inbox:      make([]Value, len(p.Inbox)),
+		TimeBounds: p.TimeBounds,
+	}
+	for i, insn := range p.Code {
+		ret.Code[i] = insn
+		if insn.Immediate != nil {
+			imm := insn.Immediate.clone()
+			ret.Code[i].Immediate = &imm
+		}
+	}
+	for i, msg := range p.Inbox {
+		ret.Inbox[i] = msg.clone()
+	}
+	return ret
+}
+
+// values calls f on every value of the program
+func (p *Program) values(f func(v *Value)) {
+	for i := range p.Code {
+		if p.Code[i].Immediate != nil {
+			f(p.Code[i].Immediate)
+		}
+	}
+	f(&p.Static)
+	for i := range p.Inbox {
+		f(&p.Inbox[i])
+	}
+}
+
+// withoutInsns returns a copy of p with count instructions starting at start
+// removed
+func (p *Program) withoutInsns(start, count int) *Program {
+	ret := p.clone()
+	ret.Code = append(ret.Code[:start], ret.Code[start+count:]...)
+	ret.values(func(v *Value) {
+		v.renumber(start, count)
+	})
+	return ret
+}
+
+// Case is a built program and the input it's run with
+type Case struct {
+	AO         *goloader.AOFile
+	Inbox      value.TupleValue
+	TimeBounds *protocol.TimeBounds
+}
+
+// Build resolves the code points of p and returns it as a Case
+func (p *Program) Build() *Case {
+	ops := make([]value.Operation, len(p.Code))
+	codePoints := make([]value.CodePointValue, len(p.Code))
+	nextHash := vm.HashOfLastInstruction
+	for i := len(p.Code) - 1; i >= 0; i-- {
+		insn := p.Code[i]
+		if insn.Immediate != nil {
+			ops[i] = value.ImmediateOperation{Op: insn.Op, Val: insn.Immediate.resolve(codePoints, i+1)}
+		} else {
+			ops[i] = value.BasicOperation{Op: insn.Op}
+		}
+		codePoints[i] = value.CodePointValue{InsnNum: int64(i), Op: ops[i], NextHash: nextHash}
+		nextHash = codePoints[i].Hash()
+	}
+
+	inbox := protocol.NewMessageStack()
+	for _, msg := range p.Inbox {
+		inbox.AddMessage(msg.resolve(codePoints, 0))
+	}
+	return &Case{
+		AO: &goloader.AOFile{
+			Version: goloader.CurrentAOVersion,
+			Code:    ops,
+			Static:  p.Static.resolve(codePoints, 0),
+		},
+		Inbox:      inbox.GetValue(),
+		TimeBounds: p.TimeBounds,
+	}
+}
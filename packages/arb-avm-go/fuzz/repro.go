@@ -0,0 +1,165 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fuzz
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/asm"
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/goloader"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/protocol"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
+)
+
+// reproInput is the part of a Case which isn't in the .ao file
+type reproInput struct {
+	LowerBoundBlock     string `json:"lowerBoundBlock"`
+	UpperBoundBlock     string `json:"upperBoundBlock"`
+	LowerBoundTimestamp string `json:"lowerBoundTimestamp"`
+	UpperBoundTimestamp string `json:"upperBoundTimestamp"`
+	// Inbox is the inbox value in the same encoding as values in .ao files
+	Inbox      string `json:"inbox"`
+	Divergence string `json:"divergence,omitempty"`
+}
+
+// WriteRepro writes c to dir as name.ao with its disassembly in name.s, and
+// its inbox and time bounds in name.json along with the divergence d if it
+// isn't nil
+func WriteRepro(dir string, name string, c *Case, d *Divergence) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	base := filepath.Join(dir, name)
+	if err := c.AO.WriteToFile(base + ".ao"); err != nil {
+		return err
+	}
+	f, err := os.Create(base + ".s")
+	if err != nil {
+		return err
+	}
+	if err := asm.Disassemble(c.AO, f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	var inbox bytes.Buffer
+	if err := goloader.WriteValue(c.Inbox, &inbox); err != nil {
+		return err
+	}
+	input := reproInput{
+		LowerBoundBlock:     c.TimeBounds.LowerBoundBlock.AsInt().String(),
+		UpperBoundBlock:     c.TimeBounds.UpperBoundBlock.AsInt().String(),
+		LowerBoundTimestamp: c.TimeBounds.LowerBoundTimestamp.String(),
+		UpperBoundTimestamp: c.TimeBounds.UpperBoundTimestamp.String(),
+		Inbox:               hex.EncodeToString(inbox.Bytes()),
+	}
+	if d != nil {
+		input.Divergence = d.Error()
+	}
+	data, err := json.MarshalIndent(input, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(base+".json", data, 0644)
+}
+
+func parseBigInt(str string) (*big.Int, error) {
+	x, ok := new(big.Int).SetString(str, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid integer %q", str)
+	}
+	return x, nil
+}
+
+// ReadRepro reads a case written by WriteRepro
+func ReadRepro(dir string, name string) (*Case, error) {
+	base := filepath.Join(dir, name)
+	ao, err := goloader.ReadAOFromFile(base + ".ao")
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(base + ".json")
+	if err != nil {
+		return nil, err
+	}
+	var input reproInput
+	if err := json.Unmarshal(data, &input); err != nil {
+		return nil, err
+	}
+
+	bounds := make([]*big.Int, 0, 4)
+	for _, str := range []string{
+		input.LowerBoundBlock,
+		input.UpperBoundBlock,
+		input.LowerBoundTimestamp,
+		input.UpperBoundTimestamp,
+	} {
+		x, err := parseBigInt(str)
+		if err != nil {
+			return nil, err
+		}
+		bounds = append(bounds, x)
+	}
+	inboxData, err := hex.DecodeString(input.Inbox)
+	if err != nil {
+		return nil, err
+	}
+	inboxVal, err := value.UnmarshalValueFromBytes(inboxData)
+	if err != nil {
+		return nil, err
+	}
+	inbox, ok := inboxVal.(value.TupleValue)
+	if !ok {
+		return nil, errors.New("inbox must be a tuple")
+	}
+	return &Case{
+		AO:    ao,
+		Inbox: inbox,
+		TimeBounds: &protocol.TimeBounds{
+			LowerBoundBlock:     common.NewTimeBlocks(bounds[0]),
+			UpperBoundBlock:     common.NewTimeBlocks(bounds[1]),
+			LowerBoundTimestamp: bounds[2],
+			UpperBoundTimestamp: bounds[3],
+		},
+	}, nil
+}
+
+// ReproNames returns the names of the cases written to dir by WriteRepro
+func ReproNames(dir string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.ao"))
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(files))
+	for _, file := range files {
+		base := filepath.Base(file)
+		names = append(names, base[:len(base)-len(".ao")])
+	}
+	return names, nil
+}
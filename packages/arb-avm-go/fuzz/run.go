@@ -0,0 +1,336 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fuzz
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/vm"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/machine"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/protocol"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
+)
+
+// Engine is an AVM implementation which programs are run on
+type Engine interface {
+	Name() string
+	NewMachine(c *Case) (machine.Machine, error)
+}
+
+// GoEngine runs programs on the Go AVM
+type GoEngine struct{}
+
+func (GoEngine) Name() string {
+	return "go"
+}
+
+func (GoEngine) NewMachine(c *Case) (machine.Machine, error) {
+	return vm.NewMachine(c.AO.Code, c.AO.Static, false, int64(1)<<62), nil
+}
+
+// ProofChecker checks the one step proof of a single step which started
+// with the machine hash beforeHash and produced assertion
+type ProofChecker interface {
+	CheckProof(
+		beforeHash common.Hash,
+		timeBounds *protocol.TimeBounds,
+		inbox value.TupleValue,
+		assertion *protocol.ExecutionAssertion,
+		proof []byte,
+	) error
+}
+
+type Config struct {
+	// MaxSteps is the number of steps after which a run is stopped
+	MaxSteps uint64
+	// StepsPerCheck is the number of steps run between comparisons of the
+	// machines. Smaller values find the step which diverged more precisely
+	StepsPerCheck uint64
+	// Proofs compares the one step proof of every step between the engines,
+	// and checks it with Prover if it's set
+	Proofs bool
+	Prover ProofChecker
+}
+
+func DefaultConfig() Config {
+	return Config{MaxSteps: 1000, StepsPerCheck: 1}
+}
+
+// Divergence is a difference between the engines found by Run
+type Divergence struct {
+	// Step is the number of steps which all engines ran the same way
+	Step uint64
+	// Field is what differed: load, panic, steps, status, blocked, gas, inbox,
+	// sends, logs, hash or proof
+	Field   string
+	Engines []string
+	Values  []string
+}
+
+func (d *Divergence) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%v differs after step %v", d.Field, d.Step)
+	for i, name := range d.Engines {
+		fmt.Fprintf(&sb, "\n  %v: %v", name, d.Values[i])
+	}
+	return sb.String()
+}
+
+type stepResult struct {
+	steps     uint64
+	assertion *protocol.ExecutionAssertion
+	status    machine.Status
+	blocked   machine.BlockReason
+	hash      common.Hash
+}
+
+type field struct {
+	name  string
+	get   func(r stepResult) string
+	equal func(a, b stepResult) bool
+}
+
+func valuesEqual(a, b []value.Value) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !value.Eq(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// fields are compared in order so that the cause of a divergence is reported
+// rather than its effect on the machine hash
+var fields = []field{
+	{
+		"steps",
+		func(r stepResult) string { return fmt.Sprint(r.steps) },
+		nil,
+	},
+	{
+		"status",
+		func(r stepResult) string { return fmt.Sprint(r.status) },
+		nil,
+	},
+	{
+		"blocked",
+		func(r stepResult) string { return fmt.Sprint(r.blocked) },
+		func(a, b stepResult) bool {
+			if a.blocked == nil || b.blocked == nil {
+				return a.blocked == nil && b.blocked == nil
+			}
+			return a.blocked.Equals(b.blocked)
+		},
+	},
+	{
+		"gas",
+		func(r stepResult) string { return fmt.Sprint(r.assertion.NumGas) },
+		nil,
+	},
+	{
+		"inbox",
+		func(r stepResult) string { return fmt.Sprint(r.assertion.DidInboxInsn) },
+		nil,
+	},
+	{
+		"sends",
+		func(r stepResult) string { return fmt.Sprint(r.assertion.OutMsgs) },
+		func(a, b stepResult) bool { return valuesEqual(a.assertion.OutMsgs, b.assertion.OutMsgs) },
+	},
+	{
+		"logs",
+		func(r stepResult) string { return fmt.Sprint(r.assertion.Logs) },
+		func(a, b stepResult) bool { return valuesEqual(a.assertion.Logs, b.assertion.Logs) },
+	},
+	{
+		"hash",
+		func(r stepResult) string { return r.hash.String() },
+		func(a, b stepResult) bool { return a.hash == b.hash && a.assertion.AfterHash == b.assertion.AfterHash },
+	},
+}
+
+func engineNames(engines []Engine) []string {
+	names := make([]string, 0, len(engines))
+	for _, engine := range engines {
+		names = append(names, engine.Name())
+	}
+	return names
+}
+
+func compareResults(engines []Engine, results []stepResult, step uint64) *Divergence {
+	for _, f := range fields {
+		for _, result := range results[1:] {
+			var same bool
+			if f.equal != nil {
+				same = f.equal(results[0], result)
+			} else {
+				same = f.get(results[0]) == f.get(result)
+			}
+			if same {
+				continue
+			}
+			values := make([]string, 0, len(results))
+			for _, r := range results {
+				values = append(values, f.get(r))
+			}
+			return &Divergence{Step: step, Field: f.name, Engines: engineNames(engines), Values: values}
+		}
+	}
+	return nil
+}
+
+// Run runs c on every engine in lockstep and returns the first difference
+// between them, or nil if they all ran the same way. An error is returned if
+// the case couldn't be run at all
+func Run(engines []Engine, c *Case, cfg Config) (*Divergence, error) {
+	machines := make([]machine.Machine, 0, len(engines))
+	loadErrors := make([]string, 0, len(engines))
+	failedLoads := 0
+	for _, engine := range engines {
+		m, err := engine.NewMachine(c)
+		if err != nil {
+			failedLoads++
+			loadErrors = append(loadErrors, err.Error())
+		} else {
+			loadErrors = append(loadErrors, "loaded")
+		}
+		machines = append(machines, m)
+	}
+	if failedLoads == len(engines) {
+		return nil, fmt.Errorf("no engine could load the program: %v", loadErrors[0])
+	}
+	if failedLoads > 0 {
+		return &Divergence{Field: "load", Engines: engineNames(engines), Values: loadErrors}, nil
+	}
+
+	stepsPerCheck := cfg.StepsPerCheck
+	if stepsPerCheck == 0 || cfg.Proofs {
+		stepsPerCheck = 1
+	}
+	inbox := c.Inbox
+	var steps uint64
+	for steps < cfg.MaxSteps {
+		count := stepsPerCheck
+		if steps+count > cfg.MaxSteps {
+			count = cfg.MaxSteps - steps
+		}
+
+		var proof []byte
+		beforeHash := machines[0].Hash()
+		if cfg.Proofs && machines[0].CurrentStatus() == machine.Extensive {
+			var d *Divergence
+			proof, d = compareProofs(engines, machines, steps)
+			if d != nil {
+				return d, nil
+			}
+		}
+
+		results := make([]stepResult, 0, len(machines))
+		panics := make([]string, 0, len(machines))
+		panicked := false
+		for _, m := range machines {
+			result, err := execute(m, count, c.TimeBounds, inbox)
+			results = append(results, result)
+			if err != nil {
+				panicked = true
+				panics = append(panics, err.Error())
+			} else {
+				panics = append(panics, "no panic")
+			}
+		}
+		if panicked {
+			return &Divergence{Step: steps, Field: "panic", Engines: engineNames(engines), Values: panics}, nil
+		}
+		if d := compareResults(engines, results, steps); d != nil {
+			return d, nil
+		}
+
+		result := results[0]
+		if cfg.Prover != nil && proof != nil && result.steps == 1 {
+			err := cfg.Prover.CheckProof(beforeHash, c.TimeBounds, inbox, result.assertion, proof)
+			if err != nil {
+				return &Divergence{
+					Step:    steps,
+					Field:   "proof",
+					Engines: []string{"one step proof"},
+					Values:  []string{err.Error()},
+				}, nil
+			}
+		}
+
+		steps += result.steps
+		if result.assertion.DidInboxInsn {
+			inbox = value.NewEmptyTuple()
+		}
+		if result.steps < count {
+			break
+		}
+	}
+	return nil, nil
+}
+
+// execute runs up to count steps on m. A panic in the engine is returned as
+// an error since it's a bug even if every engine panics
+func execute(
+	m machine.Machine,
+	count uint64,
+	timeBounds *protocol.TimeBounds,
+	inbox value.TupleValue,
+) (result stepResult, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	assertion, ran := m.ExecuteAssertion(count, timeBounds, inbox, 0)
+	return stepResult{
+		steps:     ran,
+		assertion: assertion,
+		status:    m.CurrentStatus(),
+		blocked:   m.IsBlocked(timeBounds.LowerBoundBlock, false),
+		hash:      m.Hash(),
+	}, nil
+}
+
+// compareProofs returns the proof of the next step if every engine gives the
+// same one
+func compareProofs(engines []Engine, machines []machine.Machine, step uint64) ([]byte, *Divergence) {
+	proofs := make([][]byte, 0, len(machines))
+	values := make([]string, 0, len(machines))
+	for _, m := range machines {
+		proof, err := m.MarshalForProof()
+		if err != nil {
+			proofs = append(proofs, nil)
+			values = append(values, err.Error())
+		} else {
+			proofs = append(proofs, proof)
+			values = append(values, fmt.Sprintf("%x", proof))
+		}
+	}
+	for i := range proofs[1:] {
+		if !bytes.Equal(proofs[0], proofs[i+1]) || values[0] != values[i+1] {
+			return nil, &Divergence{Step: step, Field: "proof", Engines: engineNames(engines), Values: values}
+		}
+	}
+	return proofs[0], nil
+}
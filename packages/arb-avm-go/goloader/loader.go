@@ -26,9 +26,20 @@ import (
 	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
 )
 
+// RawExtension is an extension section of an AO file. The loader doesn't
+// interpret extensions but keeps them so that files can be written back out
+// unchanged
 type RawExtension struct {
-	id   uint32
-	data []byte
+	ID   uint32
+	Data []byte
+}
+
+// AOFile is the contents of a compiled AVM program
+type AOFile struct {
+	Version    uint32
+	Extensions []RawExtension
+	Code       []value.Operation
+	Static     value.Value
 }
 
 type Error struct {
@@ -52,6 +63,25 @@ func LoadMachineFromFile(fileName string, warnMode bool) (*vm.Machine, error) {
 const CurrentAOVersion uint32 = 1
 
 func LoadMachine(rd io.Reader, warnMode bool) (*vm.Machine, error) {
+	ao, err := ReadAO(rd)
+	if err != nil {
+		return nil, err
+	}
+	maxSize := int64(1) << 62
+	return vm.NewMachine(ao.Code, ao.Static, warnMode, maxSize), nil
+}
+
+func ReadAOFromFile(fileName string) (*AOFile, error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ReadAO(f)
+}
+
+func ReadAO(rd io.Reader) (*AOFile, error) {
 	var aoVersion uint32
 	err := binary.Read(rd, binary.BigEndian, &aoVersion)
 	if err != nil {
@@ -76,13 +106,13 @@ func LoadMachine(rd io.Reader, warnMode bool) (*vm.Machine, error) {
 				return nil, err
 			}
 			extensionData := make([]byte, extensionLength)
-			_, err = rd.Read(extensionData)
+			_, err = io.ReadFull(rd, extensionData)
 			if err != nil {
 				return nil, err
 			}
 			extensions = append(extensions, RawExtension{
-				id:   extensionID,
-				data: extensionData,
+				ID:   extensionID,
+				Data: extensionData,
 			})
 		}
 	}
@@ -105,6 +135,10 @@ func LoadMachine(rd io.Reader, warnMode bool) (*vm.Machine, error) {
 		return nil, err2
 	}
 
-	maxSize := int64(1) << 62
-	return vm.NewMachine(insns, static, warnMode, maxSize), nil
+	return &AOFile{
+		Version:    aoVersion,
+		Extensions: extensions,
+		Code:       insns,
+		Static:     static,
+	}, nil
 }
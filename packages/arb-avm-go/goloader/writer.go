@@ -0,0 +1,125 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package goloader
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
+)
+
+func (ao *AOFile) WriteToFile(fileName string) error {
+	f, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	if err := ao.Write(f); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Write outputs ao in the format read by ReadAO
+func (ao *AOFile) Write(wr io.Writer) error {
+	if err := binary.Write(wr, binary.BigEndian, ao.Version); err != nil {
+		return err
+	}
+	for _, ext := range ao.Extensions {
+		if ext.ID == 0 {
+			return Error{"AO extension id 0 is reserved for the end of the extensions"}
+		}
+		if err := binary.Write(wr, binary.BigEndian, ext.ID); err != nil {
+			return err
+		}
+		if err := binary.Write(wr, binary.BigEndian, uint32(len(ext.Data))); err != nil {
+			return err
+		}
+		if _, err := wr.Write(ext.Data); err != nil {
+			return err
+		}
+	}
+	if err := binary.Write(wr, binary.BigEndian, uint32(0)); err != nil {
+		return err
+	}
+	if err := binary.Write(wr, binary.BigEndian, uint64(len(ao.Code))); err != nil {
+		return err
+	}
+	for _, op := range ao.Code {
+		if err := WriteOperation(op, wr); err != nil {
+			return err
+		}
+	}
+	return WriteValue(ao.Static, wr)
+}
+
+// WriteOperation matches value.NewOperationFromReader
+func WriteOperation(op value.Operation, wr io.Writer) error {
+	switch op := op.(type) {
+	case value.BasicOperation:
+		_, err := wr.Write([]byte{0, byte(op.Op)})
+		return err
+	case value.ImmediateOperation:
+		if _, err := wr.Write([]byte{1, byte(op.Op)}); err != nil {
+			return err
+		}
+		return WriteValue(op.Val, wr)
+	default:
+		return fmt.Errorf("unknown operation type %T", op)
+	}
+}
+
+// WriteValue matches value.UnmarshalValue. It differs from value.MarshalValue
+// which leaves out the immediate count of code point operations and the size
+// of hash only values
+func WriteValue(val value.Value, wr io.Writer) error {
+	if _, err := wr.Write([]byte{val.InternalTypeCode()}); err != nil {
+		return err
+	}
+	switch val := val.(type) {
+	case value.IntValue:
+		return val.Marshal(wr)
+	case value.CodePointValue:
+		if err := binary.Write(wr, binary.BigEndian, val.InsnNum); err != nil {
+			return err
+		}
+		if err := WriteOperation(val.Op, wr); err != nil {
+			return err
+		}
+		_, err := wr.Write(val.NextHash[:])
+		return err
+	case value.HashOnlyValue:
+		if err := binary.Write(wr, binary.LittleEndian, val.Size()); err != nil {
+			return err
+		}
+		hash := val.Hash()
+		_, err := wr.Write(hash[:])
+		return err
+	case value.TupleValue:
+		for _, item := range val.Contents() {
+			if err := WriteValue(item, wr); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown value type %T", val)
+	}
+}
@@ -46,6 +46,12 @@ type Machine struct {
 	sizeException bool
 
 	warnHandler WarningHandler
+
+	tracer         Tracer
+	traceStepCount uint64
+	traceWarnings  []string
+
+	profile *machine.Profile
 }
 
 func (m *Machine) Checkpoint(storage machine.CheckpointStorage) bool {
@@ -107,6 +113,10 @@ func NewMachine(opCodes []value.Operation, staticVal value.Value, warn bool, siz
 		sizeLimit,
 		false,
 		wh,
+		nil,
+		0,
+		nil,
+		nil,
 	}
 	ret.checkSize()
 	return ret
@@ -250,7 +260,11 @@ func (m *Machine) IsBlocked(currentTime *common.TimeBlocks, newMessages bool) ma
 		if immediate, ok := op.(value.ImmediateOperation); ok {
 			param = immediate.Val
 		} else {
-			param, _ = m.stack.Pop()
+			var err error
+			param, err = m.stack.Pop()
+			if err != nil {
+				return nil
+			}
 			m.stack.Push(param)
 		}
 		paramInt, ok := param.(value.IntValue)
@@ -279,8 +293,16 @@ func (m *Machine) ExecuteAssertion(
 		timeBounds,
 		inbox,
 	)
+	profile := m.newRunProfile()
 	for assCtx.StepCount() < maxSteps {
-		_, blocked := RunInstruction(m, m.pc.GetCurrentInsn())
+		var blocked machine.BlockReason
+		pc, steps, gas := m.pc.pc, assCtx.StepCount(), assCtx.GasCount()
+		if m.tracer != nil {
+			blocked = m.runTracedInstruction(m.pc.GetCurrentInsn(), assCtx)
+		} else {
+			_, blocked = RunInstruction(m, m.pc.GetCurrentInsn())
+		}
+		profile.record(pc, assCtx.StepCount()-steps, assCtx.GasCount()-gas)
 		if blocked != nil {
 			break
 		}
@@ -292,6 +314,7 @@ func (m *Machine) ExecuteAssertion(
 			}
 		}
 	}
+	profile.finish()
 	return assCtx.Finalize(m)
 }
 
@@ -300,6 +323,9 @@ func (m *Machine) Send(message value.Value) {
 }
 
 func (m *Machine) Warn(str string) {
+	if m.tracer != nil {
+		m.traceWarnings = append(m.traceWarnings, str)
+	}
 	m.warnHandler.Warn(str)
 }
 
@@ -425,6 +451,10 @@ func (m *Machine) Clone() machine.Machine { // clone machine state--new machine
 		m.sizeLimit,
 		m.sizeException,
 		newWarnHandler,
+		nil,
+		0,
+		nil,
+		m.profile,
 	}
 	// WARNING: risk of bug here, because of shallow copy of stack, callstack
 	return ret
@@ -0,0 +1,41 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vm
+
+import (
+	"testing"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/code"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
+)
+
+func TestIsBlockedInboxEmptyStack(t *testing.T) {
+	insns := []value.Operation{
+		value.BasicOperation{Op: code.INBOX},
+		value.BasicOperation{Op: code.HALT},
+	}
+	m := NewMachine(insns, value.NewInt64Value(1), false, 100)
+	// Running the inbox instruction fails since it has no timeout, so the
+	// machine isn't blocked waiting for messages
+	if blocked := m.IsBlocked(common.NewTimeBlocksInt(0), false); blocked != nil {
+		t.Error("machine with empty stack blocked on", blocked)
+	}
+	if m.Stack().Count() != 0 {
+		t.Error("checking for a block changed the stack")
+	}
+}
@@ -0,0 +1,67 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vm
+
+import (
+	"github.com/offchainlabs/arbitrum/packages/arb-util/machine"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
+)
+
+// SetProfile records the cost of every instruction run by ExecuteAssertion
+// into p, or stops recording if p is nil. Clones of the machine record into
+// the same profile
+func (m *Machine) SetProfile(p *machine.Profile) {
+	m.profile = p
+}
+
+// runProfile counts the cost of each pc during a single ExecuteAssertion so
+// that the shared profile is only locked once per run. A nil runProfile
+// records nothing
+type runProfile struct {
+	target  *machine.Profile
+	code    []value.Operation
+	entries []machine.ProfileEntry
+}
+
+func (m *Machine) newRunProfile() *runProfile {
+	if m.profile == nil {
+		return nil
+	}
+	return &runProfile{
+		target:  m.profile,
+		code:    m.pc.flat,
+		entries: make([]machine.ProfileEntry, len(m.pc.flat)),
+	}
+}
+
+func (p *runProfile) record(pc int64, steps uint64, gas uint64) {
+	if p == nil || steps == 0 || pc < 0 || pc >= int64(len(p.entries)) {
+		return
+	}
+	entry := &p.entries[pc]
+	entry.PC = pc
+	entry.Opcode = p.code[pc].GetOp()
+	entry.Steps += steps
+	entry.Gas += gas
+}
+
+func (p *runProfile) finish() {
+	if p == nil {
+		return
+	}
+	p.target.Add(p.entries)
+}
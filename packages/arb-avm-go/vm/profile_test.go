@@ -0,0 +1,74 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vm
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/code"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/machine"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/protocol"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
+)
+
+func TestProfile(t *testing.T) {
+	insns := []value.Operation{
+		value.ImmediateOperation{Op: code.NOP, Val: value.NewInt64Value(2)},
+		value.ImmediateOperation{Op: code.ADD, Val: value.NewInt64Value(4)},
+		value.BasicOperation{Op: code.POP},
+		value.BasicOperation{Op: code.HALT},
+	}
+	m := NewMachine(insns, value.NewInt64Value(0), false, 100)
+	profile := machine.NewProfile()
+	m.SetProfile(profile)
+	tb := &protocol.TimeBounds{
+		LowerBoundBlock:     common.NewTimeBlocks(big.NewInt(0)),
+		UpperBoundBlock:     common.NewTimeBlocks(big.NewInt(100)),
+		LowerBoundTimestamp: big.NewInt(0),
+		UpperBoundTimestamp: big.NewInt(100),
+	}
+	// Clones record into the same profile so every pc is counted twice
+	assertion, steps := m.Clone().ExecuteAssertion(1000, tb, value.NewEmptyTuple(), 0)
+	assertion2, steps2 := m.Clone().ExecuteAssertion(1000, tb, value.NewEmptyTuple(), 0)
+
+	entries := profile.Entries()
+	var profileSteps, profileGas uint64
+	for _, entry := range entries {
+		profileSteps += entry.Steps
+		profileGas += entry.Gas
+		if entry.Opcode != insns[entry.PC].GetOp() {
+			t.Error("pc", entry.PC, "recorded opcode", entry.Opcode)
+		}
+	}
+	if profileSteps != steps+steps2 {
+		t.Error("profile counted", profileSteps, "steps instead of", steps+steps2)
+	}
+	if profileGas != assertion.NumGas+assertion2.NumGas {
+		t.Error("profile counted", profileGas, "gas instead of", assertion.NumGas+assertion2.NumGas)
+	}
+	if len(entries) < 3 || entries[1].PC != 1 || entries[1].Steps != 2 || entries[1].Gas != 2*Instructions[code.ADD].gas {
+		t.Error("profile had wrong counts for add", entries)
+	}
+
+	m.SetProfile(nil)
+	m.ExecuteAssertion(1000, tb, value.NewEmptyTuple(), 0)
+	if len(profile.Entries()) != len(entries) || profile.Entries()[1].Steps != 2 {
+		t.Error("machine recorded after its profile was removed")
+	}
+}
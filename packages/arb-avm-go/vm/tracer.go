@@ -0,0 +1,508 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vm
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/code"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/machine"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
+)
+
+// TraceStep records the effect of running a single instruction
+type TraceStep struct {
+	// Step counts the instructions traced on the machine so far
+	Step      uint64
+	PC        int64
+	Opcode    value.Opcode
+	Immediate value.Value // nil unless the operation has an immediate value
+
+	// Gas is the ArbGas charged for the instruction, which is zero if it
+	// blocked
+	Gas uint64
+
+	// StackPops includes the immediate value if the instruction popped it.
+	// Pushed holds the values left on top of the stack by the instruction,
+	// with the top of the stack last
+	StackPops    int
+	Pushed       []value.Value
+	AuxStackPops int
+	AuxPushed    []value.Value
+
+	// Register is the register's new value if the instruction set it
+	Register value.Value
+
+	// BlockReason is set if the machine couldn't run the instruction
+	BlockReason machine.BlockReason
+	Warnings    []string
+}
+
+func (s TraceStep) OpName() string {
+	name, ok := code.InstructionNames[s.Opcode]
+	if !ok {
+		return fmt.Sprintf("INVALID(%#x)", s.Opcode)
+	}
+	return name
+}
+
+// Tracer is called after every instruction that a machine runs in
+// ExecuteAssertion once it is installed with SetTracer
+type Tracer interface {
+	TraceStep(step TraceStep)
+}
+
+// SetTracer installs a tracer on the machine, or removes it if t is nil.
+// Clones of the machine aren't traced
+func (m *Machine) SetTracer(t Tracer) {
+	m.tracer = t
+}
+
+// popTop removes the top count values of s and returns them with the top of
+// the stack last, or as many values as s has if it has fewer
+func popTop(s interface {
+	Pop() (value.Value, error)
+}, count int) []value.Value {
+	vals := make([]value.Value, count)
+	for i := count - 1; i >= 0; i-- {
+		val, err := s.Pop()
+		if err != nil {
+			return vals[i+1:]
+		}
+		vals[i] = val
+	}
+	return vals
+}
+
+func (m *Machine) runTracedInstruction(op value.Operation, assCtx *MachineAssertionContext) machine.BlockReason {
+	step := TraceStep{
+		Step:   m.traceStepCount,
+		PC:     m.pc.pc,
+		Opcode: op.GetOp(),
+	}
+	stackCount := m.stack.Count()
+	if immediate, ok := op.(value.ImmediateOperation); ok {
+		step.Immediate = immediate.Val
+		stackCount++
+	}
+	auxStackCount := m.auxstack.Count()
+	m.traceWarnings = nil
+	gas := assCtx.GasCount()
+
+	mods, blocked := RunInstruction(m, op)
+
+	step.Warnings = m.traceWarnings
+	m.traceWarnings = nil
+	if blocked != nil {
+		step.BlockReason = blocked
+	} else {
+		step.Gas = assCtx.GasCount() - gas
+		step.StackPops = mods.stackPopsPerformed
+		step.AuxStackPops = mods.auxStackPopsPerformed
+		pushes := int(m.stack.Count()-stackCount) + step.StackPops
+		auxPushes := int(m.auxstack.Count()-auxStackCount) + step.AuxStackPops
+		if pushes > 0 {
+			step.Pushed = popTop(m.stack, pushes)
+			for _, val := range step.Pushed {
+				m.stack.Push(val)
+			}
+		}
+		if auxPushes > 0 {
+			step.AuxPushed = popTop(m.auxstack, auxPushes)
+			for _, val := range step.AuxPushed {
+				m.auxstack.Push(val)
+			}
+		}
+		if op.GetOp() == code.RSET {
+			step.Register = m.register.Get()
+		}
+	}
+	m.tracer.TraceStep(step)
+	m.traceStepCount++
+	return blocked
+}
+
+func blockReasonString(reason machine.BlockReason) string {
+	if reason == nil {
+		return ""
+	}
+	return fmt.Sprint(reason)
+}
+
+func valueStrings(vals []value.Value) []string {
+	if len(vals) == 0 {
+		return nil
+	}
+	ret := make([]string, 0, len(vals))
+	for _, val := range vals {
+		ret = append(ret, fmt.Sprint(val))
+	}
+	return ret
+}
+
+func (s TraceStep) MarshalJSON() ([]byte, error) {
+	var immediate, register string
+	if s.Immediate != nil {
+		immediate = fmt.Sprint(s.Immediate)
+	}
+	if s.Register != nil {
+		register = fmt.Sprint(s.Register)
+	}
+	return json.Marshal(struct {
+		Step         uint64   `json:"step"`
+		PC           int64    `json:"pc"`
+		Opcode       uint8    `json:"opcode"`
+		OpName       string   `json:"op"`
+		Immediate    string   `json:"immediate,omitempty"`
+		Gas          uint64   `json:"gas"`
+		StackPops    int      `json:"stackPops"`
+		Pushed       []string `json:"pushed,omitempty"`
+		AuxStackPops int      `json:"auxStackPops"`
+		AuxPushed    []string `json:"auxPushed,omitempty"`
+		Register     string   `json:"register,omitempty"`
+		BlockReason  string   `json:"blockReason,omitempty"`
+		Warnings     []string `json:"warnings,omitempty"`
+	}{
+		Step:         s.Step,
+		PC:           s.PC,
+		Opcode:       uint8(s.Opcode),
+		OpName:       s.OpName(),
+		Immediate:    immediate,
+		Gas:          s.Gas,
+		StackPops:    s.StackPops,
+		Pushed:       valueStrings(s.Pushed),
+		AuxStackPops: s.AuxStackPops,
+		AuxPushed:    valueStrings(s.AuxPushed),
+		Register:     register,
+		BlockReason:  blockReasonString(s.BlockReason),
+		Warnings:     s.Warnings,
+	})
+}
+
+// JSONTracer writes each step as a line of JSON. After a write fails no more
+// steps are written and the error is returned by Err
+type JSONTracer struct {
+	enc *json.Encoder
+	err error
+}
+
+func NewJSONTracer(w io.Writer) *JSONTracer {
+	return &JSONTracer{enc: json.NewEncoder(w)}
+}
+
+func (t *JSONTracer) TraceStep(step TraceStep) {
+	if t.err != nil {
+		return
+	}
+	t.err = t.enc.Encode(step)
+}
+
+func (t *JSONTracer) Err() error {
+	return t.err
+}
+
+// Flags describing the optional fields of a step in the binary trace format
+const (
+	traceHasImmediate byte = 1 << iota
+	traceHasRegister
+	traceHasBlockReason
+)
+
+// Block reasons in the binary trace format
+const (
+	traceHaltBlocked byte = iota
+	traceErrorBlocked
+	traceBreakpointBlocked
+	traceInboxBlocked
+)
+
+// BinaryTracer writes each step in a compact binary format which can be read
+// back with ReadTraceStep. Each step is its flags, step number, pc, opcode,
+// gas and pop counts, followed by the values it pushed, its optional fields
+// and its warnings. Integers are varint encoded and values use the standard
+// value encoding. The output is buffered until Flush is called. After a
+// write fails no more steps are written and the error is returned by Flush
+type BinaryTracer struct {
+	w   *bufio.Writer
+	err error
+}
+
+func NewBinaryTracer(w io.Writer) *BinaryTracer {
+	return &BinaryTracer{w: bufio.NewWriter(w)}
+}
+
+func (t *BinaryTracer) TraceStep(step TraceStep) {
+	if t.err != nil {
+		return
+	}
+	t.err = writeTraceStep(t.w, step)
+}
+
+func (t *BinaryTracer) Flush() error {
+	if t.err != nil {
+		return t.err
+	}
+	return t.w.Flush()
+}
+
+func writeUvarint(w io.Writer, x uint64) error {
+	var buf [binary.MaxVarintLen64]byte
+	_, err := w.Write(buf[:binary.PutUvarint(buf[:], x)])
+	return err
+}
+
+func writeVarint(w io.Writer, x int64) error {
+	var buf [binary.MaxVarintLen64]byte
+	_, err := w.Write(buf[:binary.PutVarint(buf[:], x)])
+	return err
+}
+
+func writeValues(w io.Writer, vals []value.Value) error {
+	if err := writeUvarint(w, uint64(len(vals))); err != nil {
+		return err
+	}
+	for _, val := range vals {
+		if err := value.MarshalValue(val, w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeBlockReason(w io.Writer, reason machine.BlockReason) error {
+	switch reason := reason.(type) {
+	case machine.HaltBlocked:
+		_, err := w.Write([]byte{traceHaltBlocked})
+		return err
+	case machine.ErrorBlocked:
+		_, err := w.Write([]byte{traceErrorBlocked})
+		return err
+	case machine.BreakpointBlocked:
+		_, err := w.Write([]byte{traceBreakpointBlocked})
+		return err
+	case machine.InboxBlocked:
+		if _, err := w.Write([]byte{traceInboxBlocked}); err != nil {
+			return err
+		}
+		return value.MarshalValue(reason.Timeout, w)
+	default:
+		return fmt.Errorf("can't encode block reason %T", reason)
+	}
+}
+
+func writeTraceStep(w io.Writer, step TraceStep) error {
+	var flags byte
+	if step.Immediate != nil {
+		flags |= traceHasImmediate
+	}
+	if step.Register != nil {
+		flags |= traceHasRegister
+	}
+	if step.BlockReason != nil {
+		flags |= traceHasBlockReason
+	}
+	if _, err := w.Write([]byte{flags}); err != nil {
+		return err
+	}
+	if err := writeUvarint(w, step.Step); err != nil {
+		return err
+	}
+	if err := writeVarint(w, step.PC); err != nil {
+		return err
+	}
+	if _, err := w.Write([]byte{byte(step.Opcode)}); err != nil {
+		return err
+	}
+	if err := writeUvarint(w, step.Gas); err != nil {
+		return err
+	}
+	if err := writeUvarint(w, uint64(step.StackPops)); err != nil {
+		return err
+	}
+	if err := writeUvarint(w, uint64(step.AuxStackPops)); err != nil {
+		return err
+	}
+	if err := writeValues(w, step.Pushed); err != nil {
+		return err
+	}
+	if err := writeValues(w, step.AuxPushed); err != nil {
+		return err
+	}
+	if step.Immediate != nil {
+		if err := value.MarshalValue(step.Immediate, w); err != nil {
+			return err
+		}
+	}
+	if step.Register != nil {
+		if err := value.MarshalValue(step.Register, w); err != nil {
+			return err
+		}
+	}
+	if step.BlockReason != nil {
+		if err := writeBlockReason(w, step.BlockReason); err != nil {
+			return err
+		}
+	}
+	if err := writeUvarint(w, uint64(len(step.Warnings))); err != nil {
+		return err
+	}
+	for _, warning := range step.Warnings {
+		if err := writeUvarint(w, uint64(len(warning))); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, warning); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// maxTraceItems bounds the lengths read from a binary trace so that a
+// corrupt trace can't cause huge allocations
+const maxTraceItems = 1 << 20
+
+func readCount(r io.ByteReader) (int, error) {
+	count, err := binary.ReadUvarint(r)
+	if err != nil {
+		return 0, err
+	}
+	if count > maxTraceItems {
+		return 0, errors.New("binary trace length out of range")
+	}
+	return int(count), nil
+}
+
+func readValues(r *bufio.Reader) ([]value.Value, error) {
+	count, err := readCount(r)
+	if err != nil || count == 0 {
+		return nil, err
+	}
+	vals := make([]value.Value, 0, count)
+	for i := 0; i < count; i++ {
+		val, err := value.UnmarshalValue(r)
+		if err != nil {
+			return nil, err
+		}
+		vals = append(vals, val)
+	}
+	return vals, nil
+}
+
+func readBlockReason(r *bufio.Reader) (machine.BlockReason, error) {
+	kind, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	switch kind {
+	case traceHaltBlocked:
+		return machine.HaltBlocked{}, nil
+	case traceErrorBlocked:
+		return machine.ErrorBlocked{}, nil
+	case traceBreakpointBlocked:
+		return machine.BreakpointBlocked{}, nil
+	case traceInboxBlocked:
+		timeout, err := value.UnmarshalValue(r)
+		if err != nil {
+			return nil, err
+		}
+		timeoutInt, ok := timeout.(value.IntValue)
+		if !ok {
+			return nil, errors.New("inbox block timeout must be an int")
+		}
+		return machine.InboxBlocked{Timeout: timeoutInt}, nil
+	default:
+		return nil, fmt.Errorf("unknown block reason %v in binary trace", kind)
+	}
+}
+
+// ReadTraceStep reads a step written by BinaryTracer. It returns io.EOF if r
+// has no more steps
+func ReadTraceStep(r *bufio.Reader) (TraceStep, error) {
+	var step TraceStep
+	flags, err := r.ReadByte()
+	if err != nil {
+		return step, err
+	}
+	fail := func(err error) (TraceStep, error) {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return TraceStep{}, err
+	}
+	if step.Step, err = binary.ReadUvarint(r); err != nil {
+		return fail(err)
+	}
+	if step.PC, err = binary.ReadVarint(r); err != nil {
+		return fail(err)
+	}
+	opcode, err := r.ReadByte()
+	if err != nil {
+		return fail(err)
+	}
+	step.Opcode = value.Opcode(opcode)
+	if step.Gas, err = binary.ReadUvarint(r); err != nil {
+		return fail(err)
+	}
+	if step.StackPops, err = readCount(r); err != nil {
+		return fail(err)
+	}
+	if step.AuxStackPops, err = readCount(r); err != nil {
+		return fail(err)
+	}
+	if step.Pushed, err = readValues(r); err != nil {
+		return fail(err)
+	}
+	if step.AuxPushed, err = readValues(r); err != nil {
+		return fail(err)
+	}
+	if flags&traceHasImmediate != 0 {
+		if step.Immediate, err = value.UnmarshalValue(r); err != nil {
+			return fail(err)
+		}
+	}
+	if flags&traceHasRegister != 0 {
+		if step.Register, err = value.UnmarshalValue(r); err != nil {
+			return fail(err)
+		}
+	}
+	if flags&traceHasBlockReason != 0 {
+		if step.BlockReason, err = readBlockReason(r); err != nil {
+			return fail(err)
+		}
+	}
+	warningCount, err := readCount(r)
+	if err != nil {
+		return fail(err)
+	}
+	for i := 0; i < warningCount; i++ {
+		length, err := readCount(r)
+		if err != nil {
+			return fail(err)
+		}
+		buf := make([]byte, length)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return fail(err)
+		}
+		step.Warnings = append(step.Warnings, string(buf))
+	}
+	return step, nil
+}
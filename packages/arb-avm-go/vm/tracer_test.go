@@ -0,0 +1,207 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vm
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"math/big"
+	"testing"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/code"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/machine"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/protocol"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
+)
+
+type recordingTracer struct {
+	steps []TraceStep
+}
+
+func (t *recordingTracer) TraceStep(step TraceStep) {
+	t.steps = append(t.steps, step)
+}
+
+type multiTracer []Tracer
+
+func (t multiTracer) TraceStep(step TraceStep) {
+	for _, tracer := range t {
+		tracer.TraceStep(step)
+	}
+}
+
+func runTracedMachine(t *testing.T, tracer Tracer) {
+	insns := []value.Operation{
+		value.ImmediateOperation{Op: code.NOP, Val: value.NewInt64Value(2)},
+		value.ImmediateOperation{Op: code.ADD, Val: value.NewInt64Value(4)},
+		value.BasicOperation{Op: code.DUP0},
+		value.BasicOperation{Op: code.AUXPUSH},
+		value.BasicOperation{Op: code.RSET},
+		value.BasicOperation{Op: code.POP},
+		value.BasicOperation{Op: code.BREAKPOINT},
+		value.BasicOperation{Op: code.HALT},
+	}
+	m := NewMachine(insns, value.NewInt64Value(1), false, 100)
+	m.SetTracer(tracer)
+	tb := &protocol.TimeBounds{
+		LowerBoundBlock:     common.NewTimeBlocks(big.NewInt(0)),
+		UpperBoundBlock:     common.NewTimeBlocks(big.NewInt(100000)),
+		LowerBoundTimestamp: big.NewInt(0),
+		UpperBoundTimestamp: big.NewInt(100000),
+	}
+	_, steps := m.ExecuteAssertion(80000, tb, value.NewEmptyTuple(), 0)
+	if steps != 6 {
+		t.Fatal("machine ran", steps, "steps instead of 6")
+	}
+}
+
+func TestTracerSteps(t *testing.T) {
+	tracer := &recordingTracer{}
+	runTracedMachine(t, tracer)
+
+	if len(tracer.steps) != 7 {
+		t.Fatal("traced", len(tracer.steps), "steps instead of 7")
+	}
+	add := tracer.steps[1]
+	if add.OpName() != code.InstructionNames[code.ADD] {
+		t.Error("second step was", add.OpName())
+	}
+	if add.StackPops != 2 || len(add.Pushed) != 1 || !value.Eq(add.Pushed[0], value.NewInt64Value(6)) {
+		t.Error("add step had wrong stack delta", add.StackPops, add.Pushed)
+	}
+	if add.Gas != 3 {
+		t.Error("add used", add.Gas, "gas")
+	}
+	dup := tracer.steps[2]
+	if dup.StackPops != 1 || len(dup.Pushed) != 2 {
+		t.Error("dup step had wrong stack delta", dup.StackPops, dup.Pushed)
+	}
+	auxPush := tracer.steps[3]
+	if auxPush.StackPops != 1 || len(auxPush.Pushed) != 0 || len(auxPush.AuxPushed) != 1 {
+		t.Error("auxpush step had wrong stack delta", auxPush.StackPops, auxPush.Pushed, auxPush.AuxPushed)
+	}
+	rset := tracer.steps[4]
+	if rset.Register == nil || !value.Eq(rset.Register, value.NewInt64Value(6)) {
+		t.Error("rset step recorded register", rset.Register)
+	}
+	// POP on an empty stack fails and jumps to the error handler, which is
+	// unset, so the machine stops
+	pop := tracer.steps[5]
+	if len(pop.Warnings) == 0 {
+		t.Error("pop on empty stack didn't record a warning")
+	}
+	last := tracer.steps[6]
+	if _, ok := last.BlockReason.(machine.ErrorBlocked); !ok {
+		t.Error("last step had block reason", last.BlockReason)
+	}
+	if last.Gas != 0 {
+		t.Error("blocked step used gas")
+	}
+	for i, step := range tracer.steps {
+		if step.Step != uint64(i) {
+			t.Error("step", i, "was numbered", step.Step)
+		}
+	}
+}
+
+func TestTracerInvalidOpcode(t *testing.T) {
+	insns := []value.Operation{
+		value.BasicOperation{Op: value.Opcode(0xf0)},
+		value.BasicOperation{Op: code.HALT},
+	}
+	m := NewMachine(insns, value.NewInt64Value(1), false, 100)
+	tracer := &recordingTracer{}
+	m.SetTracer(tracer)
+	tb := &protocol.TimeBounds{
+		LowerBoundBlock:     common.NewTimeBlocks(big.NewInt(0)),
+		UpperBoundBlock:     common.NewTimeBlocks(big.NewInt(100000)),
+		LowerBoundTimestamp: big.NewInt(0),
+		UpperBoundTimestamp: big.NewInt(100000),
+	}
+	m.ExecuteAssertion(10, tb, value.NewEmptyTuple(), 0)
+	if len(tracer.steps) == 0 {
+		t.Fatal("invalid opcode wasn't traced")
+	}
+	step := tracer.steps[0]
+	if step.Opcode != 0xf0 || step.OpName() != "INVALID(0xf0)" {
+		t.Error("traced", step.OpName(), "instead of the invalid opcode")
+	}
+	if step.Gas != 0 {
+		t.Error("invalid opcode used", step.Gas, "gas")
+	}
+}
+
+func TestJSONTracer(t *testing.T) {
+	var buf bytes.Buffer
+	tracer := NewJSONTracer(&buf)
+	runTracedMachine(t, tracer)
+	if err := tracer.Err(); err != nil {
+		t.Fatal(err)
+	}
+	dec := json.NewDecoder(&buf)
+	lines := 0
+	for {
+		var step map[string]interface{}
+		if err := dec.Decode(&step); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := step["op"]; !ok {
+			t.Error("step", lines, "had no op name")
+		}
+		lines++
+	}
+	if lines != 7 {
+		t.Error("wrote", lines, "lines instead of 7")
+	}
+}
+
+func TestBinaryTracerRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	recorded := &recordingTracer{}
+	binaryTracer := NewBinaryTracer(&buf)
+	runTracedMachine(t, multiTracer{recorded, binaryTracer})
+	if err := binaryTracer.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	r := bufio.NewReader(&buf)
+	for i, expected := range recorded.steps {
+		step, err := ReadTraceStep(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expectedJSON, err := json.Marshal(expected)
+		if err != nil {
+			t.Fatal(err)
+		}
+		stepJSON, err := json.Marshal(step)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(expectedJSON, stepJSON) {
+			t.Error("step", i, "read back as", string(stepJSON), "instead of", string(expectedJSON))
+		}
+	}
+	if _, err := ReadTraceStep(r); err != io.EOF {
+		t.Error("expected end of trace but got", err)
+	}
+}
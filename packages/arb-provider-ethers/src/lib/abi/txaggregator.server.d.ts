@@ -11,8 +11,67 @@ export interface SendTransactionArgs {
 
 export interface SendTransactionReply {
   accepted?: boolean
+  txHash?: string
+}
+
+export interface GetTransactionStatusArgs {
+  txHash?: string
+}
+
+export interface GetTransactionStatusReply {
+  status?: string
+  l1TxHash?: string
+  error?: string
+}
+
+export interface PendingTransactionsArgs {
+  address?: string
+}
+
+export interface PendingTransaction {
+  from?: string
+  to?: string
+  sequenceNum?: string
+  value?: string
+  data?: string
+  ready?: boolean
+}
+
+export interface PendingTransactionsReply {
+  transactions?: Array<PendingTransaction>
+}
+
+export interface PendingNonceArgs {
+  address?: string
+}
+
+export interface PendingNonceReply {
+  nonce?: string
+  found?: boolean
+}
+
+export interface BatchStatusArgs {}
+
+export interface BatchInfo {
+  id?: number
+  status?: string
+  l1TxHash?: string
+  transactionCount?: number
+  error?: string
+}
+
+export interface BatchStatusReply {
+  batches?: Array<BatchInfo>
+  readyTransactions?: number
+  retryInSeconds?: number
 }
 
 export interface TxAggregatorService {
   SendTransaction: (r: SendTransactionArgs) => SendTransactionReply
+  PendingTransactions: (r: PendingTransactionsArgs) => PendingTransactionsReply
+  PendingNonce: (r: PendingNonceArgs) => PendingNonceReply
+  BatchStatus: (r: BatchStatusArgs) => BatchStatusReply
+  GetTransactionStatus: (
+    r: GetTransactionStatusArgs
+  ) => GetTransactionStatusReply
 }
@@ -16,6 +16,7 @@ import (
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 
 	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/arbbridge"
@@ -33,6 +34,11 @@ type ArbConnection struct {
 	vmId        common.Address
 	globalInbox arbbridge.GlobalInbox
 	sequenceNum *big.Int
+
+	// pushClient is connected to the validator's WebSocket endpoint if one
+	// is available and is used to receive logs without polling
+	pushClient *ethclient.Client
+	pushRPC    *rpc.Client
 }
 
 func Dial(url string, auth *bind.TransactOpts, ethclint *ethclient.Client) (*ArbConnection, error) {
@@ -58,6 +64,30 @@ func Dial(url string, auth *bind.TransactOpts, ethclint *ethclient.Client) (*Arb
 	return &ArbConnection{proxy: proxy, vmId: vmId, globalInbox: globalInbox}, nil
 }
 
+// DialWithSubscriptions connects to the validator like Dial, and also
+// connects to the validator's Ethereum JSON-RPC WebSocket endpoint at wsURL
+// so that log subscriptions are pushed rather than polled. If the WebSocket
+// endpoint can't be reached, subscriptions fall back to polling
+func DialWithSubscriptions(
+	url string,
+	wsURL string,
+	auth *bind.TransactOpts,
+	ethclint *ethclient.Client,
+) (*ArbConnection, error) {
+	conn, err := Dial(url, auth, ethclint)
+	if err != nil {
+		return nil, err
+	}
+	pushRPC, err := rpc.Dial(wsURL)
+	if err != nil {
+		log.Println("Validator subscriptions unavailable, falling back to polling:", err)
+		return conn, nil
+	}
+	conn.pushRPC = pushRPC
+	conn.pushClient = ethclient.NewClient(pushRPC)
+	return conn, nil
+}
+
 func (conn *ArbConnection) getInfoCon() (*ArbInfo, error) {
 	return NewArbInfo(ARB_INFO_ADDRESS, conn)
 }
@@ -251,7 +281,106 @@ func (conn *ArbConnection) SubscribeFilterLogs(
 	query ethereum.FilterQuery,
 	ch chan<- types.Log,
 ) (ethereum.Subscription, error) {
-	return newSubscription(conn, query, ch), nil
+	if conn.pushClient != nil {
+		sub, err := newPushSubscription(ctx, conn, query, ch)
+		if err == nil {
+			return sub, nil
+		}
+		log.Println("Validator log subscription failed, falling back to polling:", err)
+	}
+	return newSubscription(conn, query, ch, 0, 0), nil
+}
+
+// pushSubscription forwards the logs pushed by the validator and switches
+// to polling if the push subscription fails, for example because a
+// notification couldn't be decoded
+type pushSubscription struct {
+	errChan   chan error
+	unsubOnce *sync.Once
+	closeChan chan interface{}
+	wg        sync.WaitGroup
+}
+
+func newPushSubscription(
+	ctx context.Context,
+	conn *ArbConnection,
+	query ethereum.FilterQuery,
+	ch chan<- types.Log,
+) (*pushSubscription, error) {
+	// Polling resumes from the current block if the push subscription fails
+	// before delivering any logs
+	var startBlock hexutil.Uint64
+	if err := conn.pushRPC.CallContext(ctx, &startBlock, "eth_blockNumber"); err != nil {
+		return nil, err
+	}
+	pushChan := make(chan types.Log)
+	push, err := conn.pushClient.SubscribeFilterLogs(ctx, query, pushChan)
+	if err != nil {
+		return nil, err
+	}
+	sub := &pushSubscription{
+		errChan:   make(chan error, 1),
+		unsubOnce: &sync.Once{},
+		closeChan: make(chan interface{}),
+	}
+	sub.wg.Add(1)
+	go func() {
+		defer sub.wg.Done()
+		lastBlock := uint64(startBlock)
+		seenInBlock := uint(0)
+		for {
+			select {
+			case <-sub.closeChan:
+				push.Unsubscribe()
+				return
+			case l := <-pushChan:
+				select {
+				case ch <- l:
+				case <-sub.closeChan:
+					push.Unsubscribe()
+					return
+				}
+				if l.BlockNumber != lastBlock {
+					lastBlock = l.BlockNumber
+					seenInBlock = 0
+				}
+				seenInBlock++
+			case err := <-push.Err():
+				push.Unsubscribe()
+				log.Println("Validator log subscription failed, falling back to polling:", err)
+				sub.poll(newSubscription(conn, query, ch, lastBlock, seenInBlock))
+				return
+			}
+		}
+	}()
+	return sub, nil
+}
+
+// poll waits on the polling subscription that replaced the push
+// subscription and forwards its error
+func (sub *pushSubscription) poll(polling *subscription) {
+	defer polling.Unsubscribe()
+	select {
+	case <-sub.closeChan:
+	case err := <-polling.Err():
+		sub.errChan <- err
+	}
+}
+
+// Unsubscribe cancels the sending of events to the data channel
+// and closes the error channel.
+func (sub *pushSubscription) Unsubscribe() {
+	sub.unsubOnce.Do(func() {
+		close(sub.closeChan)
+		sub.wg.Wait()
+		close(sub.errChan)
+	})
+}
+
+// Err returns the subscription error channel. Only one value will ever be
+// sent. The error channel is closed by Unsubscribe.
+func (sub *pushSubscription) Err() <-chan error {
+	return sub.errChan
 }
 
 const subscriptionPollingInterval = 5 * time.Second
@@ -259,6 +388,9 @@ const subscriptionPollingInterval = 5 * time.Second
 type subscription struct {
 	proxy            ValidatorProxy
 	firstBlockUnseen uint64
+	// skipInFirstBlock is the number of logs in firstBlockUnseen that have
+	// already been delivered
+	skipInFirstBlock uint
 	logChan          chan<- types.Log
 	errChan          chan error
 	address          common.Address
@@ -340,11 +472,18 @@ func _decodeLogInfo(ins *validatorserver.LogInfo) (*types.Log, error) {
 	return outs, nil
 }
 
-func newSubscription(conn *ArbConnection, query ethereum.FilterQuery, ch chan<- types.Log) *subscription {
+func newSubscription(
+	conn *ArbConnection,
+	query ethereum.FilterQuery,
+	ch chan<- types.Log,
+	firstBlockUnseen uint64,
+	skipInFirstBlock uint,
+) *subscription {
 	address, topics := _extractAddrTopics(query)
 	sub := &subscription{
 		conn.proxy,
-		0,
+		firstBlockUnseen,
+		skipInFirstBlock,
 		ch,
 		make(chan error, 1),
 		address,
@@ -355,8 +494,9 @@ func newSubscription(conn *ArbConnection, query ethereum.FilterQuery, ch chan<-
 	}
 	sub.wg.Add(1)
 	go func() {
-		defer sub.wg.Done()
+		// Unsubscribe waits on wg, so it has to run after Done
 		defer sub.Unsubscribe()
+		defer sub.wg.Done()
 		ticker := time.NewTicker(subscriptionPollingInterval)
 		defer ticker.Stop()
 		for {
@@ -384,6 +524,10 @@ func newSubscription(conn *ArbConnection, query ethereum.FilterQuery, ch chan<-
 					if outs.BlockNumber < sub.firstBlockUnseen {
 						ok = false
 					}
+					if ok && outs.BlockNumber == sub.firstBlockUnseen && sub.skipInFirstBlock > 0 {
+						sub.skipInFirstBlock--
+						ok = false
+					}
 					if ok {
 						sub.logChan <- *outs
 						if sub.firstBlockUnseen <= outs.BlockNumber {
@@ -21,15 +21,15 @@ import (
 	"flag"
 	"log"
 	"os"
+	"path/filepath"
 
 	"github.com/gorilla/rpc"
 	"github.com/gorilla/rpc/json"
 
-	"github.com/ethereum/go-ethereum/ethclient"
-
 	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/arbbridge"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/ethbridge"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/metrics"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/utils"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator/txaggregator"
 )
@@ -37,6 +37,13 @@ import (
 func main() {
 	fs := flag.NewFlagSet("", flag.ContinueOnError)
 	walletArgs := utils.AddFlags(fs)
+	ethClientArgs := utils.AddEthClientFlags(fs)
+	batchArgs := txaggregator.AddBatchPolicyFlags(fs)
+	metricsPort := fs.String(
+		"metrics-port",
+		"",
+		"metrics-port=Port",
+	)
 
 	err := fs.Parse(os.Args[1:])
 	if err != nil {
@@ -45,24 +52,39 @@ func main() {
 
 	if fs.NArg() != 3 {
 		log.Fatalf(
-			"usage: arb-tx-aggregator %v %v",
+			"usage: arb-tx-aggregator %v %v %v",
 			utils.WalletArgsString,
+			utils.EthClientArgsString,
 			utils.RollupArgsString,
 		)
 	}
 
 	rollupArgs := utils.ParseRollupCommand(fs, 0)
 
-	auth, err := utils.GetKeystore(rollupArgs.ValidatorFolder, walletArgs, fs)
+	if *metricsPort != "" {
+		go func() {
+			log.Fatal(metrics.LaunchMetrics(*metricsPort))
+		}()
+	}
+
+	batchConfig, err := txaggregator.ParseBatchPolicyConfig(batchArgs, fs)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ethclint, err := utils.DialEthClient(context.Background(), rollupArgs.EthURL, ethClientArgs)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	ethclint, err := ethclient.Dial(rollupArgs.EthURL)
+	auth, err := utils.GetTransactAuth(context.Background(), rollupArgs.ValidatorFolder, walletArgs, fs, ethclint)
 	if err != nil {
 		log.Fatal(err)
 	}
 	client := ethbridge.NewEthAuthClient(ethclint, auth)
+	if err := utils.ConfigureGasPricing(walletArgs, ethclint, client); err != nil {
+		log.Fatal(err)
+	}
 
 	if err := arbbridge.WaitForNonZeroBalance(
 		context.Background(),
@@ -85,10 +107,25 @@ func main() {
 		log.Fatal(err)
 	}
 
-	server := txaggregator.NewRPCServer(
+	policy, err := txaggregator.NewThresholdPolicy(
+		batchConfig,
+		ethclint.SuggestGasPrice,
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	server, err := txaggregator.NewRPCServer(
 		context.Background(),
-		globalInbox, rollupArgs.Address,
+		client,
+		globalInbox,
+		rollupArgs.Address,
+		policy,
+		filepath.Join(rollupArgs.ValidatorFolder, "aggregator_tx_log"),
 	)
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	s := rpc.NewServer()
 	s.RegisterCodec(
@@ -0,0 +1,160 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package txaggregator
+
+import (
+	"time"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/message"
+)
+
+const (
+	// maxSubmitAttempts is the number of batches a transaction can be part of
+	// before it is dropped
+	maxSubmitAttempts = 5
+
+	// droppedTxTimeout is how long a submitted batch can go unseen by the L1
+	// node before it is considered dropped
+	droppedTxTimeout = time.Minute
+
+	minRetryBackoff = time.Second
+	maxRetryBackoff = time.Minute * 5
+
+	// maxBatchHistory is the number of finished batches kept for reporting
+	maxBatchHistory = 100
+)
+
+type batchState int
+
+const (
+	batchSubmitted batchState = iota
+	batchConfirmed
+	batchFailed
+)
+
+func (s batchState) String() string {
+	switch s {
+	case batchSubmitted:
+		return "submitted"
+	case batchConfirmed:
+		return "confirmed"
+	case batchFailed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
+
+type batch struct {
+	id        uint64
+	txes      []*pooledTx
+	l1TxHash  common.Hash
+	state     batchState
+	submitted time.Time
+	err       string
+}
+
+func (b *batch) batchTxes() []message.BatchTx {
+	txes := make([]message.BatchTx, 0, len(b.txes))
+	for _, ptx := range b.txes {
+		txes = append(txes, ptx.tx)
+	}
+	return txes
+}
+
+// batchTracker keeps track of batches which have been sent to the L1 chain
+// until their receipt lands, along with recently finished batches and the
+// backoff applied after failed submissions
+type batchTracker struct {
+	nextID   uint64
+	inFlight []*batch
+	history  []*batch
+
+	backoff time.Duration
+	retryAt time.Time
+}
+
+func newBatchTracker() *batchTracker {
+	return &batchTracker{}
+}
+
+func (t *batchTracker) newBatch(txes []*pooledTx) *batch {
+	for _, ptx := range txes {
+		ptx.attempts++
+	}
+	b := &batch{id: t.nextID, txes: txes}
+	t.nextID++
+	return b
+}
+
+func (t *batchTracker) canSubmit(now time.Time) bool {
+	return !now.Before(t.retryAt)
+}
+
+// submitted records that the batch was accepted by the L1 node
+func (t *batchTracker) submitted(b *batch, l1TxHash common.Hash, now time.Time) {
+	b.l1TxHash = l1TxHash
+	b.state = batchSubmitted
+	b.submitted = now
+	t.inFlight = append(t.inFlight, b)
+	t.backoff = 0
+}
+
+// submitFailed records that the batch couldn't be sent and delays the next
+// submission with exponential backoff
+func (t *batchTracker) submitFailed(b *batch, err error, now time.Time) {
+	if t.backoff == 0 {
+		t.backoff = minRetryBackoff
+	} else {
+		t.backoff *= 2
+		if t.backoff > maxRetryBackoff {
+			t.backoff = maxRetryBackoff
+		}
+	}
+	t.retryAt = now.Add(t.backoff)
+	t.finish(b, batchFailed, err.Error())
+}
+
+// finish moves the batch into the history
+func (t *batchTracker) finish(b *batch, state batchState, err string) {
+	b.state = state
+	b.err = err
+	for i, inFlight := range t.inFlight {
+		if inFlight == b {
+			t.inFlight = append(t.inFlight[:i], t.inFlight[i+1:]...)
+			break
+		}
+	}
+	t.history = append(t.history, b)
+	if len(t.history) > maxBatchHistory {
+		t.history = t.history[len(t.history)-maxBatchHistory:]
+	}
+}
+
+// retryable returns the transactions in a failed batch which haven't used up
+// their submission attempts
+func retryable(b *batch) []*pooledTx {
+	txes := make([]*pooledTx, 0, len(b.txes))
+	for _, ptx := range b.txes {
+		if ptx.attempts >= maxSubmitAttempts {
+			continue
+		}
+		txes = append(txes, ptx)
+	}
+	return txes
+}
@@ -0,0 +1,60 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package txaggregator
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+)
+
+func TestBatchTrackerBackoff(t *testing.T) {
+	tracker := newBatchTracker()
+	now := time.Now()
+	tracker.submitFailed(tracker.newBatch(nil), errors.New("failed"), now)
+	if tracker.canSubmit(now) {
+		t.Error("expected submission to be delayed after failure")
+	}
+	tracker.submitFailed(tracker.newBatch(nil), errors.New("failed"), now)
+	if tracker.backoff != 2*minRetryBackoff {
+		t.Error("expected backoff to double but got", tracker.backoff)
+	}
+	tracker.submitted(tracker.newBatch(nil), common.Hash{1}, now)
+	if tracker.backoff != 0 || len(tracker.inFlight) != 1 {
+		t.Error("expected successful submission to reset backoff")
+	}
+	if len(tracker.history) != 2 {
+		t.Error("expected failed batches to be recorded but got", len(tracker.history))
+	}
+}
+
+func TestRetryableDropsExhaustedTxes(t *testing.T) {
+	tracker := newBatchTracker()
+	ptx := &pooledTx{tx: generateTestBatchTx(0, 0)}
+	var b *batch
+	for i := 0; i < maxSubmitAttempts; i++ {
+		b = tracker.newBatch([]*pooledTx{ptx})
+		if i < maxSubmitAttempts-1 && len(retryable(b)) != 1 {
+			t.Fatal("expected tx to be retried after attempt", i+1)
+		}
+	}
+	if len(retryable(b)) != 0 {
+		t.Error("expected tx to be dropped after", maxSubmitAttempts, "attempts")
+	}
+}
@@ -0,0 +1,271 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package txaggregator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io/ioutil"
+	"log"
+	"math/big"
+	"time"
+)
+
+// PendingBatch summarizes the transactions which are ready to be batched
+type PendingBatch struct {
+	TransactionCount int
+	CalldataBytes    int
+	OldestReceived   time.Time
+}
+
+// BatchPolicy decides when the aggregator submits a batch and how large each
+// batch can be
+type BatchPolicy interface {
+	// ShouldFlush reports whether a batch should be submitted now
+	ShouldFlush(ctx context.Context, pending PendingBatch) bool
+
+	// MaxTransactions is the maximum number of transactions in a batch
+	MaxTransactions() int
+
+	// MaxBytes is the maximum size of a batch's calldata, or 0 if there is
+	// no limit
+	MaxBytes() int
+
+	// CheckInterval is how often ShouldFlush is consulted
+	CheckInterval() time.Duration
+}
+
+// GasPriceFunc returns the current L1 gas price
+type GasPriceFunc func(ctx context.Context) (*big.Int, error)
+
+// BatchPolicyConfig configures a ThresholdPolicy. A batch is flushed as soon
+// as any of the enabled thresholds is reached
+type BatchPolicyConfig struct {
+	// MaxTransactions flushes once this many transactions are ready and
+	// limits the size of each batch
+	MaxTransactions int `json:"maxTransactions"`
+
+	// MaxBytes flushes once the ready transactions reach this much calldata
+	// and limits the size of each batch. 0 disables the limit
+	MaxBytes int `json:"maxBytes"`
+
+	// MaxAge flushes once the oldest ready transaction has waited this long.
+	// 0 disables the limit
+	MaxAge Duration `json:"maxAge"`
+
+	// FlushGasPrice flushes any ready transactions while the L1 gas price is
+	// at or below this price in wei. nil disables the threshold
+	FlushGasPrice *big.Int `json:"flushGasPrice"`
+
+	// CheckInterval is how often the thresholds are checked
+	CheckInterval Duration `json:"checkInterval"`
+}
+
+// Duration is a time.Duration which is written in JSON as a string such as
+// "5s"
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return err
+	}
+	parsed, err := time.ParseDuration(str)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// DefaultBatchPolicyConfig submits up to 200 transactions at a time, at most
+// 5 seconds after they were received
+func DefaultBatchPolicyConfig() BatchPolicyConfig {
+	return BatchPolicyConfig{
+		MaxTransactions: 200,
+		MaxAge:          Duration(time.Second * 5),
+		CheckInterval:   Duration(time.Second),
+	}
+}
+
+func (c BatchPolicyConfig) validate() error {
+	if c.MaxTransactions <= 0 {
+		return errors.New("max batch transactions must be positive")
+	}
+	if c.MaxBytes < 0 {
+		return errors.New("max batch bytes can't be negative")
+	}
+	if c.CheckInterval <= 0 {
+		return errors.New("batch check interval must be positive")
+	}
+	return nil
+}
+
+// ThresholdPolicy is a BatchPolicy which flushes when the ready transactions
+// exceed a count, calldata size or age threshold, or when the L1 gas price is
+// cheap enough
+type ThresholdPolicy struct {
+	config   BatchPolicyConfig
+	gasPrice GasPriceFunc
+}
+
+// NewThresholdPolicy creates a policy from the given config. gasPrice is
+// only used if the config sets FlushGasPrice
+func NewThresholdPolicy(
+	config BatchPolicyConfig,
+	gasPrice GasPriceFunc,
+) (*ThresholdPolicy, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	if config.FlushGasPrice != nil && gasPrice == nil {
+		return nil, errors.New("flush gas price requires a gas price source")
+	}
+	return &ThresholdPolicy{config: config, gasPrice: gasPrice}, nil
+}
+
+func (p *ThresholdPolicy) ShouldFlush(ctx context.Context, pending PendingBatch) bool {
+	if pending.TransactionCount == 0 {
+		return false
+	}
+	if pending.TransactionCount >= p.config.MaxTransactions {
+		return true
+	}
+	if p.config.MaxBytes > 0 && pending.CalldataBytes >= p.config.MaxBytes {
+		return true
+	}
+	if p.config.MaxAge > 0 && time.Since(pending.OldestReceived) >= time.Duration(p.config.MaxAge) {
+		return true
+	}
+	if p.config.FlushGasPrice != nil {
+		price, err := p.gasPrice(ctx)
+		if err != nil {
+			log.Println("Failed to get gas price for batch policy", err)
+			return false
+		}
+		return price.Cmp(p.config.FlushGasPrice) <= 0
+	}
+	return false
+}
+
+func (p *ThresholdPolicy) MaxTransactions() int {
+	return p.config.MaxTransactions
+}
+
+func (p *ThresholdPolicy) MaxBytes() int {
+	return p.config.MaxBytes
+}
+
+func (p *ThresholdPolicy) CheckInterval() time.Duration {
+	return time.Duration(p.config.CheckInterval)
+}
+
+type BatchPolicyFlags struct {
+	config          *string
+	maxTransactions *int
+	maxBytes        *int
+	maxAge          *time.Duration
+	flushGasPrice   *float64
+	checkInterval   *time.Duration
+}
+
+// AddBatchPolicyFlags adds the flags used to configure the batch policy
+func AddBatchPolicyFlags(fs *flag.FlagSet) BatchPolicyFlags {
+	defaults := DefaultBatchPolicyConfig()
+	return BatchPolicyFlags{
+		config: fs.String(
+			"batch-config",
+			"",
+			"batch-config=path/to/config.json",
+		),
+		maxTransactions: fs.Int(
+			"batch-max-txs",
+			defaults.MaxTransactions,
+			"batch-max-txs=Int",
+		),
+		maxBytes: fs.Int(
+			"batch-max-bytes",
+			defaults.MaxBytes,
+			"batch-max-bytes=Int",
+		),
+		maxAge: fs.Duration(
+			"batch-max-age",
+			time.Duration(defaults.MaxAge),
+			"batch-max-age=Duration",
+		),
+		flushGasPrice: fs.Float64(
+			"batch-flush-gasprice",
+			0,
+			"batch-flush-gasprice=FloatInGwei",
+		),
+		checkInterval: fs.Duration(
+			"batch-check-interval",
+			time.Duration(defaults.CheckInterval),
+			"batch-check-interval=Duration",
+		),
+	}
+}
+
+// ParseBatchPolicyConfig builds the batch policy config from the defaults,
+// then the config file if one was given, then any flags which were set
+// explicitly
+func ParseBatchPolicyConfig(
+	args BatchPolicyFlags,
+	fs *flag.FlagSet,
+) (BatchPolicyConfig, error) {
+	config := DefaultBatchPolicyConfig()
+	if *args.config != "" {
+		data, err := ioutil.ReadFile(*args.config)
+		if err != nil {
+			return config, err
+		}
+		if err := json.Unmarshal(data, &config); err != nil {
+			return config, err
+		}
+	}
+
+	var err error
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "batch-max-txs":
+			config.MaxTransactions = *args.maxTransactions
+		case "batch-max-bytes":
+			config.MaxBytes = *args.maxBytes
+		case "batch-max-age":
+			config.MaxAge = Duration(*args.maxAge)
+		case "batch-flush-gasprice":
+			if *args.flushGasPrice < 0 {
+				err = errors.New("batch flush gas price can't be negative")
+				return
+			}
+			gasPriceAsFloat := 1e9 * (*args.flushGasPrice)
+			config.FlushGasPrice, _ = big.NewFloat(gasPriceAsFloat).Int(nil)
+		case "batch-check-interval":
+			config.CheckInterval = Duration(*args.checkInterval)
+		}
+	})
+	if err != nil {
+		return config, err
+	}
+	return config, config.validate()
+}
@@ -0,0 +1,130 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package txaggregator
+
+import (
+	"context"
+	"flag"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestThresholdPolicy(t *testing.T) {
+	config := DefaultBatchPolicyConfig()
+	config.MaxTransactions = 10
+	config.MaxBytes = 1000
+	config.MaxAge = Duration(time.Minute)
+	config.FlushGasPrice = big.NewInt(5)
+
+	gasPrice := big.NewInt(10)
+	policy, err := NewThresholdPolicy(config, func(ctx context.Context) (*big.Int, error) {
+		return gasPrice, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	fresh := PendingBatch{TransactionCount: 1, CalldataBytes: 200, OldestReceived: time.Now()}
+	if policy.ShouldFlush(ctx, PendingBatch{}) {
+		t.Error("shouldn't flush an empty batch")
+	}
+	if policy.ShouldFlush(ctx, fresh) {
+		t.Error("shouldn't flush before any threshold is reached")
+	}
+	full := fresh
+	full.TransactionCount = 10
+	if !policy.ShouldFlush(ctx, full) {
+		t.Error("should flush at max transactions")
+	}
+	large := fresh
+	large.CalldataBytes = 1000
+	if !policy.ShouldFlush(ctx, large) {
+		t.Error("should flush at max bytes")
+	}
+	old := fresh
+	old.OldestReceived = time.Now().Add(-time.Hour)
+	if !policy.ShouldFlush(ctx, old) {
+		t.Error("should flush at max age")
+	}
+	gasPrice = big.NewInt(5)
+	if !policy.ShouldFlush(ctx, fresh) {
+		t.Error("should flush when gas is cheap")
+	}
+}
+
+func TestThresholdPolicyNoMaxAge(t *testing.T) {
+	config := DefaultBatchPolicyConfig()
+	config.MaxTransactions = 10
+	config.MaxAge = 0
+	policy, err := NewThresholdPolicy(config, func(ctx context.Context) (*big.Int, error) {
+		return big.NewInt(10), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	old := PendingBatch{TransactionCount: 1, CalldataBytes: 200, OldestReceived: time.Now().Add(-time.Hour)}
+	if policy.ShouldFlush(context.Background(), old) {
+		t.Error("shouldn't flush on age when max age is zero")
+	}
+	old.TransactionCount = 10
+	if !policy.ShouldFlush(context.Background(), old) {
+		t.Error("should still flush at max transactions")
+	}
+}
+
+func TestParseBatchPolicyConfig(t *testing.T) {
+	configFile, err := ioutil.TempFile("", "batchconfig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(configFile.Name())
+	if _, err := configFile.WriteString(`{"maxTransactions": 50, "maxAge": "30s"}`); err != nil {
+		t.Fatal(err)
+	}
+	configFile.Close()
+
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	args := AddBatchPolicyFlags(fs)
+	if err := fs.Parse([]string{
+		"-batch-config", configFile.Name(),
+		"-batch-max-txs", "20",
+		"-batch-flush-gasprice", "2",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	config, err := ParseBatchPolicyConfig(args, fs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.MaxTransactions != 20 {
+		t.Error("expected flag to override config file but got", config.MaxTransactions)
+	}
+	if time.Duration(config.MaxAge) != 30*time.Second {
+		t.Error("expected max age from config file but got", time.Duration(config.MaxAge))
+	}
+	if config.FlushGasPrice.Cmp(big.NewInt(2e9)) != 0 {
+		t.Error("expected gas price in wei but got", config.FlushGasPrice)
+	}
+	if config.CheckInterval != DefaultBatchPolicyConfig().CheckInterval {
+		t.Error("expected default check interval")
+	}
+}
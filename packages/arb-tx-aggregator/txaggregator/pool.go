@@ -0,0 +1,303 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package txaggregator
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/message"
+)
+
+// maxQueuedPerSender limits the number of transactions a single sender can
+// have waiting in the pool
+const maxQueuedPerSender = 64
+
+var (
+	errNonceTooLow     = errors.New("sequence number already included in a batch")
+	errDuplicateTx     = errors.New("transaction already in pool")
+	errSenderQueueFull = errors.New("too many pending transactions from sender")
+)
+
+type pooledTx struct {
+	tx       message.BatchTx
+	sender   common.Address
+	hash     common.Hash
+	arrival  uint64
+	received time.Time
+	attempts int
+
+	// logID identifies the transaction's record in the server's txLog
+	logID uint64
+}
+
+// accountQueue holds the pooled transactions of a single sender ordered by
+// sequence number
+type accountQueue struct {
+	// nextSeqNum is the sequence number of the next transaction from this
+	// sender that can be included in a batch
+	nextSeqNum *big.Int
+	txes       []*pooledTx
+}
+
+func (q *accountQueue) find(seqNum *big.Int) (int, bool) {
+	i := sort.Search(len(q.txes), func(i int) bool {
+		return q.txes[i].tx.SeqNum.Cmp(seqNum) >= 0
+	})
+	return i, i < len(q.txes) && q.txes[i].tx.SeqNum.Cmp(seqNum) == 0
+}
+
+// readyCount returns the number of transactions at the front of the queue
+// which can be batched. Requeued transactions below nextSeqNum were already
+// released in order once so they are always ready, followed by transactions
+// with consecutive sequence numbers starting at nextSeqNum
+func (q *accountQueue) readyCount() int {
+	expected := new(big.Int).Set(q.nextSeqNum)
+	count := 0
+	for _, ptx := range q.txes {
+		if ptx.tx.SeqNum.Cmp(q.nextSeqNum) < 0 {
+			count++
+			continue
+		}
+		if ptx.tx.SeqNum.Cmp(expected) != 0 {
+			break
+		}
+		expected.Add(expected, big.NewInt(1))
+		count++
+	}
+	return count
+}
+
+// txPool holds transactions waiting to be included in a batch. Transactions
+// are grouped by sender and only released for batching in sequence number
+// order, so transactions after a gap are held back until the gap is filled.
+//
+// The aggregator has no view of the chain state, so the first sequence number
+// seen from a sender is treated as that sender's next sequence number
+type txPool struct {
+	accounts   map[common.Address]*accountQueue
+	arrivalSeq uint64
+}
+
+func newTxPool() *txPool {
+	return &txPool{
+		accounts: make(map[common.Address]*accountQueue),
+	}
+}
+
+// Add inserts the transaction into the pool. A transaction with the same
+// sequence number as one which is still waiting in the pool replaces it, in
+// which case the replaced transaction is returned
+func (p *txPool) Add(ptx *pooledTx) (*pooledTx, error) {
+	tx := ptx.tx
+	q, ok := p.accounts[ptx.sender]
+	if !ok {
+		q = &accountQueue{nextSeqNum: new(big.Int).Set(tx.SeqNum)}
+		p.accounts[ptx.sender] = q
+	}
+
+	i, found := q.find(tx.SeqNum)
+	if !found && tx.SeqNum.Cmp(q.nextSeqNum) < 0 {
+		return nil, errNonceTooLow
+	}
+	if ptx.received.IsZero() {
+		ptx.received = time.Now()
+	}
+	if found {
+		if q.txes[i].tx.Sig == tx.Sig && bytes.Equal(q.txes[i].tx.Data, tx.Data) {
+			return nil, errDuplicateTx
+		}
+		replaced := q.txes[i]
+		p.arrivalSeq++
+		ptx.arrival = p.arrivalSeq
+		q.txes[i] = ptx
+		return replaced, nil
+	}
+	if len(q.txes) >= maxQueuedPerSender {
+		return nil, errSenderQueueFull
+	}
+
+	p.arrivalSeq++
+	ptx.arrival = p.arrivalSeq
+	q.txes = append(q.txes, nil)
+	copy(q.txes[i+1:], q.txes[i:])
+	q.txes[i] = ptx
+	return nil, nil
+}
+
+// ReadyCount returns the number of transactions which could be included in
+// the next batch
+func (p *txPool) ReadyCount() int {
+	count := 0
+	for _, q := range p.accounts {
+		count += q.readyCount()
+	}
+	return count
+}
+
+// QueuedCount returns the total number of transactions in the pool including
+// those which are waiting for a gap in their sender's sequence numbers
+func (p *txPool) QueuedCount() int {
+	count := 0
+	for _, q := range p.accounts {
+		count += len(q.txes)
+	}
+	return count
+}
+
+// ReadyStats summarizes the transactions which could be included in the next
+// batch
+func (p *txPool) ReadyStats() PendingBatch {
+	var stats PendingBatch
+	for _, q := range p.accounts {
+		for _, ptx := range q.txes[:q.readyCount()] {
+			stats.TransactionCount++
+			stats.CalldataBytes += ptx.encodedLength()
+			if stats.OldestReceived.IsZero() || ptx.received.Before(stats.OldestReceived) {
+				stats.OldestReceived = ptx.received
+			}
+		}
+	}
+	return stats
+}
+
+func (ptx *pooledTx) encodedLength() int {
+	return message.DataOffset + len(ptx.tx.Data)
+}
+
+// PopBatch removes up to maxTxes ready transactions from the pool, stopping
+// early if the next transaction would take the encoded batch over maxBytes.
+// A maxBytes of 0 means there is no limit. Transactions from each sender
+// stay in sequence number order and senders are interleaved in the order
+// their transactions arrived
+func (p *txPool) PopBatch(maxTxes int, maxBytes int) []*pooledTx {
+	batch := make([]*pooledTx, 0)
+	batchBytes := 0
+	for len(batch) < maxTxes {
+		var next *accountQueue
+		for _, q := range p.accounts {
+			if q.readyCount() == 0 {
+				continue
+			}
+			if next == nil || q.txes[0].arrival < next.txes[0].arrival {
+				next = q
+			}
+		}
+		if next == nil {
+			break
+		}
+		ptx := next.txes[0]
+		if maxBytes > 0 && len(batch) > 0 && batchBytes+ptx.encodedLength() > maxBytes {
+			break
+		}
+		batchBytes += ptx.encodedLength()
+		batch = append(batch, ptx)
+		next.txes = next.txes[1:]
+		if ptx.tx.SeqNum.Cmp(next.nextSeqNum) >= 0 {
+			next.nextSeqNum = new(big.Int).Add(ptx.tx.SeqNum, big.NewInt(1))
+		}
+	}
+	return batch
+}
+
+// Requeue returns transactions from a batch which didn't make it onto the
+// chain to the pool so that they're included in a later batch. If the sender
+// has since queued a replacement for one of them, the replacement is kept
+func (p *txPool) Requeue(txes []*pooledTx) {
+	for _, ptx := range txes {
+		q, ok := p.accounts[ptx.sender]
+		if !ok {
+			q = &accountQueue{nextSeqNum: new(big.Int).Set(ptx.tx.SeqNum)}
+			p.accounts[ptx.sender] = q
+		}
+		i, found := q.find(ptx.tx.SeqNum)
+		if found {
+			continue
+		}
+		q.txes = append(q.txes, nil)
+		copy(q.txes[i+1:], q.txes[i:])
+		q.txes[i] = ptx
+	}
+}
+
+// Pending returns all pooled transactions, optionally restricted to a single
+// sender. For each transaction it also reports whether it is ready to be
+// batched or held back by a sequence number gap
+func (p *txPool) Pending(sender *common.Address) ([]*pooledTx, []bool) {
+	senders := make([]common.Address, 0, len(p.accounts))
+	if sender != nil {
+		if _, ok := p.accounts[*sender]; ok {
+			senders = append(senders, *sender)
+		}
+	} else {
+		for s := range p.accounts {
+			senders = append(senders, s)
+		}
+		sort.Slice(senders, func(i, j int) bool {
+			return bytes.Compare(senders[i][:], senders[j][:]) < 0
+		})
+	}
+
+	txes := make([]*pooledTx, 0)
+	ready := make([]bool, 0)
+	for _, s := range senders {
+		q := p.accounts[s]
+		readyCount := q.readyCount()
+		for i, ptx := range q.txes {
+			txes = append(txes, ptx)
+			ready = append(ready, i < readyCount)
+		}
+	}
+	return txes, ready
+}
+
+// Dropped records that txes, which were taken from the pool for a batch,
+// will never be included. Their senders' later transactions are held back
+// until the dropped sequence numbers are sent again
+func (p *txPool) Dropped(txes []*pooledTx) {
+	for _, ptx := range txes {
+		q, ok := p.accounts[ptx.sender]
+		if !ok {
+			continue
+		}
+		if ptx.tx.SeqNum.Cmp(q.nextSeqNum) < 0 {
+			q.nextSeqNum = new(big.Int).Set(ptx.tx.SeqNum)
+		}
+	}
+}
+
+// PendingSeqNum returns the sequence number that the sender's next
+// transaction should use given the transactions already in the pool. It
+// follows the highest sequence number which has been released or is ready to
+// be, so requeued transactions aren't counted twice
+func (p *txPool) PendingSeqNum(sender common.Address) (*big.Int, bool) {
+	q, ok := p.accounts[sender]
+	if !ok {
+		return nil, false
+	}
+	next := new(big.Int).Set(q.nextSeqNum)
+	for _, ptx := range q.txes[:q.readyCount()] {
+		if ptx.tx.SeqNum.Cmp(next) >= 0 {
+			next.Add(ptx.tx.SeqNum, big.NewInt(1))
+		}
+	}
+	return next, true
+}
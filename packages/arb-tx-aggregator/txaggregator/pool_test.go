@@ -0,0 +1,185 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package txaggregator
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/message"
+)
+
+func generateTestBatchTx(seqNum int64, sigByte byte) message.BatchTx {
+	var sig [signatureLength]byte
+	sig[0] = sigByte
+	return message.BatchTx{
+		To:     common.Address{5},
+		SeqNum: big.NewInt(seqNum),
+		Value:  big.NewInt(0),
+		Data:   []byte{1, 2, 3},
+		Sig:    sig,
+	}
+}
+
+func TestPoolOrdersBySeqNum(t *testing.T) {
+	pool := newTxPool()
+	sender := common.Address{1}
+	for _, seqNum := range []int64{3, 5, 4} {
+		if _, err := pool.Add(&pooledTx{sender: sender, tx: generateTestBatchTx(seqNum, 0)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if pool.ReadyCount() != 3 {
+		t.Fatal("expected 3 ready txes but got", pool.ReadyCount())
+	}
+	batch := pool.PopBatch(200, 0)
+	for i, tx := range batch {
+		if tx.tx.SeqNum.Int64() != int64(i+3) {
+			t.Error("batch out of order at", i, "got seq num", tx.tx.SeqNum)
+		}
+	}
+	if _, err := pool.Add(&pooledTx{sender: sender, tx: generateTestBatchTx(4, 0)}); err != errNonceTooLow {
+		t.Error("expected nonce too low error but got", err)
+	}
+}
+
+func TestPoolHoldsBackGaps(t *testing.T) {
+	pool := newTxPool()
+	sender := common.Address{1}
+	if _, err := pool.Add(&pooledTx{sender: sender, tx: generateTestBatchTx(0, 0)}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pool.Add(&pooledTx{sender: sender, tx: generateTestBatchTx(2, 0)}); err != nil {
+		t.Fatal(err)
+	}
+	if batch := pool.PopBatch(200, 0); len(batch) != 1 {
+		t.Fatal("expected tx after gap to be held back")
+	}
+	nonce, found := pool.PendingSeqNum(sender)
+	if !found || nonce.Int64() != 1 {
+		t.Error("expected pending nonce 1 but got", nonce)
+	}
+	if _, err := pool.Add(&pooledTx{sender: sender, tx: generateTestBatchTx(1, 0)}); err != nil {
+		t.Fatal(err)
+	}
+	if batch := pool.PopBatch(200, 0); len(batch) != 2 {
+		t.Error("expected filled gap to release both txes but got", len(batch))
+	}
+}
+
+func TestPoolRequeue(t *testing.T) {
+	pool := newTxPool()
+	sender := common.Address{1}
+	for _, seqNum := range []int64{0, 1} {
+		if _, err := pool.Add(&pooledTx{sender: sender, tx: generateTestBatchTx(seqNum, 0)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	failed := pool.PopBatch(1, 0)
+	if _, err := pool.Add(&pooledTx{sender: sender, tx: generateTestBatchTx(2, 0)}); err != nil {
+		t.Fatal(err)
+	}
+	pool.Requeue(failed)
+	batch := pool.PopBatch(200, 0)
+	if len(batch) != 3 {
+		t.Fatal("expected requeued tx to be ready but got", len(batch), "txes")
+	}
+	for i, ptx := range batch {
+		if ptx.tx.SeqNum.Int64() != int64(i) {
+			t.Error("batch out of order at", i, "got seq num", ptx.tx.SeqNum)
+		}
+	}
+}
+
+func TestPoolRequeuePendingSeqNum(t *testing.T) {
+	pool := newTxPool()
+	sender := common.Address{1}
+	for _, seqNum := range []int64{0, 1, 2} {
+		if _, err := pool.Add(&pooledTx{sender: sender, tx: generateTestBatchTx(seqNum, 0)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	pool.Requeue(pool.PopBatch(200, 0))
+	if pool.ReadyCount() != 3 {
+		t.Error("expected requeued txes to be ready but got", pool.ReadyCount())
+	}
+	nonce, found := pool.PendingSeqNum(sender)
+	if !found || nonce.Int64() != 3 {
+		t.Error("expected pending nonce 3 but got", nonce)
+	}
+}
+
+func TestPoolDropped(t *testing.T) {
+	pool := newTxPool()
+	sender := common.Address{1}
+	for _, seqNum := range []int64{0, 1, 2} {
+		if _, err := pool.Add(&pooledTx{sender: sender, tx: generateTestBatchTx(seqNum, 0)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	failed := pool.PopBatch(200, 0)
+	if _, err := pool.Add(&pooledTx{sender: sender, tx: generateTestBatchTx(3, 0)}); err != nil {
+		t.Fatal(err)
+	}
+	// The tx with sequence number 1 is dropped and the others are requeued
+	pool.Dropped(failed[1:2])
+	pool.Requeue([]*pooledTx{failed[0], failed[2]})
+
+	nonce, found := pool.PendingSeqNum(sender)
+	if !found || nonce.Int64() != 1 {
+		t.Error("expected pending nonce to go back to 1 but got", nonce)
+	}
+	batch := pool.PopBatch(200, 0)
+	if len(batch) != 1 || batch[0].tx.SeqNum.Int64() != 0 {
+		t.Fatal("expected txes after the dropped one to be held back but got", len(batch))
+	}
+	if _, err := pool.Add(&pooledTx{sender: sender, tx: generateTestBatchTx(1, 0)}); err != nil {
+		t.Fatal("resending the dropped tx failed", err)
+	}
+	batch = pool.PopBatch(200, 0)
+	if len(batch) != 3 {
+		t.Fatal("expected resent tx to release the held back txes but got", len(batch))
+	}
+	for i, ptx := range batch {
+		if ptx.tx.SeqNum.Int64() != int64(i+1) {
+			t.Error("batch out of order at", i, "got seq num", ptx.tx.SeqNum)
+		}
+	}
+}
+
+func TestPoolReplacement(t *testing.T) {
+	pool := newTxPool()
+	sender := common.Address{1}
+	if _, err := pool.Add(&pooledTx{sender: sender, tx: generateTestBatchTx(0, 1)}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pool.Add(&pooledTx{sender: sender, tx: generateTestBatchTx(0, 1)}); err != errDuplicateTx {
+		t.Error("expected duplicate error but got", err)
+	}
+	replaced, err := pool.Add(&pooledTx{sender: sender, tx: generateTestBatchTx(0, 2)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replaced == nil || replaced.tx.Sig[0] != 1 {
+		t.Error("expected tx to be replaced")
+	}
+	batch := pool.PopBatch(200, 0)
+	if len(batch) != 1 || batch[0].tx.Sig[0] != 2 {
+		t.Error("expected batch to contain the replacement tx")
+	}
+}
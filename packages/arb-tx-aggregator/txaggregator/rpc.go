@@ -30,8 +30,26 @@ type RPCServer struct {
 	*Server
 }
 
-func NewRPCServer(ctx context.Context, globalInbox arbbridge.GlobalInbox, rollupAddress common.Address) *RPCServer {
-	return &RPCServer{Server: NewServer(ctx, globalInbox, rollupAddress)}
+func NewRPCServer(
+	ctx context.Context,
+	client arbbridge.ArbClient,
+	globalInbox arbbridge.GlobalInbox,
+	rollupAddress common.Address,
+	policy BatchPolicy,
+	txLogPath string,
+) (*RPCServer, error) {
+	server, err := NewServer(
+		ctx,
+		client,
+		globalInbox,
+		rollupAddress,
+		policy,
+		txLogPath,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &RPCServer{Server: server}, nil
 }
 
 // SendTransaction converts the server implementation of SendTransaction to the
@@ -39,7 +57,53 @@ func NewRPCServer(ctx context.Context, globalInbox arbbridge.GlobalInbox, rollup
 func (m *RPCServer) SendTransaction(r *http.Request, args *SendTransactionArgs, reply *SendTransactionReply) error {
 	ret, err := m.Server.SendTransaction(context.Background(), args)
 	if ret != nil {
-		*reply = *ret
+		reply.Accepted = ret.Accepted
+		reply.TxHash = ret.TxHash
+	}
+	return err
+}
+
+// PendingTransactions converts the server implementation of
+// PendingTransactions to the required rpc server interface
+func (m *RPCServer) PendingTransactions(r *http.Request, args *PendingTransactionsArgs, reply *PendingTransactionsReply) error {
+	ret, err := m.Server.PendingTransactions(context.Background(), args)
+	if ret != nil {
+		reply.Transactions = ret.Transactions
+	}
+	return err
+}
+
+// PendingNonce converts the server implementation of PendingNonce to the
+// required rpc server interface
+func (m *RPCServer) PendingNonce(r *http.Request, args *PendingNonceArgs, reply *PendingNonceReply) error {
+	ret, err := m.Server.PendingNonce(context.Background(), args)
+	if ret != nil {
+		reply.Nonce = ret.Nonce
+		reply.Found = ret.Found
+	}
+	return err
+}
+
+// BatchStatus converts the server implementation of BatchStatus to the
+// required rpc server interface
+func (m *RPCServer) BatchStatus(r *http.Request, args *BatchStatusArgs, reply *BatchStatusReply) error {
+	ret, err := m.Server.BatchStatus(context.Background(), args)
+	if ret != nil {
+		reply.Batches = ret.Batches
+		reply.ReadyTransactions = ret.ReadyTransactions
+		reply.RetryInSeconds = ret.RetryInSeconds
+	}
+	return err
+}
+
+// GetTransactionStatus converts the server implementation of
+// GetTransactionStatus to the required rpc server interface
+func (m *RPCServer) GetTransactionStatus(r *http.Request, args *GetTransactionStatusArgs, reply *GetTransactionStatusReply) error {
+	ret, err := m.Server.GetTransactionStatus(context.Background(), args)
+	if ret != nil {
+		reply.Status = ret.Status
+		reply.L1TxHash = ret.L1TxHash
+		reply.Error = ret.Error
 	}
 	return err
 }
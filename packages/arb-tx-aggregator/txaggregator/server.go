@@ -33,36 +33,76 @@ import (
 	"github.com/offchainlabs/arbitrum/packages/arb-util/hashing"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/arbbridge"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/message"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/metrics"
 )
 
-const maxTransactions = 200
-
 const signatureLength = 65
 const recoverBitPos = signatureLength - 1
 
+var (
+	readyTxGauge       = metrics.NewGauge("arbitrum/aggregator/queue/ready")
+	queuedTxGauge      = metrics.NewGauge("arbitrum/aggregator/queue/queued")
+	batchTxHistogram   = metrics.NewHistogram("arbitrum/aggregator/batch/transactions")
+	batchSizeHistogram = metrics.NewHistogram("arbitrum/aggregator/batch/bytes")
+)
+
 type Server struct {
 	rollupAddress common.Address
+	client        arbbridge.ArbClient
 	globalInbox   arbbridge.GlobalInbox
+	policy        BatchPolicy
 
 	sync.Mutex
-	valid        bool
-	transactions []message.BatchTx
+	pool     *txPool
+	batches  *batchTracker
+	txLog    *txLog
+	statuses *txStatusTracker
 }
 
-// NewServer returns a new instance of the Server class
+// NewServer returns a new instance of the Server class. Batches are
+// submitted according to policy. Accepted transactions are recorded in a log
+// at txLogPath so that they survive a restart. Any transactions which were
+// outstanding in the log are returned to the queue
 func NewServer(
 	ctx context.Context,
+	client arbbridge.ArbClient,
 	globalInbox arbbridge.GlobalInbox,
 	rollupAddress common.Address,
-) *Server {
+	policy BatchPolicy,
+	txLogPath string,
+) (*Server, error) {
+	txLog, entries, err := openTxLog(txLogPath)
+	if err != nil {
+		return nil, errors2.Wrap(err, "error opening tx log")
+	}
+
 	server := &Server{
 		rollupAddress: rollupAddress,
+		client:        client,
 		globalInbox:   globalInbox,
-		valid:         true,
+		policy:        policy,
+		pool:          newTxPool(),
+		batches:       newBatchTracker(),
+		txLog:         txLog,
+		statuses:      newTxStatusTracker(),
+	}
+
+	if len(entries) > 0 {
+		log.Println("Restoring", len(entries), "transactions from tx log")
+	}
+	for _, entry := range entries {
+		if err := server.addToPool(&pooledTx{
+			tx:     entry.tx,
+			sender: entry.sender,
+			hash:   entry.tx.Transaction(rollupAddress, entry.sender).ReceiptHash(),
+			logID:  entry.id,
+		}); err != nil {
+			log.Println("Dropping restored tx", entry.id, err)
+		}
 	}
 
 	go func() {
-		ticker := time.NewTicker(time.Second * 5)
+		ticker := time.NewTicker(policy.CheckInterval())
 		defer ticker.Stop()
 		for {
 			select {
@@ -70,43 +110,69 @@ func NewServer(
 				return
 
 			case <-ticker.C:
+				server.checkBatches(ctx)
+
 				server.Lock()
-				// Keep sending in spin loop until we can't anymore
-				sentFull := false
-				for server.valid && len(server.transactions) >= maxTransactions {
-					server.sendBatch(ctx)
-					sentFull = true
-				}
-				// If we have've sent any batches, send a partial
-				if !sentFull && server.valid && len(server.transactions) > 0 {
+				readyTxGauge.Update(int64(server.pool.ReadyCount()))
+				queuedTxGauge.Update(int64(server.pool.QueuedCount()))
+				server.Unlock()
+
+				// Keep sending in spin loop until the policy says to wait
+				for {
+					server.Lock()
+					if !server.batches.canSubmit(time.Now()) {
+						server.Unlock()
+						break
+					}
+					pending := server.pool.ReadyStats()
+					server.Unlock()
+
+					if !policy.ShouldFlush(ctx, pending) {
+						break
+					}
+
+					server.Lock()
 					server.sendBatch(ctx)
+					server.Unlock()
 				}
-				server.Unlock()
 			}
 		}
 	}()
-	return server
+	go func() {
+		<-ctx.Done()
+		server.Lock()
+		defer server.Unlock()
+		if err := server.txLog.Close(); err != nil {
+			log.Println("Error closing tx log", err)
+		}
+	}()
+	return server, nil
 }
 
 func (m *Server) sendBatch(ctx context.Context) {
-	var txes []message.BatchTx
-
-	if len(m.transactions) > maxTransactions {
-		txes = m.transactions[:maxTransactions]
-		m.transactions = m.transactions[maxTransactions:]
-	} else {
-		txes = m.transactions
-		m.transactions = nil
+	b := m.batches.newBatch(m.pool.PopBatch(
+		m.policy.MaxTransactions(),
+		m.policy.MaxBytes(),
+	))
+	if len(b.txes) == 0 {
+		return
 	}
+	txes := b.batchTxes()
+	batchTxHistogram.Update(int64(len(b.txes)))
+	batchSize := 0
+	for _, ptx := range b.txes {
+		batchSize += ptx.encodedLength()
+	}
+	batchSizeHistogram.Update(int64(batchSize))
 	m.Unlock()
 
-	log.Println("Submitting batch with", len(txes), "transactions")
+	log.Println("Submitting batch", b.id, "with", len(txes), "transactions")
 
 	for _, tx := range txes {
 		log.Println("tx: ", tx)
 	}
 
-	err := m.globalInbox.DeliverTransactionBatchNoWait(
+	l1TxHash, err := m.globalInbox.DeliverTransactionBatchNoWait(
 		ctx,
 		m.rollupAddress,
 		txes,
@@ -114,8 +180,96 @@ func (m *Server) sendBatch(ctx context.Context) {
 
 	m.Lock()
 	if err != nil {
-		log.Println("Transaction aggregator failed: ", err)
-		m.valid = false
+		log.Println("Failed to submit batch", b.id, err)
+		m.batches.submitFailed(b, err, time.Now())
+		m.requeue(b)
+		return
+	}
+	m.batches.submitted(b, l1TxHash, time.Now())
+	m.statuses.submitted(b.txes, l1TxHash)
+}
+
+// checkBatches looks up the L1 status of each batch which is waiting for a
+// receipt. Batches which were reverted, dropped or replaced are returned to
+// the pool so that their transactions are resubmitted
+func (m *Server) checkBatches(ctx context.Context) {
+	m.Lock()
+	inFlight := append([]*batch{}, m.batches.inFlight...)
+	m.Unlock()
+
+	for _, b := range inFlight {
+		status, err := m.client.GetTxStatus(ctx, b.l1TxHash)
+		if err != nil {
+			log.Println("Failed to get status of batch", b.id, err)
+			continue
+		}
+
+		m.Lock()
+		switch status {
+		case arbbridge.TxSucceeded:
+			log.Println("Batch", b.id, "confirmed in", b.l1TxHash)
+			m.batches.finish(b, batchConfirmed, "")
+			m.statuses.included(b.txes, b.l1TxHash)
+			m.removeFromLog(b.txes)
+		case arbbridge.TxFailed:
+			log.Println("Batch", b.id, "reverted in", b.l1TxHash)
+			m.batches.finish(b, batchFailed, "L1 transaction reverted")
+			m.requeue(b)
+		case arbbridge.TxNotFound:
+			if time.Since(b.submitted) > droppedTxTimeout {
+				log.Println("Batch", b.id, "was dropped or replaced")
+				m.batches.finish(b, batchFailed, "L1 transaction dropped or replaced")
+				m.requeue(b)
+			}
+		}
+		m.Unlock()
+	}
+}
+
+func (m *Server) requeue(b *batch) {
+	txes := retryable(b)
+	if len(txes) < len(b.txes) {
+		dropped := make([]*pooledTx, 0, len(b.txes)-len(txes))
+		for _, ptx := range b.txes {
+			if ptx.attempts >= maxSubmitAttempts {
+				dropped = append(dropped, ptx)
+				m.statuses.failed(ptx, "dropped after too many failed batches")
+			}
+		}
+		log.Println("Dropping", len(dropped), "transactions from batch", b.id, "after", maxSubmitAttempts, "attempts")
+		m.removeFromLog(dropped)
+		m.pool.Dropped(dropped)
+	}
+	for _, ptx := range txes {
+		m.statuses.queued(ptx)
+	}
+	m.pool.Requeue(txes)
+}
+
+// addToPool adds a transaction which has already been written to the tx log
+// to the pool, removing it or the transaction it replaced from the log
+func (m *Server) addToPool(ptx *pooledTx) error {
+	replaced, err := m.pool.Add(ptx)
+	if err != nil {
+		m.removeFromLog([]*pooledTx{ptx})
+		return err
+	}
+	if replaced != nil {
+		log.Println("Replaced queued tx from", ptx.sender, "with sequence number", ptx.tx.SeqNum)
+		m.statuses.failed(replaced, "replaced by a later transaction")
+		m.removeFromLog([]*pooledTx{replaced})
+	}
+	m.statuses.queued(ptx)
+	return nil
+}
+
+func (m *Server) removeFromLog(txes []*pooledTx) {
+	ids := make([]uint64, 0, len(txes))
+	for _, ptx := range txes {
+		ids = append(ids, ptx.logID)
+	}
+	if err := m.txLog.Remove(ids); err != nil {
+		log.Println("Error updating tx log", err)
 	}
 }
 
@@ -186,23 +340,174 @@ func (m *Server) SendTransaction(
 		return nil, errors.New("Invalid signature")
 	}
 
+	pubkeyRecovered, err := crypto.SigToPub(messageHash[:], signature)
+	if err != nil {
+		return nil, errors2.Wrap(err, "error recovering sender")
+	}
+	sender := common.NewAddressFromEth(crypto.PubkeyToAddress(*pubkeyRecovered))
+
 	m.Lock()
 	defer m.Unlock()
 
-	if !m.valid {
-		return nil, errors.New("Tx aggregator is not running")
-	}
-
 	var sigData [signatureLength]byte
 	copy(sigData[:], signature)
 
-	m.transactions = append(m.transactions, message.BatchTx{
+	tx := message.BatchTx{
 		To:     to,
 		SeqNum: sequenceNum,
 		Value:  valueInt,
 		Data:   data,
 		Sig:    sigData,
-	})
+	}
 
-	return &SendTransactionReply{}, nil
+	// The tx must be in the log before it is acknowledged
+	logID, err := m.txLog.Append(sender, tx)
+	if err != nil {
+		log.Println("Error writing tx log", err)
+		return nil, errors.New("failed to record transaction")
+	}
+
+	txHash := tx.Transaction(m.rollupAddress, sender).ReceiptHash()
+	if err := m.addToPool(&pooledTx{
+		tx:     tx,
+		sender: sender,
+		hash:   txHash,
+		logID:  logID,
+	}); err != nil {
+		return nil, err
+	}
+
+	return &SendTransactionReply{
+		Accepted: true,
+		TxHash:   hexutil.Encode(txHash[:]),
+	}, nil
+}
+
+// PendingTransactions returns the transactions waiting in the pool. If an
+// address is given, only transactions from that sender are returned
+func (m *Server) PendingTransactions(
+	ctx context.Context,
+	args *PendingTransactionsArgs,
+) (*PendingTransactionsReply, error) {
+	var sender *common.Address
+	if args.Address != "" {
+		address, err := decodeAddress(args.Address)
+		if err != nil {
+			return nil, err
+		}
+		sender = &address
+	}
+
+	m.Lock()
+	txes, ready := m.pool.Pending(sender)
+	m.Unlock()
+
+	pending := make([]*PendingTransaction, 0, len(txes))
+	for i, ptx := range txes {
+		pending = append(pending, &PendingTransaction{
+			From:        hexutil.Encode(ptx.sender[:]),
+			To:          hexutil.Encode(ptx.tx.To[:]),
+			SequenceNum: ptx.tx.SeqNum.String(),
+			Value:       ptx.tx.Value.String(),
+			Data:        hexutil.Encode(ptx.tx.Data),
+			Ready:       ready[i],
+		})
+	}
+	return &PendingTransactionsReply{Transactions: pending}, nil
+}
+
+// PendingNonce returns the sequence number that the next transaction from
+// the given address should use, taking into account the transactions in the
+// pool. Found is false if the aggregator hasn't seen the address
+func (m *Server) PendingNonce(
+	ctx context.Context,
+	args *PendingNonceArgs,
+) (*PendingNonceReply, error) {
+	address, err := decodeAddress(args.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	m.Lock()
+	nonce, found := m.pool.PendingSeqNum(address)
+	m.Unlock()
+
+	if !found {
+		return &PendingNonceReply{Found: false}, nil
+	}
+	return &PendingNonceReply{Nonce: nonce.String(), Found: true}, nil
+}
+
+// GetTransactionStatus reports whether the transaction with the given hash is
+// queued, has been submitted in a batch, has been included on L1 or has failed
+func (m *Server) GetTransactionStatus(
+	ctx context.Context,
+	args *GetTransactionStatusArgs,
+) (*GetTransactionStatusReply, error) {
+	txHashBytes, err := hexutil.Decode(args.TxHash)
+	if err != nil {
+		return nil, errors2.Wrap(err, "error decoding tx hash")
+	}
+	var txHash common.Hash
+	copy(txHash[:], txHashBytes)
+
+	m.Lock()
+	status, found := m.statuses.Get(txHash)
+	m.Unlock()
+
+	if !found {
+		return &GetTransactionStatusReply{Status: "unknown"}, nil
+	}
+	reply := &GetTransactionStatusReply{
+		Status: status.state.String(),
+		Error:  status.err,
+	}
+	if status.l1TxHash != (common.Hash{}) {
+		reply.L1TxHash = hexutil.Encode(status.l1TxHash[:])
+	}
+	return reply, nil
+}
+
+// BatchStatus reports the batches waiting for an L1 receipt along with
+// recently confirmed and failed batches
+func (m *Server) BatchStatus(
+	ctx context.Context,
+	args *BatchStatusArgs,
+) (*BatchStatusReply, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	batches := make([]*BatchInfo, 0, len(m.batches.history)+len(m.batches.inFlight))
+	for _, b := range append(append([]*batch{}, m.batches.history...), m.batches.inFlight...) {
+		info := &BatchInfo{
+			Id:               b.id,
+			Status:           b.state.String(),
+			TransactionCount: uint64(len(b.txes)),
+			Error:            b.err,
+		}
+		if b.l1TxHash != (common.Hash{}) {
+			info.L1TxHash = hexutil.Encode(b.l1TxHash[:])
+		}
+		batches = append(batches, info)
+	}
+
+	var retryIn time.Duration
+	if now := time.Now(); !m.batches.canSubmit(now) {
+		retryIn = m.batches.retryAt.Sub(now)
+	}
+	return &BatchStatusReply{
+		Batches:           batches,
+		ReadyTransactions: uint64(m.pool.ReadyCount()),
+		RetryInSeconds:    uint64(retryIn.Seconds()),
+	}, nil
+}
+
+func decodeAddress(hexAddress string) (common.Address, error) {
+	addressBytes, err := hexutil.Decode(hexAddress)
+	if err != nil {
+		return common.Address{}, errors2.Wrap(err, "error decoding address")
+	}
+	var address common.Address
+	copy(address[:], addressBytes)
+	return address, nil
 }
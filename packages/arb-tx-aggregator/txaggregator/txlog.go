@@ -0,0 +1,316 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package txaggregator
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"log"
+	"os"
+	"sort"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/message"
+)
+
+const (
+	txLogAdd    byte = 0
+	txLogRemove byte = 1
+
+	// txLogHeaderLength is the size of the kind, length and checksum fields
+	// at the start of each record
+	txLogHeaderLength = 9
+
+	// minCompactionRecords is the minimum number of records in the log
+	// before it is worth compacting
+	minCompactionRecords = 1000
+)
+
+var errCorruptTxLog = errors.New("corrupt tx log record")
+
+type txLogEntry struct {
+	id     uint64
+	sender common.Address
+	tx     message.BatchTx
+}
+
+// txLog is an append-only log of the transactions accepted by the aggregator.
+// A transaction is written to the log before it is acknowledged and a removal
+// record is written once it has been confirmed on L1 or dropped, so that on
+// restart every acknowledged transaction which may not have made it onto the
+// chain is submitted again. Once most of the log consists of removed
+// transactions, it is rewritten with only the outstanding ones
+type txLog struct {
+	path        string
+	file        *os.File
+	nextID      uint64
+	outstanding map[uint64]txLogEntry
+	records     int
+}
+
+// openTxLog opens the log at path, creating it if necessary, and returns the
+// transactions which were outstanding in the order they were accepted
+func openTxLog(path string) (*txLog, []txLogEntry, error) {
+	l := &txLog{
+		path:        path,
+		outstanding: make(map[uint64]txLogEntry),
+	}
+	validLength, err := l.replay()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Drop any partially written record at the end of the log
+	if err := file.Truncate(validLength); err != nil {
+		return nil, nil, err
+	}
+	if _, err := file.Seek(validLength, io.SeekStart); err != nil {
+		return nil, nil, err
+	}
+	l.file = file
+
+	if err := l.maybeCompact(); err != nil {
+		return nil, nil, err
+	}
+	return l, l.entries(), nil
+}
+
+// replay reads the existing log and returns the length of its valid prefix
+func (l *txLog) replay() (int64, error) {
+	file, err := os.Open(l.path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return 0, err
+	}
+	size := info.Size()
+
+	r := bufio.NewReader(file)
+	var offset int64
+	for {
+		kind, payload, err := readTxLogRecord(r, size-offset)
+		if err == io.EOF {
+			return offset, nil
+		}
+		if err == io.ErrUnexpectedEOF {
+			log.Println("Ignoring incomplete record at end of tx log")
+			return offset, nil
+		}
+		end := offset + int64(txLogHeaderLength+len(payload))
+		if err == errCorruptTxLog {
+			// Only the last record can have been partially written when the
+			// aggregator stopped, so a bad record before it means the log
+			// itself is damaged
+			if end != size {
+				return 0, fmt.Errorf("%v at offset %v of tx log", err, offset)
+			}
+			log.Println("Ignoring corrupt record at end of tx log")
+			return offset, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		if err := l.apply(kind, payload); err != nil {
+			return 0, err
+		}
+		offset = end
+	}
+}
+
+func (l *txLog) apply(kind byte, payload []byte) error {
+	l.records++
+	switch kind {
+	case txLogAdd:
+		if len(payload) < 28+message.DataOffset {
+			return errCorruptTxLog
+		}
+		id := binary.BigEndian.Uint64(payload[:8])
+		var sender common.Address
+		copy(sender[:], payload[8:28])
+		tx, err := message.NewBatchTxFromData(payload[28:], 0)
+		if err != nil {
+			return err
+		}
+		l.outstanding[id] = txLogEntry{id: id, sender: sender, tx: tx}
+		if id >= l.nextID {
+			l.nextID = id + 1
+		}
+	case txLogRemove:
+		for i := 0; i+8 <= len(payload); i += 8 {
+			delete(l.outstanding, binary.BigEndian.Uint64(payload[i:i+8]))
+		}
+	default:
+		return errCorruptTxLog
+	}
+	return nil
+}
+
+func (l *txLog) entries() []txLogEntry {
+	entries := make([]txLogEntry, 0, len(l.outstanding))
+	for _, entry := range l.outstanding {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].id < entries[j].id
+	})
+	return entries
+}
+
+// Append durably records the transaction and returns its id in the log
+func (l *txLog) Append(sender common.Address, tx message.BatchTx) (uint64, error) {
+	id := l.nextID
+	if err := writeTxLogRecord(l.file, txLogAdd, encodeTxLogAdd(id, sender, tx)); err != nil {
+		return 0, err
+	}
+	if err := l.file.Sync(); err != nil {
+		return 0, err
+	}
+	l.nextID++
+	l.records++
+	l.outstanding[id] = txLogEntry{id: id, sender: sender, tx: tx}
+	return id, nil
+}
+
+// Remove records that the transactions no longer need to be submitted
+func (l *txLog) Remove(ids []uint64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	payload := make([]byte, 0, len(ids)*8)
+	for _, id := range ids {
+		payload = append(payload, encodeUint64(id)...)
+		delete(l.outstanding, id)
+	}
+	if err := writeTxLogRecord(l.file, txLogRemove, payload); err != nil {
+		return err
+	}
+	l.records++
+	return l.maybeCompact()
+}
+
+func (l *txLog) maybeCompact() error {
+	if l.records < minCompactionRecords || l.records < 2*len(l.outstanding) {
+		return nil
+	}
+	return l.compact()
+}
+
+// compact rewrites the log so that it only contains outstanding transactions
+func (l *txLog) compact() error {
+	tmpPath := l.path + ".tmp"
+	tmp, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(tmp)
+	entries := l.entries()
+	for _, entry := range entries {
+		if err := writeTxLogRecord(w, txLogAdd, encodeTxLogAdd(entry.id, entry.sender, entry.tx)); err != nil {
+			tmp.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, l.path); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(l.path, os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	if err := l.file.Close(); err != nil {
+		log.Println("Error closing old tx log", err)
+	}
+	l.file = file
+	l.records = len(entries)
+	return nil
+}
+
+func (l *txLog) Close() error {
+	return l.file.Close()
+}
+
+func encodeTxLogAdd(id uint64, sender common.Address, tx message.BatchTx) []byte {
+	payload := encodeUint64(id)
+	payload = append(payload, sender[:]...)
+	return append(payload, tx.ToBytes()...)
+}
+
+func writeTxLogRecord(w io.Writer, kind byte, payload []byte) error {
+	header := make([]byte, txLogHeaderLength)
+	header[0] = kind
+	binary.BigEndian.PutUint32(header[1:5], uint32(len(payload)))
+	binary.BigEndian.PutUint32(header[5:9], crc32.ChecksumIEEE(payload))
+	_, err := w.Write(append(header, payload...))
+	return err
+}
+
+// readTxLogRecord reads the next record from r, which has remaining bytes
+// left. If the checksum doesn't match, the payload is returned along with
+// errCorruptTxLog so that the caller knows where the record ends
+func readTxLogRecord(r io.Reader, remaining int64) (byte, []byte, error) {
+	header := make([]byte, txLogHeaderLength)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return 0, nil, err
+	}
+	length := binary.BigEndian.Uint32(header[1:5])
+	if int64(length) > remaining-txLogHeaderLength {
+		return 0, nil, io.ErrUnexpectedEOF
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return 0, nil, err
+	}
+	if crc32.ChecksumIEEE(payload) != binary.BigEndian.Uint32(header[5:9]) {
+		return 0, payload, errCorruptTxLog
+	}
+	return header[0], payload, nil
+}
+
+func encodeUint64(val uint64) []byte {
+	ret := make([]byte, 8)
+	binary.BigEndian.PutUint64(ret, val)
+	return ret
+}
@@ -0,0 +1,207 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package txaggregator
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+)
+
+func TestTxLogReplay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "txlog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "txlog")
+
+	l, entries, err := openTxLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Fatal("expected new log to be empty")
+	}
+	sender := common.Address{1}
+	ids := make([]uint64, 0)
+	for i := int64(0); i < 3; i++ {
+		id, err := l.Append(sender, generateTestBatchTx(i, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+	}
+	if err := l.Remove(ids[:1]); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Simulate a crash in the middle of writing a record
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write([]byte{txLogAdd, 0, 0, 1}); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	l, entries, err = openTxLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	if len(entries) != 2 {
+		t.Fatal("expected 2 outstanding txes but got", len(entries))
+	}
+	for i, entry := range entries {
+		if entry.id != ids[i+1] || entry.tx.SeqNum.Int64() != int64(i+1) {
+			t.Error("unexpected entry", entry.id, entry.tx.SeqNum)
+		}
+		if entry.sender != sender {
+			t.Error("wrong sender restored")
+		}
+	}
+
+	id, err := l.Append(sender, generateTestBatchTx(3, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id <= ids[2] {
+		t.Error("expected ids to keep increasing after replay")
+	}
+}
+
+func TestTxLogCompaction(t *testing.T) {
+	dir, err := ioutil.TempDir("", "txlog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "txlog")
+
+	l, _, err := openTxLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender := common.Address{1}
+	for i := int64(0); i < minCompactionRecords; i++ {
+		id, err := l.Append(sender, generateTestBatchTx(i, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i > 0 {
+			if err := l.Remove([]uint64{id}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if l.records >= minCompactionRecords {
+		t.Error("expected log to be compacted but it has", l.records, "records")
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	l, entries, err := openTxLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	if len(entries) != 1 || entries[0].tx.SeqNum.Int64() != 0 {
+		t.Error("expected compacted log to contain the outstanding tx")
+	}
+}
+
+func TestTxLogCorruptRecord(t *testing.T) {
+	dir, err := ioutil.TempDir("", "txlog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "txlog")
+
+	l, _, err := openTxLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender := common.Address{1}
+	for i := int64(0); i < 2; i++ {
+		if _, err := l.Append(sender, generateTestBatchTx(i, 0)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writeCorrupted := func(offset int) {
+		t.Helper()
+		corrupted := append([]byte{}, data...)
+		corrupted[offset] ^= 0xff
+		if err := ioutil.WriteFile(path, corrupted, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A bad record in the middle of the log must not drop the records after it
+	writeCorrupted(txLogHeaderLength)
+	if _, _, err := openTxLog(path); err == nil {
+		t.Error("expected corrupt record before the end of the log to fail")
+	}
+
+	// The last record may have been partially written before a crash
+	writeCorrupted(len(data) - 1)
+	l, entries, err := openTxLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].tx.SeqNum.Int64() != 0 {
+		t.Error("expected only the first tx to be restored")
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// A length running past the end of the file is an incomplete record
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write([]byte{txLogAdd, 0xff, 0xff, 0xff, 0xff, 0, 0, 0, 0, 1}); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	l, entries, err = openTxLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	if len(entries) != 1 {
+		t.Error("expected 1 outstanding tx but got", len(entries))
+	}
+}
@@ -0,0 +1,117 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package txaggregator
+
+import (
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+)
+
+// maxTxStatusHistory is the number of included or failed transactions whose
+// status is remembered
+const maxTxStatusHistory = 10000
+
+type txState int
+
+const (
+	txQueued txState = iota
+	txSubmitted
+	txIncluded
+	txFailed
+)
+
+func (s txState) String() string {
+	switch s {
+	case txQueued:
+		return "queued"
+	case txSubmitted:
+		return "submitted"
+	case txIncluded:
+		return "included"
+	case txFailed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
+
+type txStatus struct {
+	state    txState
+	l1TxHash common.Hash
+	err      string
+}
+
+// txStatusTracker records the progress of each transaction accepted by the
+// aggregator, keyed by its Arbitrum tx hash
+type txStatusTracker struct {
+	statuses map[common.Hash]*txStatus
+	finished []common.Hash
+}
+
+func newTxStatusTracker() *txStatusTracker {
+	return &txStatusTracker{
+		statuses: make(map[common.Hash]*txStatus),
+	}
+}
+
+func (t *txStatusTracker) Get(txHash common.Hash) (txStatus, bool) {
+	status, ok := t.statuses[txHash]
+	if !ok {
+		return txStatus{}, false
+	}
+	return *status, true
+}
+
+func (t *txStatusTracker) queued(ptx *pooledTx) {
+	t.set(ptx.hash, txStatus{state: txQueued})
+}
+
+func (t *txStatusTracker) submitted(txes []*pooledTx, l1TxHash common.Hash) {
+	for _, ptx := range txes {
+		t.set(ptx.hash, txStatus{state: txSubmitted, l1TxHash: l1TxHash})
+	}
+}
+
+func (t *txStatusTracker) included(txes []*pooledTx, l1TxHash common.Hash) {
+	for _, ptx := range txes {
+		t.set(ptx.hash, txStatus{state: txIncluded, l1TxHash: l1TxHash})
+	}
+}
+
+func (t *txStatusTracker) failed(ptx *pooledTx, err string) {
+	t.set(ptx.hash, txStatus{state: txFailed, err: err})
+}
+
+func (t *txStatusTracker) set(txHash common.Hash, status txStatus) {
+	prev, ok := t.statuses[txHash]
+	if !ok {
+		prev = &txStatus{}
+		t.statuses[txHash] = prev
+	}
+	finishing := status.state == txIncluded || status.state == txFailed
+	wasFinished := ok && (prev.state == txIncluded || prev.state == txFailed)
+	*prev = status
+	if finishing && !wasFinished {
+		t.finished = append(t.finished, txHash)
+		if len(t.finished) > maxTxStatusHistory {
+			oldest := t.finished[0]
+			t.finished = t.finished[1:]
+			if old, ok := t.statuses[oldest]; ok && (old.state == txIncluded || old.state == txFailed) {
+				delete(t.statuses, oldest)
+			}
+		}
+	}
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package txaggregator
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+)
+
+func statusTestTx(i uint64) *pooledTx {
+	var hash common.Hash
+	binary.BigEndian.PutUint64(hash[:], i+1)
+	return &pooledTx{hash: hash}
+}
+
+func checkStatus(t *testing.T, statuses *txStatusTracker, ptx *pooledTx, state txState, l1TxHash common.Hash) {
+	t.Helper()
+	status, found := statuses.Get(ptx.hash)
+	if !found {
+		t.Fatal("no status for tx")
+	}
+	if status.state != state || status.l1TxHash != l1TxHash {
+		t.Errorf("tx is %v in %v, expected %v in %v", status.state, status.l1TxHash, state, l1TxHash)
+	}
+}
+
+func TestTxStatusLifecycle(t *testing.T) {
+	statuses := newTxStatusTracker()
+	ptx := statusTestTx(0)
+	if _, found := statuses.Get(ptx.hash); found {
+		t.Fatal("unknown tx has a status")
+	}
+
+	statuses.queued(ptx)
+	checkStatus(t, statuses, ptx, txQueued, common.Hash{})
+
+	firstL1Tx := common.Hash{1}
+	statuses.submitted([]*pooledTx{ptx}, firstL1Tx)
+	checkStatus(t, statuses, ptx, txSubmitted, firstL1Tx)
+
+	// The batch failed so the tx is queued and submitted again
+	statuses.queued(ptx)
+	checkStatus(t, statuses, ptx, txQueued, common.Hash{})
+	secondL1Tx := common.Hash{2}
+	statuses.submitted([]*pooledTx{ptx}, secondL1Tx)
+	statuses.included([]*pooledTx{ptx}, secondL1Tx)
+	checkStatus(t, statuses, ptx, txIncluded, secondL1Tx)
+
+	failed := statusTestTx(1)
+	statuses.queued(failed)
+	statuses.failed(failed, "replaced by a later transaction")
+	checkStatus(t, statuses, failed, txFailed, common.Hash{})
+	if status, _ := statuses.Get(failed.hash); status.err != "replaced by a later transaction" {
+		t.Error("wrong failure reason", status.err)
+	}
+	if len(statuses.finished) != 2 {
+		t.Error("expected 2 finished txes but got", len(statuses.finished))
+	}
+}
+
+func TestTxStatusHistoryLimit(t *testing.T) {
+	statuses := newTxStatusTracker()
+	pending := statusTestTx(maxTxStatusHistory + 10)
+	statuses.queued(pending)
+
+	first := statusTestTx(0)
+	statuses.included([]*pooledTx{first}, common.Hash{1})
+	// Finishing a tx again doesn't count it twice towards the limit
+	statuses.included([]*pooledTx{first}, common.Hash{1})
+	for i := uint64(1); i < maxTxStatusHistory; i++ {
+		statuses.failed(statusTestTx(i), "dropped")
+	}
+	if _, found := statuses.Get(first.hash); !found {
+		t.Fatal("tx forgotten before the history was full")
+	}
+
+	statuses.failed(statusTestTx(maxTxStatusHistory), "dropped")
+	if _, found := statuses.Get(first.hash); found {
+		t.Error("oldest finished tx wasn't forgotten")
+	}
+	if _, found := statuses.Get(statusTestTx(1).hash); !found {
+		t.Error("newer finished tx was forgotten")
+	}
+	checkStatus(t, statuses, pending, txQueued, common.Hash{})
+	if len(statuses.finished) != maxTxStatusHistory {
+		t.Error("history has", len(statuses.finished), "txes")
+	}
+}
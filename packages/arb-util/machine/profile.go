@@ -0,0 +1,305 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package machine
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
+)
+
+// ProfileEntry is the cost of the instructions run at a single pc
+type ProfileEntry struct {
+	PC     int64
+	Opcode value.Opcode
+	Steps  uint64
+	Gas    uint64
+}
+
+// ProfiledMachine is a Machine which can record the cost of every
+// instruction it runs into a Profile. Clones of the machine record into the
+// same profile
+type ProfiledMachine interface {
+	Machine
+	SetProfile(profile *Profile)
+}
+
+// Profile totals the steps and gas used at each pc over any number of
+// executions. It is safe for concurrent use
+type Profile struct {
+	sync.Mutex
+	entries map[int64]*ProfileEntry
+}
+
+func NewProfile() *Profile {
+	return &Profile{entries: make(map[int64]*ProfileEntry)}
+}
+
+// Add merges the costs from a single execution into the profile. Entries
+// with no steps are ignored
+func (p *Profile) Add(entries []ProfileEntry) {
+	p.Lock()
+	defer p.Unlock()
+	for _, entry := range entries {
+		if entry.Steps == 0 {
+			continue
+		}
+		total, ok := p.entries[entry.PC]
+		if !ok {
+			total = &ProfileEntry{PC: entry.PC, Opcode: entry.Opcode}
+			p.entries[entry.PC] = total
+		}
+		total.Steps += entry.Steps
+		total.Gas += entry.Gas
+	}
+}
+
+func (p *Profile) Reset() {
+	p.Lock()
+	defer p.Unlock()
+	p.entries = make(map[int64]*ProfileEntry)
+}
+
+// Entries returns the cost of every pc which was run, ordered by pc
+func (p *Profile) Entries() []ProfileEntry {
+	p.Lock()
+	defer p.Unlock()
+	ret := make([]ProfileEntry, 0, len(p.entries))
+	for _, entry := range p.entries {
+		ret = append(ret, *entry)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].PC < ret[j].PC })
+	return ret
+}
+
+// ByOpcode returns the cost of each opcode which was run, ordered by opcode
+func (p *Profile) ByOpcode() []ProfileEntry {
+	return totalsByOpcode(p.Entries())
+}
+
+func totalsByOpcode(entries []ProfileEntry) []ProfileEntry {
+	totals := make(map[value.Opcode]*ProfileEntry)
+	for _, entry := range entries {
+		total, ok := totals[entry.Opcode]
+		if !ok {
+			total = &ProfileEntry{PC: -1, Opcode: entry.Opcode}
+			totals[entry.Opcode] = total
+		}
+		total.Steps += entry.Steps
+		total.Gas += entry.Gas
+	}
+	ret := make([]ProfileEntry, 0, len(totals))
+	for _, total := range totals {
+		ret = append(ret, *total)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Opcode < ret[j].Opcode })
+	return ret
+}
+
+func opName(opNames map[value.Opcode]string, op value.Opcode) string {
+	if name, ok := opNames[op]; ok {
+		return name
+	}
+	return fmt.Sprintf("0x%02x", byte(op))
+}
+
+func percent(part, total uint64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return 100 * float64(part) / float64(total)
+}
+
+func sortByCost(entries []ProfileEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Gas != entries[j].Gas {
+			return entries[i].Gas > entries[j].Gas
+		}
+		return entries[i].Steps > entries[j].Steps
+	})
+}
+
+// WriteReport writes a flat text report of the total cost of each opcode and
+// of the top most expensive pcs, or of every pc if top is 0. opNames gives
+// the mnemonic of each opcode
+func (p *Profile) WriteReport(w io.Writer, opNames map[value.Opcode]string, top int) error {
+	byPC := p.Entries()
+	byOpcode := totalsByOpcode(byPC)
+	var totalSteps, totalGas uint64
+	for _, entry := range byPC {
+		totalSteps += entry.Steps
+		totalGas += entry.Gas
+	}
+	sortByCost(byPC)
+	sortByCost(byOpcode)
+	if top > 0 && len(byPC) > top {
+		byPC = byPC[:top]
+	}
+
+	wr := bufio.NewWriter(w)
+	fmt.Fprintf(wr, "Total: %v gas, %v steps\n\n", totalGas, totalSteps)
+	fmt.Fprintf(wr, "%14v %7v %14v %7v  %v\n", "gas", "gas%", "steps", "steps%", "opcode")
+	for _, entry := range byOpcode {
+		fmt.Fprintf(
+			wr,
+			"%14v %6.2f%% %14v %6.2f%%  %v\n",
+			entry.Gas,
+			percent(entry.Gas, totalGas),
+			entry.Steps,
+			percent(entry.Steps, totalSteps),
+			opName(opNames, entry.Opcode),
+		)
+	}
+	fmt.Fprintf(wr, "\n%14v %7v %14v %7v  %v\n", "gas", "gas%", "steps", "steps%", "pc")
+	for _, entry := range byPC {
+		fmt.Fprintf(
+			wr,
+			"%14v %6.2f%% %14v %6.2f%%  %v %v\n",
+			entry.Gas,
+			percent(entry.Gas, totalGas),
+			entry.Steps,
+			percent(entry.Steps, totalSteps),
+			entry.PC,
+			opName(opNames, entry.Opcode),
+		)
+	}
+	return wr.Flush()
+}
+
+// WritePprof writes the profile in the gzipped protobuf format read by
+// pprof. Each opcode is a function and each pc is a line of the function for
+// its opcode in a file called programName, so pprof's default view shows the
+// cost of each opcode and its -lines view shows the cost of each pc
+func (p *Profile) WritePprof(w io.Writer, opNames map[value.Opcode]string, programName string) error {
+	entries := p.Entries()
+	table := newStringTable()
+	var b protobuf
+
+	// Profile.sample_type
+	for _, sampleType := range [][2]string{{"steps", "count"}, {"gas", "arbgas"}} {
+		b.message(1, func(m *protobuf) {
+			m.int64(1, table.index(sampleType[0]))
+			m.int64(2, table.index(sampleType[1]))
+		})
+	}
+	// Profile.sample
+	for i, entry := range entries {
+		b.message(2, func(m *protobuf) {
+			m.uint64(1, uint64(i+1))
+			m.int64(2, int64(entry.Steps))
+			m.int64(2, int64(entry.Gas))
+		})
+	}
+	// Profile.location
+	for i, entry := range entries {
+		b.message(4, func(m *protobuf) {
+			m.uint64(1, uint64(i+1))
+			m.uint64(3, uint64(entry.PC))
+			m.message(4, func(line *protobuf) {
+				line.uint64(1, uint64(entry.Opcode)+1)
+				line.int64(2, entry.PC)
+			})
+		})
+	}
+	// Profile.function
+	fileName := table.index(programName)
+	for _, entry := range totalsByOpcode(entries) {
+		name := table.index(opName(opNames, entry.Opcode))
+		b.message(5, func(m *protobuf) {
+			m.uint64(1, uint64(entry.Opcode)+1)
+			m.int64(2, name)
+			m.int64(3, name)
+			m.int64(4, fileName)
+		})
+	}
+	defaultSampleType := table.index("gas")
+	// Profile.string_table
+	for _, str := range table.strings {
+		b.string(6, str)
+	}
+	// Profile.default_sample_type
+	b.int64(14, defaultSampleType)
+
+	zw := gzip.NewWriter(w)
+	if _, err := zw.Write(b.data); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+type stringTable struct {
+	strings []string
+	indexes map[string]int64
+}
+
+func newStringTable() *stringTable {
+	// The first string must be empty
+	return &stringTable{strings: []string{""}, indexes: map[string]int64{"": 0}}
+}
+
+func (t *stringTable) index(str string) int64 {
+	if i, ok := t.indexes[str]; ok {
+		return i
+	}
+	i := int64(len(t.strings))
+	t.strings = append(t.strings, str)
+	t.indexes[str] = i
+	return i
+}
+
+// protobuf encodes the few protocol buffer field types used by WritePprof
+type protobuf struct {
+	data []byte
+}
+
+func (b *protobuf) varint(x uint64) {
+	for x >= 0x80 {
+		b.data = append(b.data, byte(x)|0x80)
+		x >>= 7
+	}
+	b.data = append(b.data, byte(x))
+}
+
+func (b *protobuf) uint64(tag int, x uint64) {
+	b.varint(uint64(tag) << 3)
+	b.varint(x)
+}
+
+func (b *protobuf) int64(tag int, x int64) {
+	b.uint64(tag, uint64(x))
+}
+
+func (b *protobuf) bytes(tag int, data []byte) {
+	b.varint(uint64(tag)<<3 | 2)
+	b.varint(uint64(len(data)))
+	b.data = append(b.data, data...)
+}
+
+func (b *protobuf) string(tag int, str string) {
+	b.bytes(tag, []byte(str))
+}
+
+func (b *protobuf) message(tag int, f func(m *protobuf)) {
+	var m protobuf
+	f(&m)
+	b.bytes(tag, m.data)
+}
@@ -0,0 +1,98 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package machine
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
+)
+
+var testOpNames = map[value.Opcode]string{1: "add", 2: "mul"}
+
+func testProfile() *Profile {
+	p := NewProfile()
+	p.Add([]ProfileEntry{
+		{PC: 0, Opcode: 1, Steps: 1, Gas: 3},
+		{PC: 1, Opcode: 2, Steps: 0, Gas: 0},
+		{PC: 4, Opcode: 2, Steps: 2, Gas: 6},
+	})
+	p.Add([]ProfileEntry{
+		{PC: 4, Opcode: 2, Steps: 1, Gas: 3},
+		{PC: 7, Opcode: 0x60, Steps: 1, Gas: 1},
+	})
+	return p
+}
+
+func TestProfileTotals(t *testing.T) {
+	p := testProfile()
+	entries := p.Entries()
+	if len(entries) != 3 {
+		t.Fatal("profile has", len(entries), "entries instead of 3")
+	}
+	if entries[1].PC != 4 || entries[1].Steps != 3 || entries[1].Gas != 9 {
+		t.Error("pc 4 wasn't merged", entries[1])
+	}
+	byOpcode := p.ByOpcode()
+	if len(byOpcode) != 3 || byOpcode[1].Opcode != 2 || byOpcode[1].Gas != 9 {
+		t.Error("wrong opcode totals", byOpcode)
+	}
+
+	var buf bytes.Buffer
+	if err := p.WriteReport(&buf, testOpNames, 1); err != nil {
+		t.Fatal(err)
+	}
+	report := buf.String()
+	if !strings.Contains(report, "Total: 13 gas, 5 steps") {
+		t.Error("report has wrong totals\n", report)
+	}
+	if !strings.Contains(report, "4 mul") || strings.Contains(report, "0 add") {
+		t.Error("report doesn't list only the most expensive pc\n", report)
+	}
+	if !strings.Contains(report, "0x60") {
+		t.Error("report doesn't list unnamed opcodes\n", report)
+	}
+
+	p.Reset()
+	if len(p.Entries()) != 0 {
+		t.Error("reset profile still has entries")
+	}
+}
+
+func TestProfilePprof(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testProfile().WritePprof(&buf, testOpNames, "contract.ao"); err != nil {
+		t.Fatal(err)
+	}
+	zr, err := gzip.NewReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadAll(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, str := range []string{"gas", "arbgas", "steps", "add", "mul", "0x60", "contract.ao"} {
+		if !bytes.Contains(data, []byte(str)) {
+			t.Error("profile doesn't contain", str)
+		}
+	}
+}
@@ -26,12 +26,28 @@ import (
 	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
 )
 
+// ReorgError is sent by SubscribeBlockHeaders when the next block doesn't
+// build on the last block it sent
+var ReorgError = errors.New("reorg occured")
+
 type MaybeBlockId struct {
 	BlockId   *common.BlockId
 	Timestamp *big.Int
 	Err       error
 }
 
+// TxStatus describes the state of a transaction submitted to the L1 chain
+type TxStatus int
+
+const (
+	// TxNotFound means the transaction is neither pending nor included, so
+	// it was either dropped or replaced
+	TxNotFound TxStatus = iota
+	TxPending
+	TxSucceeded
+	TxFailed
+)
+
 type ChainTimeGetter interface {
 	CurrentBlockId(ctx context.Context) (*common.BlockId, error)
 	BlockIdForHeight(ctx context.Context, height *common.TimeBlocks) (*common.BlockId, error)
@@ -49,6 +65,7 @@ type ArbClient interface {
 	NewOneStepProof(address common.Address) (OneStepProof, error)
 
 	GetBalance(ctx context.Context, account common.Address) (*big.Int, error)
+	GetTxStatus(ctx context.Context, txHash common.Hash) (TxStatus, error)
 }
 
 type ArbAuthClient interface {
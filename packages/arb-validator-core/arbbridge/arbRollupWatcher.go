@@ -28,6 +28,9 @@ type ArbRollupWatcher interface {
 
 	GetParams(ctx context.Context) (valprotocol.ChainParams, error)
 	InboxAddress(ctx context.Context) (common.Address, error)
-	GetCreationInfo(ctx context.Context) (*common.BlockId, common.Hash, error)
+	// GetCreationInfo returns the block in which the rollup was created and
+	// its initial VM hash. Only blocks from fromBlock onwards are searched,
+	// or all blocks if fromBlock is nil
+	GetCreationInfo(ctx context.Context, fromBlock *common.TimeBlocks) (*common.BlockId, common.Hash, error)
 	GetVersion(ctx context.Context) (string, error)
 }
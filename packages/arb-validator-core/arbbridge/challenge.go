@@ -18,6 +18,8 @@ package arbbridge
 
 import (
 	"context"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
 )
 
 type Challenge interface {
@@ -25,3 +27,18 @@ type Challenge interface {
 		ctx context.Context,
 	) error
 }
+
+type responseDeadlineKey struct{}
+
+// WithResponseDeadline returns a context that tells the bridge that
+// transactions sent with it must be mined before the given deadline, so
+// that it can bid up their gas price as the deadline approaches
+func WithResponseDeadline(ctx context.Context, deadline common.TimeTicks) context.Context {
+	return context.WithValue(ctx, responseDeadlineKey{}, deadline)
+}
+
+// ResponseDeadline returns the deadline attached by WithResponseDeadline
+func ResponseDeadline(ctx context.Context) (common.TimeTicks, bool) {
+	deadline, ok := ctx.Value(responseDeadlineKey{}).(common.TimeTicks)
+	return deadline, ok
+}
@@ -45,12 +45,13 @@ type GlobalInbox interface {
 	// blocking while waiting for the receipt. This behavior is different from
 	// the other ArbBridge methods. At some point other methods should be
 	// updated to behave this way once we can be confident that it will not
-	// create any security problems
+	// create any security problems. It returns the hash of the L1
+	// transaction so that the caller can track it
 	DeliverTransactionBatchNoWait(
 		ctx context.Context,
 		chain common.Address,
 		transactions []message.BatchTx,
-	) error
+	) (common.Hash, error)
 
 	DepositEthMessage(
 		ctx context.Context,
@@ -18,7 +18,6 @@ package ethbridge
 
 import (
 	"context"
-	"errors"
 	"log"
 	"math/big"
 	"sync"
@@ -26,24 +25,24 @@ import (
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/ethclient"
 
 	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/arbbridge"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/ethbridge/challengetester"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/ethbridge/executionchallenge"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/ethutils"
 )
 
 type EthArbClient struct {
-	client *ethclient.Client
+	client ethutils.EthClient
 }
 
-func NewEthClient(client *ethclient.Client) *EthArbClient {
+func NewEthClient(client ethutils.EthClient) *EthArbClient {
 	return &EthArbClient{client}
 }
 
-var reorgError = errors.New("reorg occured")
 var headerRetryDelay = time.Second * 2
 var maxFetchAttempts = 5
 
@@ -92,7 +91,7 @@ func (c *EthArbClient) SubscribeBlockHeaders(ctx context.Context, startBlockId *
 			}
 
 			if nextHeader.ParentHash != prevBlockId.HeaderHash.ToEthHash() {
-				blockIdChan <- arbbridge.MaybeBlockId{Err: reorgError}
+				blockIdChan <- arbbridge.MaybeBlockId{Err: arbbridge.ReorgError}
 				return
 			}
 
@@ -132,6 +131,28 @@ func (c *EthArbClient) GetBalance(ctx context.Context, account common.Address) (
 	return c.client.BalanceAt(ctx, account.ToEthAddress(), nil)
 }
 
+func (c *EthArbClient) GetTxStatus(ctx context.Context, txHash common.Hash) (arbbridge.TxStatus, error) {
+	receipt, err := c.client.TransactionReceipt(ctx, txHash.ToEthHash())
+	if err == nil {
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			return arbbridge.TxFailed, nil
+		}
+		return arbbridge.TxSucceeded, nil
+	}
+	if err.Error() != ethereum.NotFound.Error() {
+		return arbbridge.TxNotFound, err
+	}
+
+	_, _, err = c.client.TransactionByHash(ctx, txHash.ToEthHash())
+	if err == nil {
+		return arbbridge.TxPending, nil
+	}
+	if err.Error() != ethereum.NotFound.Error() {
+		return arbbridge.TxNotFound, err
+	}
+	return arbbridge.TxNotFound, nil
+}
+
 func (c *EthArbClient) CurrentBlockId(ctx context.Context) (*common.BlockId, error) {
 	header, err := c.client.HeaderByNumber(ctx, nil)
 	if err != nil {
@@ -150,7 +171,10 @@ func (c *EthArbClient) BlockIdForHeight(ctx context.Context, height *common.Time
 
 type TransactAuth struct {
 	sync.Mutex
-	auth *bind.TransactOpts
+	auth       *bind.TransactOpts
+	gasPrice   GasPriceOracle
+	escalation EscalationPolicy
+	nonces     *nonceManager
 }
 
 func (t *TransactAuth) getAuth(ctx context.Context) *bind.TransactOpts {
@@ -159,24 +183,147 @@ func (t *TransactAuth) getAuth(ctx context.Context) *bind.TransactOpts {
 		Nonce:    t.auth.Nonce,
 		Signer:   t.auth.Signer,
 		Value:    t.auth.Value,
-		GasPrice: t.auth.GasPrice,
+		GasPrice: t.currentGasPrice(ctx),
 		GasLimit: t.auth.GasLimit,
 		Context:  ctx,
 	}
 }
 
+// makeTx sends the transaction created by sendTx using the account's next
+// nonce. Only building and sending the transaction is serialized, so callers
+// can wait for their receipts concurrently
+func (t *TransactAuth) makeTx(
+	ctx context.Context,
+	sendTx func(auth *bind.TransactOpts) (*types.Transaction, error),
+) (*types.Transaction, error) {
+	t.Lock()
+	auth := t.getAuth(ctx)
+	t.Unlock()
+	return t.nonces.send(ctx, auth, sendTx)
+}
+
+// currentGasPrice asks the oracle for a gas price, falling back to the gas
+// price that the auth was created with if the oracle fails
+func (t *TransactAuth) currentGasPrice(ctx context.Context) *big.Int {
+	if t.gasPrice == nil {
+		return t.auth.GasPrice
+	}
+	price, err := t.gasPrice.GasPrice(ctx)
+	if err != nil {
+		log.Println("Failed to get gas price from oracle, using default", err)
+		return t.auth.GasPrice
+	}
+	return price
+}
+
+// waitForReceipt waits for tx to be mined, resubmitting it with a higher gas
+// price whenever the escalation policy considers it stuck
+func (t *TransactAuth) waitForReceipt(
+	ctx context.Context,
+	client ethutils.EthClient,
+	tx *types.Transaction,
+	methodName string,
+) (*types.Receipt, error) {
+	resubmit := func(ctx context.Context, tx *types.Transaction, pendingFor time.Duration) (*types.Transaction, error) {
+		t.Lock()
+		escalation := t.escalation
+		t.Unlock()
+		if escalation == nil {
+			return nil, nil
+		}
+		gasPrice, err := escalation.ReplacementGasPrice(ctx, tx, pendingFor)
+		if err != nil || gasPrice == nil {
+			return nil, err
+		}
+		var rawTx *types.Transaction
+		if tx.To() == nil {
+			rawTx = types.NewContractCreation(tx.Nonce(), tx.Value(), tx.Gas(), gasPrice, tx.Data())
+		} else {
+			rawTx = types.NewTransaction(tx.Nonce(), *tx.To(), tx.Value(), tx.Gas(), gasPrice, tx.Data())
+		}
+		// Sign the same way as bind.BoundContract
+		replacement, err := t.auth.Signer(types.HomesteadSigner{}, t.auth.From, rawTx)
+		if err != nil {
+			return nil, err
+		}
+		if err := client.SendTransaction(ctx, replacement); err != nil {
+			return nil, err
+		}
+		log.Println(
+			"Replaced stuck", methodName, "transaction", tx.Hash().Hex(),
+			"with", replacement.Hash().Hex(), "at gas price", gasPrice,
+		)
+		return replacement, nil
+	}
+	return waitForReceiptWithResubmit(ctx, client, t.auth.From, tx, methodName, resubmit)
+}
+
 type EthArbAuthClient struct {
 	*EthArbClient
 	auth *TransactAuth
 }
 
-func NewEthAuthClient(client *ethclient.Client, auth *bind.TransactOpts) *EthArbAuthClient {
+// NewEthAuthClient creates a client which sends transactions using auth. It
+// uses the gas price from auth, and if auth has no gas price it uses the
+// price suggested by the node. Transactions that are stuck are replaced
+// according to DefaultEscalationPolicy, escalating faster when they have a
+// response deadline. Nonces are assigned by the client, so auth should not be
+// used to send transactions elsewhere
+func NewEthAuthClient(client ethutils.EthClient, auth *bind.TransactOpts) *EthArbAuthClient {
+	var oracle GasPriceOracle
+	if auth.GasPrice != nil {
+		oracle = NewFixedGasPriceOracle(auth.GasPrice)
+	} else {
+		oracle = NewNodeGasPriceOracle(client)
+	}
+	arbClient := NewEthClient(client)
 	return &EthArbAuthClient{
-		EthArbClient: NewEthClient(client),
-		auth:         &TransactAuth{auth: auth},
+		EthArbClient: arbClient,
+		auth: &TransactAuth{
+			auth:       auth,
+			gasPrice:   oracle,
+			escalation: NewDeadlineEscalationPolicy(DefaultEscalationPolicy, arbClient),
+			nonces:     newNonceManager(client, auth.From),
+		},
 	}
 }
 
+// SubscribeBlockHeaders behaves like EthArbClient.SubscribeBlockHeaders, but
+// also resyncs the client's nonce when a reorg is detected since transactions
+// sent in the orphaned blocks may have been dropped
+func (c *EthArbAuthClient) SubscribeBlockHeaders(ctx context.Context, startBlockId *common.BlockId) (<-chan arbbridge.MaybeBlockId, error) {
+	headers, err := c.EthArbClient.SubscribeBlockHeaders(ctx, startBlockId)
+	if err != nil {
+		return nil, err
+	}
+	blockIdChan := make(chan arbbridge.MaybeBlockId, 100)
+	go func() {
+		defer close(blockIdChan)
+		for maybeBlockId := range headers {
+			if maybeBlockId.Err == arbbridge.ReorgError {
+				c.auth.nonces.resync()
+			}
+			blockIdChan <- maybeBlockId
+		}
+	}()
+	return blockIdChan, nil
+}
+
+// SetGasPriceOracle changes how the gas price of new transactions is chosen
+func (c *EthArbAuthClient) SetGasPriceOracle(oracle GasPriceOracle) {
+	c.auth.Lock()
+	c.auth.gasPrice = oracle
+	c.auth.Unlock()
+}
+
+// SetEscalationPolicy changes how stuck transactions are replaced. A nil
+// policy disables replacement
+func (c *EthArbAuthClient) SetEscalationPolicy(policy EscalationPolicy) {
+	c.auth.Lock()
+	c.auth.escalation = policy
+	c.auth.Unlock()
+}
+
 func (c *EthArbAuthClient) Address() common.Address {
 	return common.NewAddressFromEth(c.auth.auth.From)
 }
@@ -210,9 +357,13 @@ func (c *EthArbAuthClient) NewInboxTopChallenge(address common.Address) (arbbrid
 }
 
 func (c *EthArbAuthClient) DeployChallengeTest(ctx context.Context, challengeFactory common.Address) (*ChallengeTester, error) {
-	c.auth.Lock()
-	defer c.auth.Unlock()
-	testerAddress, tx, _, err := challengetester.DeployChallengeTester(c.auth.auth, c.client, challengeFactory.ToEthAddress())
+	var testerAddress ethcommon.Address
+	tx, err := c.auth.makeTx(ctx, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		var tx *types.Transaction
+		var err error
+		testerAddress, tx, _, err = challengetester.DeployChallengeTester(auth, c.client, challengeFactory.ToEthAddress())
+		return tx, err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -233,9 +384,13 @@ func (c *EthArbAuthClient) DeployChallengeTest(ctx context.Context, challengeFac
 }
 
 func (c *EthArbAuthClient) DeployOneStepProof(ctx context.Context) (arbbridge.OneStepProof, error) {
-	c.auth.Lock()
-	defer c.auth.Unlock()
-	ospAddress, tx, _, err := executionchallenge.DeployOneStepProof(c.auth.auth, c.client)
+	var ospAddress ethcommon.Address
+	tx, err := c.auth.makeTx(ctx, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		var tx *types.Transaction
+		var err error
+		ospAddress, tx, _, err = executionchallenge.DeployOneStepProof(auth, c.client)
+		return tx, err
+	})
 	if err != nil {
 		return nil, err
 	}
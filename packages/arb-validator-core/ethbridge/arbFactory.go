@@ -22,21 +22,23 @@ import (
 
 	errors2 "github.com/pkg/errors"
 
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	ethcommon "github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/core/types"
 
 	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/ethbridge/arbfactory"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/ethutils"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/valprotocol"
 )
 
 type arbFactory struct {
 	contract *arbfactory.ArbFactory
-	client   *ethclient.Client
+	client   ethutils.EthClient
 	auth     *TransactAuth
 }
 
-func newArbFactory(address ethcommon.Address, client *ethclient.Client, auth *TransactAuth) (*arbFactory, error) {
+func newArbFactory(address ethcommon.Address, client ethutils.EthClient, auth *TransactAuth) (*arbFactory, error) {
 	vmCreatorContract, err := arbfactory.NewArbFactory(address, client)
 	if err != nil {
 		return nil, errors2.Wrap(err, "Failed to connect to arbFactory")
@@ -50,22 +52,22 @@ func (con *arbFactory) CreateRollup(
 	params valprotocol.ChainParams,
 	owner common.Address,
 ) (common.Address, error) {
-	con.auth.Lock()
-	defer con.auth.Unlock()
-	tx, err := con.contract.CreateRollup(
-		con.auth.getAuth(ctx),
-		vmState,
-		params.GracePeriod.Val,
-		new(big.Int).SetUint64(params.ArbGasSpeedLimitPerTick),
-		params.MaxExecutionSteps,
-		[2]uint64{params.MaxBlockBoundsWidth, params.MaxTimestampBoundsWidth},
-		params.StakeRequirement,
-		owner.ToEthAddress(),
-	)
+	tx, err := con.auth.makeTx(ctx, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return con.contract.CreateRollup(
+			auth,
+			vmState,
+			params.GracePeriod.Val,
+			new(big.Int).SetUint64(params.ArbGasSpeedLimitPerTick),
+			params.MaxExecutionSteps,
+			[2]uint64{params.MaxBlockBoundsWidth, params.MaxTimestampBoundsWidth},
+			params.StakeRequirement,
+			owner.ToEthAddress(),
+		)
+	})
 	if err != nil {
 		return common.Address{}, errors2.Wrap(err, "Failed to call to ChainFactory.CreateChain")
 	}
-	receipt, err := WaitForReceiptWithResults(ctx, con.client, con.auth.auth.From, tx, "CreateChain")
+	receipt, err := con.auth.waitForReceipt(ctx, con.client, tx, "CreateChain")
 	if err != nil {
 		return common.Address{}, err
 	}
@@ -81,11 +83,11 @@ func (con *arbFactory) CreateRollup(
 
 type arbFactoryWatcher struct {
 	contract *arbfactory.ArbFactory
-	client   *ethclient.Client
+	client   ethutils.EthClient
 	address  ethcommon.Address
 }
 
-func newArbFactoryWatcher(address ethcommon.Address, client *ethclient.Client) (*arbFactoryWatcher, error) {
+func newArbFactoryWatcher(address ethcommon.Address, client ethutils.EthClient) (*arbFactoryWatcher, error) {
 	vmCreatorContract, err := arbfactory.NewArbFactory(address, client)
 	if err != nil {
 		return nil, errors2.Wrap(err, "Failed to connect to arbFactory")
@@ -26,22 +26,22 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	ethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/ethclient"
 
 	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
 	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/ethbridge/rollup"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/ethutils"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/valprotocol"
 )
 
 type arbRollup struct {
-	Client          *ethclient.Client
+	Client          ethutils.EthClient
 	ArbRollup       *rollup.ArbRollup
 	auth            *TransactAuth
 	contractAddress ethcommon.Address
 }
 
-func newRollup(address ethcommon.Address, client *ethclient.Client, auth *TransactAuth) (*arbRollup, error) {
+func newRollup(address ethcommon.Address, client ethutils.EthClient, auth *TransactAuth) (*arbRollup, error) {
 	arbitrumRollupContract, err := rollup.NewArbRollup(address, client)
 	if err != nil {
 		return nil, errors2.Wrap(err, "Failed to connect to arbRollup")
@@ -51,19 +51,14 @@ func newRollup(address ethcommon.Address, client *ethclient.Client, auth *Transa
 }
 
 func (vm *arbRollup) PlaceStake(ctx context.Context, stakeAmount *big.Int, proof1 []common.Hash, proof2 []common.Hash) error {
-	vm.auth.Lock()
-	defer vm.auth.Unlock()
-	call := &bind.TransactOpts{
-		From:    vm.auth.auth.From,
-		Signer:  vm.auth.auth.Signer,
-		Value:   stakeAmount,
-		Context: ctx,
-	}
-	tx, err := vm.ArbRollup.PlaceStake(
-		call,
-		hashSliceToRaw(proof1),
-		hashSliceToRaw(proof2),
-	)
+	tx, err := vm.auth.makeTx(ctx, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		auth.Value = stakeAmount
+		return vm.ArbRollup.PlaceStake(
+			auth,
+			hashSliceToRaw(proof1),
+			hashSliceToRaw(proof2),
+		)
+	})
 	if err != nil {
 		return err
 	}
@@ -71,12 +66,12 @@ func (vm *arbRollup) PlaceStake(ctx context.Context, stakeAmount *big.Int, proof
 }
 
 func (vm *arbRollup) RecoverStakeConfirmed(ctx context.Context, proof []common.Hash) error {
-	vm.auth.Lock()
-	defer vm.auth.Unlock()
-	tx, err := vm.ArbRollup.RecoverStakeConfirmed(
-		vm.auth.getAuth(ctx),
-		hashSliceToRaw(proof),
-	)
+	tx, err := vm.auth.makeTx(ctx, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return vm.ArbRollup.RecoverStakeConfirmed(
+			auth,
+			hashSliceToRaw(proof),
+		)
+	})
 	if err != nil {
 		return err
 	}
@@ -84,13 +79,13 @@ func (vm *arbRollup) RecoverStakeConfirmed(ctx context.Context, proof []common.H
 }
 
 func (vm *arbRollup) RecoverStakeOld(ctx context.Context, staker common.Address, proof []common.Hash) error {
-	vm.auth.Lock()
-	defer vm.auth.Unlock()
-	tx, err := vm.ArbRollup.RecoverStakeOld(
-		vm.auth.getAuth(ctx),
-		staker.ToEthAddress(),
-		hashSliceToRaw(proof),
-	)
+	tx, err := vm.auth.makeTx(ctx, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return vm.ArbRollup.RecoverStakeOld(
+			auth,
+			staker.ToEthAddress(),
+			hashSliceToRaw(proof),
+		)
+	})
 	if err != nil {
 		return err
 	}
@@ -98,15 +93,15 @@ func (vm *arbRollup) RecoverStakeOld(ctx context.Context, staker common.Address,
 }
 
 func (vm *arbRollup) RecoverStakeMooted(ctx context.Context, nodeHash common.Hash, staker common.Address, latestConfirmedProof []common.Hash, stakerProof []common.Hash) error {
-	vm.auth.Lock()
-	defer vm.auth.Unlock()
-	tx, err := vm.ArbRollup.RecoverStakeMooted(
-		vm.auth.getAuth(ctx),
-		staker.ToEthAddress(),
-		nodeHash,
-		hashSliceToRaw(latestConfirmedProof),
-		hashSliceToRaw(stakerProof),
-	)
+	tx, err := vm.auth.makeTx(ctx, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return vm.ArbRollup.RecoverStakeMooted(
+			auth,
+			staker.ToEthAddress(),
+			nodeHash,
+			hashSliceToRaw(latestConfirmedProof),
+			hashSliceToRaw(stakerProof),
+		)
+	})
 	if err != nil {
 		return err
 	}
@@ -114,17 +109,17 @@ func (vm *arbRollup) RecoverStakeMooted(ctx context.Context, nodeHash common.Has
 }
 
 func (vm *arbRollup) RecoverStakePassedDeadline(ctx context.Context, stakerAddress common.Address, deadlineTicks *big.Int, disputableNodeHashVal common.Hash, childType uint64, vmProtoStateHash common.Hash, proof []common.Hash) error {
-	vm.auth.Lock()
-	defer vm.auth.Unlock()
-	tx, err := vm.ArbRollup.RecoverStakePassedDeadline(
-		vm.auth.getAuth(ctx),
-		stakerAddress.ToEthAddress(),
-		deadlineTicks,
-		disputableNodeHashVal,
-		new(big.Int).SetUint64(childType),
-		vmProtoStateHash,
-		hashSliceToRaw(proof),
-	)
+	tx, err := vm.auth.makeTx(ctx, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return vm.ArbRollup.RecoverStakePassedDeadline(
+			auth,
+			stakerAddress.ToEthAddress(),
+			deadlineTicks,
+			disputableNodeHashVal,
+			new(big.Int).SetUint64(childType),
+			vmProtoStateHash,
+			hashSliceToRaw(proof),
+		)
+	})
 	if err != nil {
 		return err
 	}
@@ -132,13 +127,13 @@ func (vm *arbRollup) RecoverStakePassedDeadline(ctx context.Context, stakerAddre
 }
 
 func (vm *arbRollup) MoveStake(ctx context.Context, proof1 []common.Hash, proof2 []common.Hash) error {
-	vm.auth.Lock()
-	defer vm.auth.Unlock()
-	tx, err := vm.ArbRollup.MoveStake(
-		vm.auth.getAuth(ctx),
-		hashSliceToRaw(proof1),
-		hashSliceToRaw(proof2),
-	)
+	tx, err := vm.auth.makeTx(ctx, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return vm.ArbRollup.MoveStake(
+			auth,
+			hashSliceToRaw(proof1),
+			hashSliceToRaw(proof2),
+		)
+	})
 	if err != nil {
 		return err
 	}
@@ -146,9 +141,6 @@ func (vm *arbRollup) MoveStake(ctx context.Context, proof1 []common.Hash, proof2
 }
 
 func (vm *arbRollup) PruneLeaves(ctx context.Context, opps []valprotocol.PruneParams) error {
-	vm.auth.Lock()
-	defer vm.auth.Unlock()
-
 	fromNodes := make([]common.Hash, 0, len(opps))
 	leafProofs := make([]common.Hash, 0, len(opps))
 	leafProofLengths := make([]*big.Int, 0, len(opps))
@@ -162,14 +154,16 @@ func (vm *arbRollup) PruneLeaves(ctx context.Context, opps []valprotocol.PrunePa
 		confProofLengths = append(confProofLengths, big.NewInt(int64(len(opp.AncProof))))
 	}
 
-	tx, err := vm.ArbRollup.PruneLeaves(
-		vm.auth.getAuth(ctx),
-		hashSliceToRaw(fromNodes),
-		hashSliceToRaw(leafProofs),
-		leafProofLengths,
-		hashSliceToRaw(confProofs),
-		confProofLengths,
-	)
+	tx, err := vm.auth.makeTx(ctx, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return vm.ArbRollup.PruneLeaves(
+			auth,
+			hashSliceToRaw(fromNodes),
+			hashSliceToRaw(leafProofs),
+			leafProofLengths,
+			hashSliceToRaw(confProofs),
+			confProofLengths,
+		)
+	})
 	if err != nil {
 		return err
 	}
@@ -189,8 +183,6 @@ func (vm *arbRollup) MakeAssertion(
 	assertionClaim *valprotocol.AssertionClaim,
 	stakerProof []common.Hash,
 ) error {
-	vm.auth.Lock()
-	defer vm.auth.Unlock()
 	extraParams := [9][32]byte{
 		beforeState.MachineHash,
 		beforeState.InboxTop,
@@ -202,19 +194,21 @@ func (vm *arbRollup) MakeAssertion(
 		assertionClaim.AssertionStub.LastMessageHash,
 		assertionClaim.AssertionStub.LastLogHash,
 	}
-	tx, err := vm.ArbRollup.MakeAssertion(
-		vm.auth.getAuth(ctx),
-		extraParams,
-		beforeState.InboxCount,
-		prevDeadline.Val,
-		uint32(prevChildType),
-		assertionParams.NumSteps,
-		assertionParams.TimeBounds.AsIntArray(),
-		assertionParams.ImportedMessageCount,
-		assertionClaim.AssertionStub.DidInboxInsn,
-		assertionClaim.AssertionStub.NumGas,
-		hashSliceToRaw(stakerProof),
-	)
+	tx, err := vm.auth.makeTx(ctx, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return vm.ArbRollup.MakeAssertion(
+			auth,
+			extraParams,
+			beforeState.InboxCount,
+			prevDeadline.Val,
+			uint32(prevChildType),
+			assertionParams.NumSteps,
+			assertionParams.TimeBounds.AsIntArray(),
+			assertionParams.ImportedMessageCount,
+			assertionClaim.AssertionStub.DidInboxInsn,
+			assertionClaim.AssertionStub.NumGas,
+			hashSliceToRaw(stakerProof),
+		)
+	})
 	if err != nil {
 		return vm.ArbRollup.MakeAssertionCall(
 			ctx,
@@ -277,23 +271,22 @@ func (vm *arbRollup) Confirm(ctx context.Context, opp *valprotocol.ConfirmOpport
 		combinedProofs = append(combinedProofs, proof...)
 		stakerProofOffsets = append(stakerProofOffsets, big.NewInt(int64(len(combinedProofs))))
 	}
-	vm.auth.Lock()
-	defer vm.auth.Unlock()
-
-	tx, err := vm.ArbRollup.Confirm(
-		vm.auth.getAuth(ctx),
-		initalProtoStateHash,
-		branchesNums,
-		deadlineTicks,
-		hashSliceToRaw(challengeNodeData),
-		hashSliceToRaw(logsAcc),
-		hashSliceToRaw(vmProtoStateHashes),
-		messagesLengths,
-		messages,
-		addressSliceToRaw(opp.StakerAddresses),
-		hashSliceToRaw(combinedProofs),
-		stakerProofOffsets,
-	)
+	tx, err := vm.auth.makeTx(ctx, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return vm.ArbRollup.Confirm(
+			auth,
+			initalProtoStateHash,
+			branchesNums,
+			deadlineTicks,
+			hashSliceToRaw(challengeNodeData),
+			hashSliceToRaw(logsAcc),
+			hashSliceToRaw(vmProtoStateHashes),
+			messagesLengths,
+			messages,
+			addressSliceToRaw(opp.StakerAddresses),
+			hashSliceToRaw(combinedProofs),
+			stakerProofOffsets,
+		)
+	})
 	if err != nil {
 		return vm.ArbRollup.ConfirmCall(
 			ctx,
@@ -332,28 +325,28 @@ func (vm *arbRollup) StartChallenge(
 	challengerDataHash common.Hash,
 	challengerPeriodTicks common.TimeTicks,
 ) error {
-	vm.auth.Lock()
-	defer vm.auth.Unlock()
-	tx, err := vm.ArbRollup.StartChallenge(
-		vm.auth.getAuth(ctx),
-		asserterAddress.ToEthAddress(),
-		challengerAddress.ToEthAddress(),
-		prevNode,
-		disputableDeadline,
-		[2]*big.Int{
-			new(big.Int).SetUint64(uint64(asserterPosition)),
-			new(big.Int).SetUint64(uint64(challengerPosition)),
-		},
-		[2][32]byte{
-			asserterVMProtoHash,
-			challengerVMProtoHash,
-		},
-		hashSliceToRaw(asserterProof),
-		hashSliceToRaw(challengerProof),
-		asserterNodeHash,
-		challengerDataHash,
-		challengerPeriodTicks.Val,
-	)
+	tx, err := vm.auth.makeTx(ctx, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return vm.ArbRollup.StartChallenge(
+			auth,
+			asserterAddress.ToEthAddress(),
+			challengerAddress.ToEthAddress(),
+			prevNode,
+			disputableDeadline,
+			[2]*big.Int{
+				new(big.Int).SetUint64(uint64(asserterPosition)),
+				new(big.Int).SetUint64(uint64(challengerPosition)),
+			},
+			[2][32]byte{
+				asserterVMProtoHash,
+				challengerVMProtoHash,
+			},
+			hashSliceToRaw(asserterProof),
+			hashSliceToRaw(challengerProof),
+			asserterNodeHash,
+			challengerDataHash,
+			challengerPeriodTicks.Val,
+		)
+	})
 	if err != nil {
 		return err
 	}
@@ -403,5 +396,6 @@ func (vm *arbRollup) IsStaked(address common.Address) (bool, error) {
 //}
 
 func (vm *arbRollup) waitForReceipt(ctx context.Context, tx *types.Transaction, methodName string) error {
-	return waitForReceipt(ctx, vm.Client, vm.auth.auth.From, tx, methodName)
+	_, err := vm.auth.waitForReceipt(ctx, vm.Client, tx, methodName)
+	return err
 }
@@ -31,12 +31,12 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	ethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/ethclient"
 
 	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
 	"github.com/offchainlabs/arbitrum/packages/arb-util/protocol"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/arbbridge"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/ethbridge/rollup"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/ethutils"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/message"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/valprotocol"
 )
@@ -95,12 +95,12 @@ type ethRollupWatcher struct {
 
 	rollupAddress ethcommon.Address
 	inboxAddress  ethcommon.Address
-	client        *ethclient.Client
+	client        ethutils.EthClient
 }
 
 func newRollupWatcher(
 	rollupAddress ethcommon.Address,
-	client *ethclient.Client,
+	client ethutils.EthClient,
 ) (*ethRollupWatcher, error) {
 	arbitrumRollupContract, err := rollup.NewArbRollup(rollupAddress, client)
 	if err != nil {
@@ -539,21 +539,52 @@ func (vm *ethRollupWatcher) InboxAddress(
 	return common.NewAddressFromEth(addr), err
 }
 
+// GetCreationInfo finds the log emitted when the rollup was created at or
+// after fromBlock, or after the genesis block if fromBlock is nil. Since a
+// rollup is usually created recently compared to the age of the L1 chain,
+// the search starts at the head and pages backwards until it's found. A
+// caller which knows roughly when the rollup was created should pass that as
+// fromBlock, otherwise a missing log is only reported after the whole chain
+// has been searched
 func (con *ethRollupWatcher) GetCreationInfo(
 	ctx context.Context,
+	fromBlock *common.TimeBlocks,
 ) (*common.BlockId, common.Hash, error) {
-	addressIndex := ethcommon.Hash{}
-	copy(
-		addressIndex[:],
-		ethcommon.LeftPadBytes(con.rollupAddress.Bytes(), 32),
+	header, err := con.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, common.Hash{}, err
+	}
+	from := uint64(0)
+	if fromBlock != nil {
+		from = fromBlock.AsInt().Uint64()
+	}
+	if from > header.Number.Uint64() {
+		return nil, common.Hash{}, errors.New("rollup creation search starts after the latest block")
+	}
+	var logs []types.Log
+	err = filterLogsNewestFirst(
+		ctx,
+		con.client,
+		ethereum.FilterQuery{
+			Addresses: []ethcommon.Address{con.rollupAddress},
+			Topics:    [][]ethcommon.Hash{{rollupCreatedID}},
+		},
+		from,
+		header.Number.Uint64(),
+		func(page []types.Log) error {
+			if len(page) == 0 {
+				return nil
+			}
+			logs = page
+			return errStopLogs
+		},
 	)
-	logs, err := con.client.FilterLogs(ctx, ethereum.FilterQuery{
-		Addresses: []ethcommon.Address{con.rollupAddress},
-		Topics:    [][]ethcommon.Hash{{rollupCreatedID}},
-	})
 	if err != nil {
 		return nil, common.Hash{}, err
 	}
+	if len(logs) == 0 {
+		return nil, common.Hash{}, errors.New("rollup creation log not found")
+	}
 	if len(logs) != 1 {
 		return nil,
 			common.Hash{},
@@ -24,13 +24,14 @@ import (
 	errors2 "github.com/pkg/errors"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	ethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/ethclient"
 
 	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/arbbridge"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/ethbridge/executionchallenge"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/ethutils"
 )
 
 var continuedChallengeID ethcommon.Hash
@@ -48,7 +49,7 @@ type bisectionChallenge struct {
 	BisectionChallenge *executionchallenge.BisectionChallenge
 }
 
-func newBisectionChallenge(address ethcommon.Address, client *ethclient.Client, auth *TransactAuth) (*bisectionChallenge, error) {
+func newBisectionChallenge(address ethcommon.Address, client ethutils.EthClient, auth *TransactAuth) (*bisectionChallenge, error) {
 	challenge, err := newChallenge(address, client, auth)
 	if err != nil {
 		return nil, err
@@ -69,16 +70,16 @@ func (c *bisectionChallenge) chooseSegment(
 	segmentToChallenge uint16,
 	segments []common.Hash,
 ) error {
-	c.auth.Lock()
-	defer c.auth.Unlock()
 	tree := NewMerkleTree(segments)
-	tx, err := c.BisectionChallenge.ChooseSegment(
-		c.auth.getAuth(ctx),
-		big.NewInt(int64(segmentToChallenge)),
-		tree.GetProofFlat(int(segmentToChallenge)),
-		tree.GetRoot(),
-		tree.GetNode(int(segmentToChallenge)),
-	)
+	tx, err := c.auth.makeTx(ctx, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return c.BisectionChallenge.ChooseSegment(
+			auth,
+			big.NewInt(int64(segmentToChallenge)),
+			tree.GetProofFlat(int(segmentToChallenge)),
+			tree.GetRoot(),
+			tree.GetNode(int(segmentToChallenge)),
+		)
+	})
 	if err != nil {
 		return c.BisectionChallenge.ChooseSegmentCall(
 			ctx,
@@ -99,7 +100,7 @@ type bisectionChallengeWatcher struct {
 	BisectionChallenge *executionchallenge.BisectionChallenge
 }
 
-func newBisectionChallengeWatcher(address ethcommon.Address, client *ethclient.Client) (*bisectionChallengeWatcher, error) {
+func newBisectionChallengeWatcher(address ethcommon.Address, client ethutils.EthClient) (*bisectionChallengeWatcher, error) {
 	challenge, err := newChallengeWatcher(address, client)
 	if err != nil {
 		return nil, err
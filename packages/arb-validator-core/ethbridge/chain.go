@@ -19,6 +19,7 @@ package ethbridge
 import (
 	"context"
 	"fmt"
+	"log"
 	"math/big"
 	"time"
 
@@ -29,9 +30,9 @@ import (
 	ethereum "github.com/ethereum/go-ethereum"
 	ethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/ethclient"
 
 	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/ethutils"
 )
 
 type ArbAddresses struct {
@@ -71,20 +72,64 @@ func getLogChainInfo(log types.Log) arbbridge.ChainInfo {
 	}
 }
 
-func waitForReceipt(ctx context.Context, client *ethclient.Client, from ethcommon.Address, tx *types.Transaction, methodName string) error {
+func waitForReceipt(ctx context.Context, client ethutils.EthClient, from ethcommon.Address, tx *types.Transaction, methodName string) error {
 	_, err := WaitForReceiptWithResults(ctx, client, from, tx, methodName)
 	return err
 }
-func WaitForReceiptWithResults(ctx context.Context, client *ethclient.Client, from ethcommon.Address, tx *types.Transaction, methodName string) (*types.Receipt, error) {
+
+// WaitForReceiptWithResults waits for tx to be mined and returns its receipt,
+// or an error if the transaction reverted
+func WaitForReceiptWithResults(ctx context.Context, client ethutils.EthClient, from ethcommon.Address, tx *types.Transaction, methodName string) (*types.Receipt, error) {
+	return waitForReceiptWithResubmit(ctx, client, from, tx, methodName, nil)
+}
+
+// resubmitFunc is given the most recently sent version of a transaction
+// which hasn't been mined yet. If the transaction is stuck it sends and
+// returns a replacement with the same nonce, otherwise it returns nil
+type resubmitFunc func(ctx context.Context, tx *types.Transaction, pendingFor time.Duration) (*types.Transaction, error)
+
+func waitForReceiptWithResubmit(
+	ctx context.Context,
+	client ethutils.EthClient,
+	from ethcommon.Address,
+	tx *types.Transaction,
+	methodName string,
+	resubmit resubmitFunc,
+) (*types.Receipt, error) {
+	// Any of the versions of the transaction that were sent could be mined
+	sent := []*types.Transaction{tx}
+	sentAt := time.Now()
 	for {
 		select {
 		case _ = <-time.After(time.Second):
-			receipt, err := client.TransactionReceipt(ctx, tx.Hash())
-			if err != nil {
-				if err.Error() == ethereum.NotFound.Error() {
+			var receipt *types.Receipt
+			for _, candidate := range sent {
+				candidateReceipt, err := client.TransactionReceipt(ctx, candidate.Hash())
+				if err != nil {
+					if err.Error() == ethereum.NotFound.Error() {
+						continue
+					}
+					return nil, err
+				}
+				tx = candidate
+				receipt = candidateReceipt
+				break
+			}
+			if receipt == nil {
+				if resubmit == nil {
 					continue
 				}
-				return nil, err
+				latest := sent[len(sent)-1]
+				replacement, err := resubmit(ctx, latest, time.Since(sentAt))
+				if err != nil {
+					log.Println("Failed to replace stuck", methodName, "transaction", latest.Hash().Hex(), err)
+					continue
+				}
+				if replacement != nil {
+					sent = append(sent, replacement)
+					sentAt = time.Now()
+				}
+				continue
 			}
 			if receipt.Status != 1 {
 				data, err := receipt.MarshalJSON()
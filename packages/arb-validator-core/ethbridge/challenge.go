@@ -24,13 +24,14 @@ import (
 	errors2 "github.com/pkg/errors"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	ethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/ethclient"
 
 	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/arbbridge"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/ethbridge/executionchallenge"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/ethutils"
 )
 
 var initiatedChallengeID ethcommon.Hash
@@ -50,12 +51,12 @@ func init() {
 type challenge struct {
 	Challenge *executionchallenge.Challenge
 
-	client          *ethclient.Client
+	client          ethutils.EthClient
 	auth            *TransactAuth
 	contractAddress ethcommon.Address
 }
 
-func newChallenge(address ethcommon.Address, client *ethclient.Client, auth *TransactAuth) (*challenge, error) {
+func newChallenge(address ethcommon.Address, client ethutils.EthClient, auth *TransactAuth) (*challenge, error) {
 	challengeContract, err := executionchallenge.NewChallenge(address, client)
 	if err != nil {
 		return nil, errors2.Wrap(err, "Failed to connect to ChallengeManager")
@@ -65,9 +66,9 @@ func newChallenge(address ethcommon.Address, client *ethclient.Client, auth *Tra
 }
 
 func (c *challenge) TimeoutChallenge(ctx context.Context) error {
-	c.auth.Lock()
-	defer c.auth.Unlock()
-	tx, err := c.Challenge.TimeoutChallenge(c.auth.getAuth(ctx))
+	tx, err := c.auth.makeTx(ctx, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return c.Challenge.TimeoutChallenge(auth)
+	})
 	if err != nil {
 		return c.Challenge.TimeoutChallengeCall(
 			ctx,
@@ -80,14 +81,15 @@ func (c *challenge) TimeoutChallenge(ctx context.Context) error {
 }
 
 func (c *challenge) waitForReceipt(ctx context.Context, tx *types.Transaction, methodName string) error {
-	return waitForReceipt(ctx, c.client, c.auth.auth.From, tx, methodName)
+	_, err := c.auth.waitForReceipt(ctx, c.client, tx, methodName)
+	return err
 }
 
 type challengeWatcher struct {
 	Challenge *executionchallenge.Challenge
 }
 
-func newChallengeWatcher(address ethcommon.Address, client *ethclient.Client) (*challengeWatcher, error) {
+func newChallengeWatcher(address ethcommon.Address, client ethutils.EthClient) (*challengeWatcher, error) {
 	challengeContract, err := executionchallenge.NewChallenge(address, client)
 	if err != nil {
 		return nil, errors2.Wrap(err, "Failed to connect to ChallengeManager")
@@ -22,20 +22,22 @@ import (
 
 	errors2 "github.com/pkg/errors"
 
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	ethcommon "github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/core/types"
 
 	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/ethbridge/challengetester"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/ethutils"
 )
 
 type ChallengeTester struct {
 	contract *challengetester.ChallengeTester
-	client   *ethclient.Client
+	client   ethutils.EthClient
 	auth     *TransactAuth
 }
 
-func NewChallengeTester(address ethcommon.Address, client *ethclient.Client, auth *TransactAuth) (*ChallengeTester, error) {
+func NewChallengeTester(address ethcommon.Address, client ethutils.EthClient, auth *TransactAuth) (*ChallengeTester, error) {
 	vmCreatorContract, err := challengetester.NewChallengeTester(address, client)
 	if err != nil {
 		return nil, errors2.Wrap(err, "Failed to connect to ChallengeTester")
@@ -51,21 +53,21 @@ func (con *ChallengeTester) StartChallenge(
 	challengeHash common.Hash,
 	challengeType *big.Int,
 ) (common.Address, *common.BlockId, error) {
-	con.auth.Lock()
-	defer con.auth.Unlock()
-	tx, err := con.contract.StartChallenge(
-		con.auth.getAuth(ctx),
-		asserter.ToEthAddress(),
-		challenger.ToEthAddress(),
-		challengePeriod.Val,
-		challengeHash,
-		challengeType,
-	)
+	tx, err := con.auth.makeTx(ctx, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return con.contract.StartChallenge(
+			auth,
+			asserter.ToEthAddress(),
+			challenger.ToEthAddress(),
+			challengePeriod.Val,
+			challengeHash,
+			challengeType,
+		)
+	})
 	if err != nil {
 		return common.Address{}, nil, errors2.Wrap(err, "Failed to call to ChallengeTester.StartChallenge")
 	}
 
-	receipt, err := WaitForReceiptWithResults(ctx, con.client, con.auth.auth.From, tx, "CreateChallenge")
+	receipt, err := con.auth.waitForReceipt(ctx, con.client, tx, "CreateChallenge")
 	if err != nil {
 		return common.Address{}, nil, err
 	}
@@ -0,0 +1,141 @@
+/*
+* Copyright 2020, Offchain Labs, Inc.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*    http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package ethbridge
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/arbbridge"
+)
+
+// EscalationPolicy decides when a transaction which hasn't been mined is
+// stuck and what gas price it should be resubmitted with
+type EscalationPolicy interface {
+	// ReplacementGasPrice is called periodically while tx is waiting to be
+	// mined. It returns the gas price to resubmit tx with, or nil to keep
+	// waiting. pendingFor is the time since tx was sent
+	ReplacementGasPrice(ctx context.Context, tx *types.Transaction, pendingFor time.Duration) (*big.Int, error)
+}
+
+// Nodes reject replacement transactions which don't raise the gas price by at
+// least 10%
+const minBumpPercent = 10
+
+// BumpEscalationPolicy resubmits a transaction with its gas price raised by
+// BumpPercent each time it has been pending for StuckAfter. If MaxGasPrice
+// is set, the gas price is never raised above it
+type BumpEscalationPolicy struct {
+	StuckAfter  time.Duration
+	BumpPercent int64
+	MaxGasPrice *big.Int
+}
+
+var DefaultEscalationPolicy = BumpEscalationPolicy{
+	StuckAfter:  3 * time.Minute,
+	BumpPercent: 15,
+}
+
+func (p BumpEscalationPolicy) ReplacementGasPrice(
+	_ context.Context,
+	tx *types.Transaction,
+	pendingFor time.Duration,
+) (*big.Int, error) {
+	if pendingFor < p.StuckAfter {
+		return nil, nil
+	}
+	return p.bump(tx.GasPrice(), p.BumpPercent), nil
+}
+
+// bump returns price raised by percent or nil if the price can't be raised
+// without exceeding MaxGasPrice
+func (p BumpEscalationPolicy) bump(price *big.Int, percent int64) *big.Int {
+	if percent < minBumpPercent {
+		percent = minBumpPercent
+	}
+	bumped := new(big.Int).Mul(price, big.NewInt(100+percent))
+	bumped.Div(bumped, big.NewInt(100))
+	if bumped.Cmp(price) <= 0 {
+		bumped.Add(price, big.NewInt(1))
+	}
+	if p.MaxGasPrice != nil && bumped.Cmp(p.MaxGasPrice) > 0 {
+		bumped.Set(p.MaxGasPrice)
+	}
+	if bumped.Cmp(price) <= 0 {
+		return nil
+	}
+	return bumped
+}
+
+type deadlineEscalationPolicy struct {
+	BumpEscalationPolicy
+	client arbbridge.ChainTimeGetter
+}
+
+// NewDeadlineEscalationPolicy creates a policy which behaves like base unless
+// the transaction was sent with a context from arbbridge.WithResponseDeadline.
+// In that case it treats the transaction as stuck sooner and raises the gas
+// price faster as the deadline approaches, since missing a challenge
+// deadline loses the challenge
+func NewDeadlineEscalationPolicy(
+	base BumpEscalationPolicy,
+	client arbbridge.ChainTimeGetter,
+) EscalationPolicy {
+	return deadlineEscalationPolicy{
+		BumpEscalationPolicy: base,
+		client:               client,
+	}
+}
+
+func (p deadlineEscalationPolicy) ReplacementGasPrice(
+	ctx context.Context,
+	tx *types.Transaction,
+	pendingFor time.Duration,
+) (*big.Int, error) {
+	deadline, ok := arbbridge.ResponseDeadline(ctx)
+	if !ok {
+		return p.BumpEscalationPolicy.ReplacementGasPrice(ctx, tx, pendingFor)
+	}
+
+	blockId, err := p.client.CurrentBlockId(ctx)
+	if err != nil {
+		return nil, err
+	}
+	timeLeft := deadline.Duration() - common.TicksFromBlockNum(blockId.Height).Duration()
+
+	// Leave time for at least a few replacements before the deadline
+	stuckAfter := p.StuckAfter
+	if quarter := timeLeft / 4; quarter < stuckAfter {
+		stuckAfter = quarter
+	}
+	if minWait := common.NewTimeBlocksInt(1).Duration(); stuckAfter < minWait {
+		stuckAfter = minWait
+	}
+	if pendingFor < stuckAfter {
+		return nil, nil
+	}
+
+	bumpPercent := p.BumpPercent
+	if timeLeft < 2*p.StuckAfter {
+		bumpPercent *= 2
+	}
+	return p.bump(tx.GasPrice(), bumpPercent), nil
+}
@@ -0,0 +1,110 @@
+/*
+* Copyright 2020, Offchain Labs, Inc.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*    http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package ethbridge
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func testTx(gasPrice int64) *types.Transaction {
+	return types.NewTransaction(
+		3,
+		ethcommon.Address{},
+		big.NewInt(0),
+		100000,
+		big.NewInt(gasPrice),
+		nil,
+	)
+}
+
+func TestBumpEscalation(t *testing.T) {
+	policy := BumpEscalationPolicy{
+		StuckAfter:  time.Minute,
+		BumpPercent: 20,
+		MaxGasPrice: big.NewInt(1300),
+	}
+	ctx := context.Background()
+
+	price, err := policy.ReplacementGasPrice(ctx, testTx(1000), 30*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if price != nil {
+		t.Error("replaced tx before it was stuck")
+	}
+
+	price, err = policy.ReplacementGasPrice(ctx, testTx(1000), time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if price == nil || price.Cmp(big.NewInt(1200)) != 0 {
+		t.Error("expected bump to 1200 but got", price)
+	}
+
+	price, err = policy.ReplacementGasPrice(ctx, testTx(1200), time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if price == nil || price.Cmp(big.NewInt(1300)) != 0 {
+		t.Error("expected bump to be capped at 1300 but got", price)
+	}
+
+	price, err = policy.ReplacementGasPrice(ctx, testTx(1300), time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if price != nil {
+		t.Error("replaced tx already at max gas price with", price)
+	}
+}
+
+func TestBumpEscalationMinimum(t *testing.T) {
+	policy := BumpEscalationPolicy{StuckAfter: time.Minute, BumpPercent: 1}
+	price, err := policy.ReplacementGasPrice(context.Background(), testTx(1000), time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if price == nil || price.Cmp(big.NewInt(1100)) != 0 {
+		t.Error("expected bump to at least 1100 but got", price)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	var prices []*big.Int
+	for _, p := range []int64{50, 10, 40, 20, 30} {
+		prices = append(prices, big.NewInt(p))
+	}
+	expected := map[int]int64{
+		0:   10,
+		20:  10,
+		50:  30,
+		60:  30,
+		61:  40,
+		100: 50,
+	}
+	for p, price := range expected {
+		if got := percentile(prices, p); got.Cmp(big.NewInt(price)) != 0 {
+			t.Errorf("percentile %v was %v instead of %v", p, got, price)
+		}
+	}
+}
@@ -21,11 +21,13 @@ import (
 
 	errors2 "github.com/pkg/errors"
 
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	ethcommon "github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/core/types"
 
 	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/ethbridge/executionchallenge"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/ethutils"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/valprotocol"
 )
 
@@ -34,7 +36,7 @@ type executionChallenge struct {
 	challenge *executionchallenge.ExecutionChallenge
 }
 
-func newExecutionChallenge(address ethcommon.Address, client *ethclient.Client, auth *TransactAuth) (*executionChallenge, error) {
+func newExecutionChallenge(address ethcommon.Address, client ethutils.EthClient, auth *TransactAuth) (*executionChallenge, error) {
 	bisectionChallenge, err := newBisectionChallenge(address, client, auth)
 	if err != nil {
 		return nil, err
@@ -67,19 +69,19 @@ func (c *executionChallenge) BisectAssertion(
 		logAccs = append(logAccs, assertion.LastLogHash)
 		gasses = append(gasses, assertion.NumGas)
 	}
-	c.auth.Lock()
-	defer c.auth.Unlock()
-	tx, err := c.challenge.BisectAssertion(
-		c.auth.getAuth(ctx),
-		precondition.BeforeInbox.Hash(),
-		precondition.TimeBounds.AsIntArray(),
-		machineHashes,
-		didInboxInsns,
-		messageAccs,
-		logAccs,
-		gasses,
-		totalSteps,
-	)
+	tx, err := c.auth.makeTx(ctx, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return c.challenge.BisectAssertion(
+			auth,
+			precondition.BeforeInbox.Hash(),
+			precondition.TimeBounds.AsIntArray(),
+			machineHashes,
+			didInboxInsns,
+			messageAccs,
+			logAccs,
+			gasses,
+			totalSteps,
+		)
+	})
 	if err != nil {
 		return c.challenge.BisectAssertionCall(
 			ctx,
@@ -105,22 +107,22 @@ func (c *executionChallenge) OneStepProof(
 	assertion *valprotocol.ExecutionAssertionStub,
 	proof []byte,
 ) error {
-	c.auth.Lock()
-	defer c.auth.Unlock()
-	tx, err := c.challenge.OneStepProof(
-		c.auth.getAuth(ctx),
-		precondition.BeforeHash,
-		precondition.BeforeInbox.Hash(),
-		precondition.TimeBounds.AsIntArray(),
-		assertion.AfterHash,
-		assertion.DidInboxInsn,
-		assertion.FirstMessageHash,
-		assertion.LastMessageHash,
-		assertion.FirstLogHash,
-		assertion.LastLogHash,
-		assertion.NumGas,
-		proof,
-	)
+	tx, err := c.auth.makeTx(ctx, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return c.challenge.OneStepProof(
+			auth,
+			precondition.BeforeHash,
+			precondition.BeforeInbox.Hash(),
+			precondition.TimeBounds.AsIntArray(),
+			assertion.AfterHash,
+			assertion.DidInboxInsn,
+			assertion.FirstMessageHash,
+			assertion.LastMessageHash,
+			assertion.FirstLogHash,
+			assertion.LastLogHash,
+			assertion.NumGas,
+			proof,
+		)
+	})
 	if err != nil {
 		return c.challenge.OneStepProofCall(
 			ctx,
@@ -22,7 +22,6 @@ import (
 	"strings"
 
 	ethereum "github.com/ethereum/go-ethereum"
-	"github.com/ethereum/go-ethereum/ethclient"
 
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
@@ -33,6 +32,7 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	ethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/ethbridge/executionchallenge"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/ethutils"
 )
 
 var bisectedAssertionID ethcommon.Hash
@@ -50,12 +50,12 @@ func init() {
 type executionChallengeWatcher struct {
 	*bisectionChallengeWatcher
 	challenge *executionchallenge.ExecutionChallenge
-	client    *ethclient.Client
+	client    ethutils.EthClient
 	address   ethcommon.Address
 	topics    [][]ethcommon.Hash
 }
 
-func newExecutionChallengeWatcher(address ethcommon.Address, client *ethclient.Client) (*executionChallengeWatcher, error) {
+func newExecutionChallengeWatcher(address ethcommon.Address, client ethutils.EthClient) (*executionChallengeWatcher, error) {
 	bisectionChallenge, err := newBisectionChallengeWatcher(address, client)
 	if err != nil {
 		return nil, err
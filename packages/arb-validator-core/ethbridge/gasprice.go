@@ -0,0 +1,155 @@
+/*
+* Copyright 2020, Offchain Labs, Inc.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*    http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package ethbridge
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/ethutils"
+)
+
+// GasPriceOracle chooses the gas price for transactions sent to L1
+type GasPriceOracle interface {
+	GasPrice(ctx context.Context) (*big.Int, error)
+}
+
+type fixedGasPriceOracle struct {
+	price *big.Int
+}
+
+// NewFixedGasPriceOracle always uses the given gas price
+func NewFixedGasPriceOracle(price *big.Int) GasPriceOracle {
+	return fixedGasPriceOracle{price: new(big.Int).Set(price)}
+}
+
+func (o fixedGasPriceOracle) GasPrice(context.Context) (*big.Int, error) {
+	return new(big.Int).Set(o.price), nil
+}
+
+type nodeGasPriceOracle struct {
+	client ethutils.EthClient
+}
+
+// NewNodeGasPriceOracle uses the gas price suggested by the L1 node
+func NewNodeGasPriceOracle(client ethutils.EthClient) GasPriceOracle {
+	return nodeGasPriceOracle{client: client}
+}
+
+func (o nodeGasPriceOracle) GasPrice(ctx context.Context) (*big.Int, error) {
+	return o.client.SuggestGasPrice(ctx)
+}
+
+type percentileGasPriceOracle struct {
+	sync.Mutex
+	client     ethutils.EthClient
+	blocks     int
+	percentile int
+	maxPrice   *big.Int
+
+	cachedHead  uint64
+	cachedPrice *big.Int
+}
+
+// NewPercentileGasPriceOracle uses the given percentile of the gas prices
+// paid by transactions in the most recent blocks, falling back to the node's
+// suggestion if those blocks are empty. If maxPrice is not nil, the price is
+// capped at maxPrice
+func NewPercentileGasPriceOracle(
+	client ethutils.EthClient,
+	blocks int,
+	percentile int,
+	maxPrice *big.Int,
+) (GasPriceOracle, error) {
+	if blocks <= 0 {
+		return nil, errors.New("gas price oracle must sample at least one block")
+	}
+	if percentile < 0 || percentile > 100 {
+		return nil, errors.New("gas price percentile must be between 0 and 100")
+	}
+	return &percentileGasPriceOracle{
+		client:     client,
+		blocks:     blocks,
+		percentile: percentile,
+		maxPrice:   maxPrice,
+	}, nil
+}
+
+func (o *percentileGasPriceOracle) GasPrice(ctx context.Context) (*big.Int, error) {
+	head, err := o.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	o.Lock()
+	if o.cachedPrice != nil && o.cachedHead == head.Number.Uint64() {
+		price := new(big.Int).Set(o.cachedPrice)
+		o.Unlock()
+		return price, nil
+	}
+	o.Unlock()
+
+	var prices []*big.Int
+	number := new(big.Int).Set(head.Number)
+	for i := 0; i < o.blocks && number.Sign() >= 0; i++ {
+		block, err := o.client.BlockByNumber(ctx, number)
+		if err != nil {
+			return nil, err
+		}
+		for _, tx := range block.Transactions() {
+			prices = append(prices, tx.GasPrice())
+		}
+		number.Sub(number, big.NewInt(1))
+	}
+
+	var price *big.Int
+	if len(prices) == 0 {
+		price, err = o.client.SuggestGasPrice(ctx)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		price = percentile(prices, o.percentile)
+	}
+	if o.maxPrice != nil && price.Cmp(o.maxPrice) > 0 {
+		price = new(big.Int).Set(o.maxPrice)
+	}
+
+	o.Lock()
+	o.cachedHead = head.Number.Uint64()
+	o.cachedPrice = price
+	o.Unlock()
+	return new(big.Int).Set(price), nil
+}
+
+// percentile returns the pth percentile of prices using the nearest rank
+// method. prices must not be empty
+func percentile(prices []*big.Int, p int) *big.Int {
+	sorted := make([]*big.Int, len(prices))
+	copy(sorted, prices)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Cmp(sorted[j]) < 0
+	})
+	index := (len(sorted)*p + 99) / 100
+	if index > 0 {
+		index--
+	}
+	return new(big.Int).Set(sorted[index])
+}
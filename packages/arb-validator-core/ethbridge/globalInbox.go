@@ -30,18 +30,18 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	ethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/ethclient"
 
 	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/ethutils"
 )
 
 type globalInbox struct {
 	GlobalInbox *globalinbox.GlobalInbox
-	client      *ethclient.Client
+	client      ethutils.EthClient
 	auth        *TransactAuth
 }
 
-func newGlobalInbox(address ethcommon.Address, client *ethclient.Client, auth *TransactAuth) (*globalInbox, error) {
+func newGlobalInbox(address ethcommon.Address, client ethutils.EthClient, auth *TransactAuth) (*globalInbox, error) {
 	globalInboxContract, err := globalinbox.NewGlobalInbox(address, client)
 	if err != nil {
 		return nil, errors2.Wrap(err, "Failed to connect to GlobalInbox")
@@ -50,16 +50,16 @@ func newGlobalInbox(address ethcommon.Address, client *ethclient.Client, auth *T
 }
 
 func (con *globalInbox) SendTransactionMessage(ctx context.Context, data []byte, vmAddress common.Address, contactAddress common.Address, amount *big.Int, seqNumber *big.Int) error {
-	con.auth.Lock()
-	defer con.auth.Unlock()
-	tx, err := con.GlobalInbox.SendTransactionMessage(
-		con.auth.getAuth(ctx),
-		vmAddress.ToEthAddress(),
-		contactAddress.ToEthAddress(),
-		seqNumber,
-		amount,
-		data,
-	)
+	tx, err := con.auth.makeTx(ctx, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return con.GlobalInbox.SendTransactionMessage(
+			auth,
+			vmAddress.ToEthAddress(),
+			contactAddress.ToEthAddress(),
+			seqNumber,
+			amount,
+			data,
+		)
+	})
 	if err != nil {
 		return err
 	}
@@ -78,12 +78,13 @@ func (con *globalInbox) deliverTransactionBatch(
 		}
 		data = append(data, tx.ToBytes()...)
 	}
-	con.auth.Lock()
-	return con.GlobalInbox.DeliverTransactionBatch(
-		con.auth.getAuth(ctx),
-		chain.ToEthAddress(),
-		data,
-	)
+	return con.auth.makeTx(ctx, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return con.GlobalInbox.DeliverTransactionBatch(
+			auth,
+			chain.ToEthAddress(),
+			data,
+		)
+	})
 }
 
 func (con *globalInbox) DeliverTransactionBatch(
@@ -95,8 +96,6 @@ func (con *globalInbox) DeliverTransactionBatch(
 	if err != nil {
 		return err
 	}
-	defer con.auth.Unlock()
-
 	return con.waitForReceipt(ctx, tx, "DeliverTransactionBatch")
 }
 
@@ -104,13 +103,12 @@ func (con *globalInbox) DeliverTransactionBatchNoWait(
 	ctx context.Context,
 	chain common.Address,
 	transactions []message.BatchTx,
-) error {
-	_, err := con.deliverTransactionBatch(ctx, chain, transactions)
+) (common.Hash, error) {
+	tx, err := con.deliverTransactionBatch(ctx, chain, transactions)
 	if err != nil {
-		return err
+		return common.Hash{}, err
 	}
-	con.auth.Unlock()
-	return err
+	return common.NewHashFromEth(tx.Hash()), nil
 }
 
 func (con *globalInbox) DepositEthMessage(
@@ -120,17 +118,14 @@ func (con *globalInbox) DepositEthMessage(
 	value *big.Int,
 ) error {
 
-	tx, err := con.GlobalInbox.DepositEthMessage(
-		&bind.TransactOpts{
-			From:     con.auth.auth.From,
-			Signer:   con.auth.auth.Signer,
-			GasLimit: con.auth.auth.GasLimit,
-			Value:    value,
-			Context:  ctx,
-		},
-		vmAddress.ToEthAddress(),
-		destination.ToEthAddress(),
-	)
+	tx, err := con.auth.makeTx(ctx, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		auth.Value = value
+		return con.GlobalInbox.DepositEthMessage(
+			auth,
+			vmAddress.ToEthAddress(),
+			destination.ToEthAddress(),
+		)
+	})
 
 	if err != nil {
 		return err
@@ -146,15 +141,15 @@ func (con *globalInbox) DepositERC20Message(
 	destination common.Address,
 	value *big.Int,
 ) error {
-	con.auth.Lock()
-	defer con.auth.Unlock()
-	tx, err := con.GlobalInbox.DepositERC20Message(
-		con.auth.getAuth(ctx),
-		vmAddress.ToEthAddress(),
-		tokenAddress.ToEthAddress(),
-		destination.ToEthAddress(),
-		value,
-	)
+	tx, err := con.auth.makeTx(ctx, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return con.GlobalInbox.DepositERC20Message(
+			auth,
+			vmAddress.ToEthAddress(),
+			tokenAddress.ToEthAddress(),
+			destination.ToEthAddress(),
+			value,
+		)
+	})
 
 	if err != nil {
 		return err
@@ -170,15 +165,15 @@ func (con *globalInbox) DepositERC721Message(
 	destination common.Address,
 	value *big.Int,
 ) error {
-	con.auth.Lock()
-	defer con.auth.Unlock()
-	tx, err := con.GlobalInbox.DepositERC721Message(
-		con.auth.getAuth(ctx),
-		vmAddress.ToEthAddress(),
-		tokenAddress.ToEthAddress(),
-		destination.ToEthAddress(),
-		value,
-	)
+	tx, err := con.auth.makeTx(ctx, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return con.GlobalInbox.DepositERC721Message(
+			auth,
+			vmAddress.ToEthAddress(),
+			tokenAddress.ToEthAddress(),
+			destination.ToEthAddress(),
+			value,
+		)
+	})
 
 	if err != nil {
 		return err
@@ -200,5 +195,6 @@ func (con *globalInbox) GetTokenBalance(
 }
 
 func (con *globalInbox) waitForReceipt(ctx context.Context, tx *types.Transaction, methodName string) error {
-	return waitForReceipt(ctx, con.client, con.auth.auth.From, tx, methodName)
+	_, err := con.auth.waitForReceipt(ctx, con.client, tx, methodName)
+	return err
 }
@@ -26,10 +26,12 @@ import (
 
 	errors2 "github.com/pkg/errors"
 
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	ethcommon "github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/core/types"
 
 	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/ethutils"
 )
 
 type inboxTopChallenge struct {
@@ -37,7 +39,7 @@ type inboxTopChallenge struct {
 	contract *inboxtopchallenge.InboxTopChallenge
 }
 
-func newInboxTopChallenge(address ethcommon.Address, client *ethclient.Client, auth *TransactAuth) (*inboxTopChallenge, error) {
+func newInboxTopChallenge(address ethcommon.Address, client ethutils.EthClient, auth *TransactAuth) (*inboxTopChallenge, error) {
 	bisectionChallenge, err := newBisectionChallenge(address, client, auth)
 	if err != nil {
 		return nil, err
@@ -54,13 +56,13 @@ func (c *inboxTopChallenge) Bisect(
 	chainHashes []common.Hash,
 	chainLength *big.Int,
 ) error {
-	c.auth.Lock()
-	defer c.auth.Unlock()
-	tx, err := c.contract.Bisect(
-		c.auth.getAuth(ctx),
-		hashSliceToRaw(chainHashes),
-		chainLength,
-	)
+	tx, err := c.auth.makeTx(ctx, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return c.contract.Bisect(
+			auth,
+			hashSliceToRaw(chainHashes),
+			chainLength,
+		)
+	})
 	if err != nil {
 		return c.contract.BisectCall(
 			ctx,
@@ -79,13 +81,13 @@ func (c *inboxTopChallenge) OneStepProof(
 	lowerHashA common.Hash,
 	value common.Hash,
 ) error {
-	c.auth.Lock()
-	defer c.auth.Unlock()
-	tx, err := c.contract.OneStepProof(
-		c.auth.getAuth(ctx),
-		lowerHashA,
-		value,
-	)
+	tx, err := c.auth.makeTx(ctx, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return c.contract.OneStepProof(
+			auth,
+			lowerHashA,
+			value,
+		)
+	})
 	if err != nil {
 		return err
 	}
@@ -25,14 +25,13 @@ import (
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/ethclient"
 )
 
-func (_InboxTopChallenge *InboxTopChallengeTransactor) BisectCall(ctx context.Context, client *ethclient.Client, from common.Address, contractAddress common.Address, _chainHashes [][32]byte, _chainLength *big.Int) error {
+func (_InboxTopChallenge *InboxTopChallengeTransactor) BisectCall(ctx context.Context, client ethutils.EthClient, from common.Address, contractAddress common.Address, _chainHashes [][32]byte, _chainLength *big.Int) error {
 	return callCheck(ctx, client, from, contractAddress, "bisect", _chainHashes, _chainLength)
 }
 
-func callCheck(ctx context.Context, client *ethclient.Client, from common.Address, contractAddress common.Address, method string, params ...interface{}) error {
+func callCheck(ctx context.Context, client ethutils.EthClient, from common.Address, contractAddress common.Address, method string, params ...interface{}) error {
 	contractABI, err := abi.JSON(bytes.NewReader([]byte(InboxTopChallengeABI)))
 	if err != nil {
 		return err
@@ -21,225 +21,112 @@ import (
 	"errors"
 	"log"
 	"math/big"
-
-	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/arbbridge"
+	"strings"
+	"sync/atomic"
 
 	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/ethutils"
 
 	ethereum "github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/ethclient"
 )
 
-type maybeLog struct {
-	log types.Log
-	err error
-}
-
-func getLogs(
-	ctx context.Context,
-	client *ethclient.Client,
-	filter ethereum.FilterQuery,
-	startHeight *common.TimeBlocks,
-	startIndex uint,
-) (<-chan maybeLog, error) {
-	header, err := client.HeaderByNumber(ctx, nil)
-	if err != nil {
-		return nil, err
-	}
-	streamingLogChan := make(chan types.Log)
-	logSub, err := client.SubscribeFilterLogs(ctx, filter, streamingLogChan)
-	if err != nil {
-		return nil, err
-	}
-	logChan := make(chan maybeLog, 1024)
-	go func() {
-		defer close(logChan)
-		defer logSub.Unsubscribe()
-
-		// Get initial old logs
-		filter.FromBlock = startHeight.AsInt()
-		filter.ToBlock = header.Number
-		log.Println("Filter1 from", filter.FromBlock, "to", filter.ToBlock)
-		logs, err := client.FilterLogs(ctx, filter)
-		if err != nil {
-			logChan <- maybeLog{err: err}
-			return
-		}
-		for _, ethLog := range logs {
-			if ethLog.BlockNumber > startHeight.AsInt().Uint64() || ethLog.Index >= startIndex {
-				logChan <- maybeLog{log: ethLog}
-			}
-		}
-
-		// Retrieve for log from stream
-		var ethStreamLog types.Log
-		var ok bool
-		select {
-		case <-ctx.Done():
-			return
-		case ethStreamLog, ok = <-streamingLogChan:
-			if !ok {
-				logChan <- maybeLog{err: errors.New("streamingLogChan terminated early1")}
-				return
-			}
-			log.Println("First stream log", ethStreamLog.BlockNumber)
-		case err := <-logSub.Err():
-			logChan <- maybeLog{err: err}
-			return
-		}
+// Default for the largest block range requested by a single eth_getLogs call.
+// Hosted providers commonly reject larger ranges
+const defaultMaxLogBlockSpan = 5000
 
-		// If there was a gap between initial retrieval and the stream, fill it in
-		if ethStreamLog.BlockNumber > header.Number.Uint64()+1 {
-			filter.FromBlock = new(big.Int).Add(header.Number, big.NewInt(1))
-			filter.ToBlock = new(big.Int).Sub(new(big.Int).SetUint64(ethStreamLog.BlockNumber), big.NewInt(1))
-			log.Println("Filter2 from", filter.FromBlock, "to", filter.ToBlock)
-			logs, err := client.FilterLogs(ctx, filter)
-			if err != nil {
-				logChan <- maybeLog{err: err}
-				return
-			}
-			for _, ethLog := range logs {
-				logChan <- maybeLog{log: ethLog}
-			}
-		}
+var maxLogBlockSpan uint64 = defaultMaxLogBlockSpan
 
-		logChan <- maybeLog{log: ethStreamLog}
+// SetMaxLogBlockSpan sets the largest block range requested when fetching
+// historical logs. Ranges are split into pages of at most this many blocks
+func SetMaxLogBlockSpan(span uint64) {
+	if span == 0 {
+		span = defaultMaxLogBlockSpan
+	}
+	atomic.StoreUint64(&maxLogBlockSpan, span)
+}
 
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case ethLog, ok := <-streamingLogChan:
-				if !ok {
-					logChan <- maybeLog{err: errors.New("streamingLogChan terminated early2")}
-					return
-				}
-				logChan <- maybeLog{log: ethLog}
-			case err := <-logSub.Err():
-				logChan <- maybeLog{err: err}
-				return
-			}
+// isLogRangeError returns whether err is a node rejecting a log query because
+// its block range or result set is too large. The patterns cover the
+// messages of geth and the common hosted providers
+func isLogRangeError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, pattern := range []string{
+		"returned more than",
+		"too many results",
+		"block range",
+		"range too large",
+		"range is too wide",
+		"is limited to",
+		"limit exceeded",
+		"size exceeded",
+	} {
+		if strings.Contains(msg, pattern) {
+			return true
 		}
-	}()
-
-	return logChan, nil
+	}
+	return false
 }
 
-func getEvents(
+// errStopLogs can be returned by a page handler to stop fetching further
+// pages without failing
+var errStopLogs = errors.New("stop fetching logs")
+
+// filterLogsNewestFirst fetches the logs matching filter from blocks from
+// through to, passing each page of logs to handle starting with the page of
+// newest blocks and moving back towards from. Each page covers at most
+// maxLogBlockSpan blocks. If the node rejects a page for having too many
+// results, the page is halved and retried, and the page size grows back
+// after each successful request. It is meant for searching for recent logs,
+// so handle can return errStopLogs once it has found them
+func filterLogsNewestFirst(
 	ctx context.Context,
-	client *ethclient.Client,
+	client ethutils.EthClient,
 	filter ethereum.FilterQuery,
-	startHeight *common.TimeBlocks,
-	startIndex uint,
-	logTransformer func(types.Log) (arbbridge.Event, error),
-) (<-chan arbbridge.MaybeEvent, error) {
-	header, err := client.HeaderByNumber(ctx, nil)
-	if err != nil {
-		return nil, err
-	}
-	streamingLogChan := make(chan types.Log)
-	logSub, err := client.SubscribeFilterLogs(ctx, filter, streamingLogChan)
-	if err != nil {
-		return nil, err
-	}
-	logChan := make(chan arbbridge.MaybeEvent, 1024)
-	go func() {
-		defer close(logChan)
-		defer logSub.Unsubscribe()
-
-		// Get initial old logs
-		filter.FromBlock = startHeight.AsInt()
-		filter.ToBlock = header.Number
-		log.Println("Filter1 from", filter.FromBlock, "to", filter.ToBlock)
+	from uint64,
+	to uint64,
+	handle func([]types.Log) error,
+) error {
+	maxSpan := atomic.LoadUint64(&maxLogBlockSpan)
+	span := maxSpan
+	for from <= to {
+		start := from
+		if to-from >= span {
+			start = to - span + 1
+		}
+		filter.FromBlock = new(big.Int).SetUint64(start)
+		filter.ToBlock = new(big.Int).SetUint64(to)
 		logs, err := client.FilterLogs(ctx, filter)
 		if err != nil {
-			logChan <- arbbridge.MaybeEvent{Err: err}
-			return
-		}
-		for _, ethLog := range logs {
-			if ethLog.BlockNumber > startHeight.AsInt().Uint64() || ethLog.Index >= startIndex {
-				event, err := logTransformer(ethLog)
-				if err != nil {
-					logChan <- arbbridge.MaybeEvent{Err: err}
-					return
-				}
-				logChan <- arbbridge.MaybeEvent{Event: event}
-			}
-		}
-
-		// Retrieve for log from stream
-		var ethStreamLog types.Log
-		var ok bool
-		select {
-		case <-ctx.Done():
-			return
-		case ethStreamLog, ok = <-streamingLogChan:
-			if !ok {
-				logChan <- arbbridge.MaybeEvent{Err: errors.New("streamingLogChan terminated early1")}
-				return
+			if to > start && isLogRangeError(err) {
+				span = (to - start + 1) / 2
+				log.Println("Log query from", start, "to", to, "rejected, retrying with span", span, err)
+				continue
 			}
-			log.Println("First stream log", ethStreamLog.BlockNumber)
-		case err := <-logSub.Err():
-			logChan <- arbbridge.MaybeEvent{Err: err}
-			return
+			return err
 		}
-
-		// If there was a gap between initial retrieval and the stream, fill it in
-		if ethStreamLog.BlockNumber > header.Number.Uint64()+1 {
-			filter.FromBlock = new(big.Int).Add(header.Number, big.NewInt(1))
-			filter.ToBlock = new(big.Int).Sub(new(big.Int).SetUint64(ethStreamLog.BlockNumber), big.NewInt(1))
-			log.Println("Filter2 from", filter.FromBlock, "to", filter.ToBlock)
-			logs, err := client.FilterLogs(ctx, filter)
-			if err != nil {
-				logChan <- arbbridge.MaybeEvent{Err: err}
-				return
-			}
-			for _, ethLog := range logs {
-				event, err := logTransformer(ethLog)
-				if err != nil {
-					logChan <- arbbridge.MaybeEvent{Err: err}
-					return
-				}
-				logChan <- arbbridge.MaybeEvent{Event: event}
-			}
+		if err := handle(logs); err == errStopLogs {
+			return nil
+		} else if err != nil {
+			return err
 		}
-
-		event, err := logTransformer(ethStreamLog)
-		if err != nil {
-			logChan <- arbbridge.MaybeEvent{Err: err}
-			return
+		if start == from {
+			return nil
 		}
-		logChan <- arbbridge.MaybeEvent{Event: event}
-
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case ethLog, ok := <-streamingLogChan:
-				if !ok {
-					logChan <- arbbridge.MaybeEvent{Err: errors.New("streamingLogChan terminated early2")}
-					return
-				}
-				event, err := logTransformer(ethLog)
-				if err != nil {
-					logChan <- arbbridge.MaybeEvent{Err: err}
-					return
-				}
-				logChan <- arbbridge.MaybeEvent{Event: event}
-			case err := <-logSub.Err():
-				logChan <- arbbridge.MaybeEvent{Err: err}
-				return
+		to = start - 1
+		if span < maxSpan {
+			span *= 2
+			if span > maxSpan {
+				span = maxSpan
 			}
 		}
-	}()
-
-	return logChan, nil
+	}
+	return nil
 }
 
 func nextBlockHash(
 	ctx context.Context,
-	client *ethclient.Client,
+	client ethutils.EthClient,
 	prevBlock *common.BlockId,
 ) (*common.BlockId, error) {
 	if prevBlock == nil {
@@ -262,7 +149,7 @@ func nextBlockHash(
 
 func getNextLogs(
 	ctx context.Context,
-	client *ethclient.Client,
+	client ethutils.EthClient,
 	filter ethereum.FilterQuery,
 	prevBlock *common.BlockId,
 ) ([]types.Log, error) {
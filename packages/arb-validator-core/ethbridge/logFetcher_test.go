@@ -0,0 +1,147 @@
+/*
+* Copyright 2020, Offchain Labs, Inc.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*    http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package ethbridge
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/ethutils"
+)
+
+// testLogClient has one log in every block and rejects log queries that
+// would return more than maxResults logs
+type testLogClient struct {
+	ethutils.EthClient
+	maxResults uint64
+	queries    [][2]uint64
+}
+
+func (c *testLogClient) FilterLogs(_ context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	from := query.FromBlock.Uint64()
+	to := query.ToBlock.Uint64()
+	c.queries = append(c.queries, [2]uint64{from, to})
+	if to-from+1 > c.maxResults {
+		return nil, errors.New("query returned more than 10000 results")
+	}
+	var logs []types.Log
+	for i := from; i <= to; i++ {
+		logs = append(logs, types.Log{BlockNumber: i})
+	}
+	return logs, nil
+}
+
+func TestFilterLogsNewestFirstShrinks(t *testing.T) {
+	SetMaxLogBlockSpan(100)
+	defer SetMaxLogBlockSpan(0)
+	client := &testLogClient{maxResults: 30}
+
+	var received []uint64
+	err := filterLogsNewestFirst(context.Background(), client, ethereum.FilterQuery{}, 10, 500, func(logs []types.Log) error {
+		// Each page is in ascending order but the pages go backwards
+		for i := len(logs) - 1; i >= 0; i-- {
+			received = append(received, logs[i].BlockNumber)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(received) != 491 {
+		t.Fatal("received", len(received), "logs instead of 491")
+	}
+	for i, blockNum := range received {
+		if blockNum != 500-uint64(i) {
+			t.Fatal("log", i, "was from block", blockNum)
+		}
+	}
+	for _, query := range client.queries {
+		if query[1]-query[0]+1 > 100 {
+			t.Error("query from", query[0], "to", query[1], "exceeded max span")
+		}
+	}
+}
+
+func TestIsLogRangeError(t *testing.T) {
+	for _, msg := range []string{
+		"query returned more than 10000 results",
+		"Log response size exceeded. You can make eth_getLogs requests with up to a 2K block range",
+		"exceed maximum block range: 5000",
+		"block range is too wide",
+		"eth_getLogs is limited to a 10,000 range",
+	} {
+		if !isLogRangeError(errors.New(msg)) {
+			t.Error("expected range error for", msg)
+		}
+	}
+	for _, msg := range []string{
+		"runtime error: index out of range [3] with length 3",
+		"missing trie node",
+		"context deadline exceeded",
+	} {
+		if isLogRangeError(errors.New(msg)) {
+			t.Error("unexpected range error for", msg)
+		}
+	}
+}
+
+func TestFilterLogsNewestFirst(t *testing.T) {
+	SetMaxLogBlockSpan(100)
+	defer SetMaxLogBlockSpan(0)
+	client := &testLogClient{maxResults: 30}
+
+	received := make(map[uint64]bool)
+	err := filterLogsNewestFirst(context.Background(), client, ethereum.FilterQuery{}, 0, 500, func(logs []types.Log) error {
+		for _, ethLog := range logs {
+			received[ethLog.BlockNumber] = true
+		}
+		if len(received) >= 100 {
+			return errStopLogs
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The pages stop once the newest 100 blocks have been received
+	if len(received) < 100 || len(received) >= 130 {
+		t.Fatal("received", len(received), "logs")
+	}
+	for i := uint64(0); i < uint64(len(received)); i++ {
+		if !received[500-i] {
+			t.Fatal("missing log from block", 500-i)
+		}
+	}
+
+	received = make(map[uint64]bool)
+	err = filterLogsNewestFirst(context.Background(), client, ethereum.FilterQuery{}, 0, 40, func(logs []types.Log) error {
+		for _, ethLog := range logs {
+			received[ethLog.BlockNumber] = true
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(received) != 41 {
+		t.Error("received", len(received), "logs instead of 41")
+	}
+}
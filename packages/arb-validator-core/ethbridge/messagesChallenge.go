@@ -22,11 +22,13 @@ import (
 
 	errors2 "github.com/pkg/errors"
 
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	ethcommon "github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/core/types"
 
 	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/ethbridge/messageschallenge"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/ethutils"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/message"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/valprotocol"
 )
@@ -36,7 +38,7 @@ type messagesChallenge struct {
 	contract *messageschallenge.MessagesChallenge
 }
 
-func newMessagesChallenge(address ethcommon.Address, client *ethclient.Client, auth *TransactAuth) (*messagesChallenge, error) {
+func newMessagesChallenge(address ethcommon.Address, client ethutils.EthClient, auth *TransactAuth) (*messagesChallenge, error) {
 	bisectionChallenge, err := newBisectionChallenge(address, client, auth)
 	if err != nil {
 		return nil, err
@@ -54,14 +56,14 @@ func (c *messagesChallenge) Bisect(
 	segmentHashes []common.Hash,
 	chainLength *big.Int,
 ) error {
-	c.auth.Lock()
-	defer c.auth.Unlock()
-	tx, err := c.contract.Bisect(
-		c.auth.getAuth(ctx),
-		hashSliceToRaw(chainHashes),
-		hashSliceToRaw(segmentHashes),
-		chainLength,
-	)
+	tx, err := c.auth.makeTx(ctx, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return c.contract.Bisect(
+			auth,
+			hashSliceToRaw(chainHashes),
+			hashSliceToRaw(segmentHashes),
+			chainLength,
+		)
+	})
 	if err != nil {
 		return err
 	}
@@ -74,21 +76,21 @@ func (c *messagesChallenge) OneStepProofTransactionMessage(
 	lowerHashB common.Hash,
 	msg message.DeliveredTransaction,
 ) error {
-	c.auth.Lock()
-	defer c.auth.Unlock()
-	tx, err := c.contract.OneStepProofTransactionMessage(
-		c.auth.getAuth(ctx),
-		lowerHashA,
-		lowerHashB,
-		msg.Chain.ToEthAddress(),
-		msg.To.ToEthAddress(),
-		msg.From.ToEthAddress(),
-		msg.SequenceNum,
-		msg.Value,
-		msg.Data,
-		msg.BlockNum.AsInt(),
-		msg.Timestamp,
-	)
+	tx, err := c.auth.makeTx(ctx, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return c.contract.OneStepProofTransactionMessage(
+			auth,
+			lowerHashA,
+			lowerHashB,
+			msg.Chain.ToEthAddress(),
+			msg.To.ToEthAddress(),
+			msg.From.ToEthAddress(),
+			msg.SequenceNum,
+			msg.Value,
+			msg.Data,
+			msg.BlockNum.AsInt(),
+			msg.Timestamp,
+		)
+	})
 	if err != nil {
 		return err
 	}
@@ -101,17 +103,17 @@ func (c *messagesChallenge) OneStepProofTransactionBatchMessage(
 	lowerHashB common.Hash,
 	msg message.DeliveredTransactionBatch,
 ) error {
-	c.auth.Lock()
-	defer c.auth.Unlock()
-	tx, err := c.contract.OneStepProofTransactionBatchMessage(
-		c.auth.getAuth(ctx),
-		lowerHashA,
-		lowerHashB,
-		msg.Chain.ToEthAddress(),
-		msg.TxData,
-		msg.BlockNum.AsInt(),
-		msg.Timestamp,
-	)
+	tx, err := c.auth.makeTx(ctx, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return c.contract.OneStepProofTransactionBatchMessage(
+			auth,
+			lowerHashA,
+			lowerHashB,
+			msg.Chain.ToEthAddress(),
+			msg.TxData,
+			msg.BlockNum.AsInt(),
+			msg.Timestamp,
+		)
+	})
 	if err != nil {
 		return err
 	}
@@ -124,19 +126,19 @@ func (c *messagesChallenge) OneStepProofEthMessage(
 	lowerHashB common.Hash,
 	msg message.DeliveredEth,
 ) error {
-	c.auth.Lock()
-	defer c.auth.Unlock()
-	tx, err := c.contract.OneStepProofEthMessage(
-		c.auth.getAuth(ctx),
-		lowerHashA,
-		lowerHashB,
-		msg.To.ToEthAddress(),
-		msg.From.ToEthAddress(),
-		msg.Value,
-		msg.BlockNum.AsInt(),
-		msg.Timestamp,
-		msg.MessageNum,
-	)
+	tx, err := c.auth.makeTx(ctx, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return c.contract.OneStepProofEthMessage(
+			auth,
+			lowerHashA,
+			lowerHashB,
+			msg.To.ToEthAddress(),
+			msg.From.ToEthAddress(),
+			msg.Value,
+			msg.BlockNum.AsInt(),
+			msg.Timestamp,
+			msg.MessageNum,
+		)
+	})
 	if err != nil {
 		return c.contract.OneStepProofEthMessageCall(
 			ctx,
@@ -162,20 +164,20 @@ func (c *messagesChallenge) OneStepProofERC20Message(
 	lowerHashB common.Hash,
 	msg message.DeliveredERC20,
 ) error {
-	c.auth.Lock()
-	defer c.auth.Unlock()
-	tx, err := c.contract.OneStepProofERC20Message(
-		c.auth.getAuth(ctx),
-		lowerHashA,
-		lowerHashB,
-		msg.To.ToEthAddress(),
-		msg.From.ToEthAddress(),
-		msg.TokenAddress.ToEthAddress(),
-		msg.Value,
-		msg.BlockNum.AsInt(),
-		msg.Timestamp,
-		msg.MessageNum,
-	)
+	tx, err := c.auth.makeTx(ctx, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return c.contract.OneStepProofERC20Message(
+			auth,
+			lowerHashA,
+			lowerHashB,
+			msg.To.ToEthAddress(),
+			msg.From.ToEthAddress(),
+			msg.TokenAddress.ToEthAddress(),
+			msg.Value,
+			msg.BlockNum.AsInt(),
+			msg.Timestamp,
+			msg.MessageNum,
+		)
+	})
 	if err != nil {
 		return err
 	}
@@ -188,20 +190,20 @@ func (c *messagesChallenge) OneStepProofERC721Message(
 	lowerHashB common.Hash,
 	msg message.DeliveredERC721,
 ) error {
-	c.auth.Lock()
-	defer c.auth.Unlock()
-	tx, err := c.contract.OneStepProofERC721Message(
-		c.auth.getAuth(ctx),
-		lowerHashA,
-		lowerHashB,
-		msg.To.ToEthAddress(),
-		msg.From.ToEthAddress(),
-		msg.TokenAddress.ToEthAddress(),
-		msg.Id,
-		msg.BlockNum.AsInt(),
-		msg.Timestamp,
-		msg.MessageNum,
-	)
+	tx, err := c.auth.makeTx(ctx, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return c.contract.OneStepProofERC721Message(
+			auth,
+			lowerHashA,
+			lowerHashB,
+			msg.To.ToEthAddress(),
+			msg.From.ToEthAddress(),
+			msg.TokenAddress.ToEthAddress(),
+			msg.Id,
+			msg.BlockNum.AsInt(),
+			msg.Timestamp,
+			msg.MessageNum,
+		)
+	})
 	if err != nil {
 		return err
 	}
@@ -214,20 +216,20 @@ func (c *messagesChallenge) OneStepProofContractTransactionMessage(
 	lowerHashB common.Hash,
 	msg message.DeliveredContractTransaction,
 ) error {
-	c.auth.Lock()
-	defer c.auth.Unlock()
-	tx, err := c.contract.OneStepProofContractTransactionMessage(
-		c.auth.getAuth(ctx),
-		lowerHashA,
-		lowerHashB,
-		msg.To.ToEthAddress(),
-		msg.From.ToEthAddress(),
-		msg.Value,
-		msg.Data,
-		msg.BlockNum.AsInt(),
-		msg.Timestamp,
-		msg.MessageNum,
-	)
+	tx, err := c.auth.makeTx(ctx, func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return c.contract.OneStepProofContractTransactionMessage(
+			auth,
+			lowerHashA,
+			lowerHashB,
+			msg.To.ToEthAddress(),
+			msg.From.ToEthAddress(),
+			msg.Value,
+			msg.Data,
+			msg.BlockNum.AsInt(),
+			msg.Timestamp,
+			msg.MessageNum,
+		)
+	})
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,674 @@
+/*
+* Copyright 2020, Offchain Labs, Inc.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*    http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package ethbridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/ethutils"
+)
+
+type MultiClientConfig struct {
+	// Quorum is the number of endpoints that must return the same block
+	// hash before a header is used. A quorum of 0 or 1 trusts whichever
+	// endpoint is currently in use
+	Quorum int
+
+	// HealthCheckInterval is how often the head of each endpoint is checked
+	HealthCheckInterval time.Duration
+
+	// MaxLag is the number of blocks an endpoint can fall behind the highest
+	// head reported by any endpoint before it is considered unhealthy
+	MaxLag uint64
+
+	// RequestTimeout bounds each request to a single endpoint so that an
+	// endpoint which stalls is failed over from
+	RequestTimeout time.Duration
+}
+
+var DefaultMultiClientConfig = MultiClientConfig{
+	Quorum:              1,
+	HealthCheckInterval: 15 * time.Second,
+	MaxLag:              5,
+	RequestTimeout:      30 * time.Second,
+}
+
+type endpoint struct {
+	url     string
+	client  *ethclient.Client
+	healthy bool
+	head    uint64
+}
+
+// MultiEthClient implements ethutils.EthClient on top of several ethereum
+// nodes. Requests go to a single healthy endpoint and fail over to the others
+// if it can't be reached. Headers can additionally be required to be
+// confirmed by a quorum of endpoints, which protects against a single
+// lagging or dishonest node
+type MultiEthClient struct {
+	sync.Mutex
+	endpoints []*endpoint
+	primary   int
+	config    MultiClientConfig
+	cancel    context.CancelFunc
+}
+
+var errNoQuorum = errors.New("L1 endpoints did not reach quorum")
+
+// DialMultiEthClient connects to each of urls and starts checking their
+// health in the background until Close is called
+func DialMultiEthClient(ctx context.Context, urls []string, config MultiClientConfig) (*MultiEthClient, error) {
+	if len(urls) == 0 {
+		return nil, errors.New("no L1 endpoints given")
+	}
+	if config.Quorum > len(urls) {
+		return nil, fmt.Errorf("quorum of %v is larger than the %v L1 endpoints given", config.Quorum, len(urls))
+	}
+	endpoints := make([]*endpoint, 0, len(urls))
+	for _, url := range urls {
+		client, err := ethclient.DialContext(ctx, url)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to %v: %v", url, err)
+		}
+		endpoints = append(endpoints, &endpoint{url: url, client: client, healthy: true})
+	}
+	m := &MultiEthClient{
+		endpoints: endpoints,
+		config:    config,
+	}
+	m.checkHealth(ctx)
+
+	healthCtx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+	go func() {
+		ticker := time.NewTicker(config.HealthCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-healthCtx.Done():
+				return
+			case <-ticker.C:
+				m.checkHealth(healthCtx)
+			}
+		}
+	}()
+	return m, nil
+}
+
+// Close stops health checking and closes the connections to all endpoints
+func (m *MultiEthClient) Close() {
+	m.cancel()
+	for _, e := range m.endpoints {
+		e.client.Close()
+	}
+}
+
+// checkHealth fetches the head of every endpoint, marking endpoints which
+// fail or have fallen too far behind as unhealthy
+func (m *MultiEthClient) checkHealth(ctx context.Context) {
+	heads := make([]uint64, len(m.endpoints))
+	errs := make([]error, len(m.endpoints))
+	var wg sync.WaitGroup
+	for i, e := range m.endpoints {
+		wg.Add(1)
+		go func(i int, e *endpoint) {
+			defer wg.Done()
+			reqCtx, cancel := m.requestContext(ctx)
+			defer cancel()
+			header, err := e.client.HeaderByNumber(reqCtx, nil)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			heads[i] = header.Number.Uint64()
+		}(i, e)
+	}
+	wg.Wait()
+
+	var maxHead uint64
+	for i := range m.endpoints {
+		if errs[i] == nil && heads[i] > maxHead {
+			maxHead = heads[i]
+		}
+	}
+
+	m.Lock()
+	defer m.Unlock()
+	for i, e := range m.endpoints {
+		wasHealthy := e.healthy
+		e.healthy = errs[i] == nil && heads[i]+m.config.MaxLag >= maxHead
+		if errs[i] == nil {
+			e.head = heads[i]
+		}
+		if wasHealthy && !e.healthy {
+			if errs[i] != nil {
+				log.Println("L1 endpoint", e.url, "is unhealthy:", errs[i])
+			} else {
+				log.Println("L1 endpoint", e.url, "is lagging at block", heads[i], "while head is", maxHead)
+			}
+		} else if !wasHealthy && e.healthy {
+			log.Println("L1 endpoint", e.url, "is healthy again")
+		}
+	}
+	m.choosePrimary()
+}
+
+// choosePrimary switches to the first healthy endpoint if the current one is
+// unhealthy. m must be locked
+func (m *MultiEthClient) choosePrimary() {
+	if m.endpoints[m.primary].healthy {
+		return
+	}
+	for i, e := range m.endpoints {
+		if e.healthy {
+			log.Println("Failing over from L1 endpoint", m.endpoints[m.primary].url, "to", e.url)
+			m.primary = i
+			return
+		}
+	}
+}
+
+func (m *MultiEthClient) markFailed(e *endpoint, err error) {
+	m.Lock()
+	defer m.Unlock()
+	if e.healthy {
+		log.Println("Request to L1 endpoint", e.url, "failed:", err)
+		e.healthy = false
+	}
+	m.choosePrimary()
+}
+
+// candidates returns the endpoints in the order they should be tried: the
+// primary first, then the other healthy endpoints and finally the unhealthy
+// endpoints in case they have recovered
+func (m *MultiEthClient) candidates() []*endpoint {
+	m.Lock()
+	defer m.Unlock()
+	ordered := make([]*endpoint, 0, len(m.endpoints))
+	ordered = append(ordered, m.endpoints[m.primary])
+	for i, e := range m.endpoints {
+		if i != m.primary && e.healthy {
+			ordered = append(ordered, e)
+		}
+	}
+	for i, e := range m.endpoints {
+		if i != m.primary && !e.healthy {
+			ordered = append(ordered, e)
+		}
+	}
+	return ordered
+}
+
+func (m *MultiEthClient) requestContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if m.config.RequestTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, m.config.RequestTimeout)
+}
+
+// isEndpointFailure returns whether err indicates that the endpoint couldn't
+// handle the request, as opposed to the request itself failing
+func isEndpointFailure(ctx context.Context, err error) bool {
+	if ctx.Err() != nil || err == ethereum.NotFound {
+		return false
+	}
+	if _, ok := err.(rpc.Error); ok {
+		// The endpoint responded with an error, e.g. a reverted call
+		return false
+	}
+	return true
+}
+
+// try calls f with each endpoint in turn until one doesn't fail
+func (m *MultiEthClient) try(ctx context.Context, f func(ctx context.Context, client *ethclient.Client) error) error {
+	var err error
+	for _, e := range m.candidates() {
+		reqCtx, cancel := m.requestContext(ctx)
+		err = f(reqCtx, e.client)
+		cancel()
+		if err == nil || !isEndpointFailure(ctx, err) {
+			return err
+		}
+		m.markFailed(e, err)
+	}
+	return err
+}
+
+// quorumHeader calls getHeader with every endpoint and returns the header
+// returned by at least Quorum of them
+func (m *MultiEthClient) quorumHeader(
+	ctx context.Context,
+	getHeader func(ctx context.Context, client *ethclient.Client) (*types.Header, error),
+) (*types.Header, error) {
+	headers := make([]*types.Header, len(m.endpoints))
+	errs := make([]error, len(m.endpoints))
+	var wg sync.WaitGroup
+	for i, e := range m.endpoints {
+		wg.Add(1)
+		go func(i int, e *endpoint) {
+			defer wg.Done()
+			reqCtx, cancel := m.requestContext(ctx)
+			defer cancel()
+			headers[i], errs[i] = getHeader(reqCtx, e.client)
+		}(i, e)
+	}
+	wg.Wait()
+
+	counts := make(map[ethcommon.Hash]int)
+	notFound := false
+	var lastErr error
+	for i, e := range m.endpoints {
+		switch {
+		case errs[i] == nil:
+			counts[headers[i].Hash()]++
+		case errs[i] == ethereum.NotFound:
+			notFound = true
+		default:
+			lastErr = errs[i]
+			if isEndpointFailure(ctx, errs[i]) {
+				m.markFailed(e, errs[i])
+			}
+		}
+	}
+	if len(counts) > 1 {
+		votes := make(map[string]int)
+		for hash, count := range counts {
+			votes[hash.Hex()] = count
+		}
+		log.Println("L1 endpoints disagree on block hash:", votes)
+	}
+	for i, header := range headers {
+		if errs[i] == nil && counts[header.Hash()] >= m.config.Quorum {
+			return header, nil
+		}
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if notFound {
+		// Some endpoints don't have the block yet
+		return nil, ethereum.NotFound
+	}
+	if lastErr != nil && len(counts) == 0 {
+		return nil, lastErr
+	}
+	return nil, errNoQuorum
+}
+
+// quorumHeight returns the highest block number that at least Quorum
+// endpoints have reached
+func (m *MultiEthClient) quorumHeight(ctx context.Context) (uint64, error) {
+	heights := make([]uint64, 0, len(m.endpoints))
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+	for _, e := range m.endpoints {
+		wg.Add(1)
+		go func(e *endpoint) {
+			defer wg.Done()
+			reqCtx, cancel := m.requestContext(ctx)
+			defer cancel()
+			header, err := e.client.HeaderByNumber(reqCtx, nil)
+			if err != nil {
+				if isEndpointFailure(ctx, err) {
+					m.markFailed(e, err)
+				}
+				return
+			}
+			lock.Lock()
+			heights = append(heights, header.Number.Uint64())
+			lock.Unlock()
+		}(e)
+	}
+	wg.Wait()
+	if len(heights) < m.config.Quorum {
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+		return 0, errNoQuorum
+	}
+	sort.Slice(heights, func(i, j int) bool {
+		return heights[i] > heights[j]
+	})
+	return heights[m.config.Quorum-1], nil
+}
+
+// latestQuorumHeader returns the most recent header that a quorum of
+// endpoints agree on. The endpoints may disagree about the most recent blocks
+// during a reorg or if some of them are dishonest, so up to MaxLag blocks
+// before the quorum height are also tried
+func (m *MultiEthClient) latestQuorumHeader(ctx context.Context) (*types.Header, error) {
+	height, err := m.quorumHeight(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := uint64(0); ; i++ {
+		number := new(big.Int).SetUint64(height - i)
+		header, err := m.quorumHeader(ctx, func(ctx context.Context, client *ethclient.Client) (*types.Header, error) {
+			return client.HeaderByNumber(ctx, number)
+		})
+		if (err != ethereum.NotFound && err != errNoQuorum) || i >= m.config.MaxLag || i >= height {
+			return header, err
+		}
+	}
+}
+
+func (m *MultiEthClient) HeaderByHash(ctx context.Context, hash ethcommon.Hash) (*types.Header, error) {
+	getHeader := func(ctx context.Context, client *ethclient.Client) (*types.Header, error) {
+		return client.HeaderByHash(ctx, hash)
+	}
+	if m.config.Quorum > 1 {
+		return m.quorumHeader(ctx, getHeader)
+	}
+	var header *types.Header
+	err := m.try(ctx, func(ctx context.Context, client *ethclient.Client) error {
+		var err error
+		header, err = getHeader(ctx, client)
+		return err
+	})
+	return header, err
+}
+
+func (m *MultiEthClient) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	if m.config.Quorum > 1 {
+		if number == nil {
+			return m.latestQuorumHeader(ctx)
+		}
+		return m.quorumHeader(ctx, func(ctx context.Context, client *ethclient.Client) (*types.Header, error) {
+			return client.HeaderByNumber(ctx, number)
+		})
+	}
+	var header *types.Header
+	err := m.try(ctx, func(ctx context.Context, client *ethclient.Client) error {
+		var err error
+		header, err = client.HeaderByNumber(ctx, number)
+		return err
+	})
+	return header, err
+}
+
+func (m *MultiEthClient) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	var block *types.Block
+	err := m.try(ctx, func(ctx context.Context, client *ethclient.Client) error {
+		var err error
+		block, err = client.BlockByNumber(ctx, number)
+		return err
+	})
+	return block, err
+}
+
+func (m *MultiEthClient) BalanceAt(ctx context.Context, account ethcommon.Address, blockNumber *big.Int) (*big.Int, error) {
+	var balance *big.Int
+	err := m.try(ctx, func(ctx context.Context, client *ethclient.Client) error {
+		var err error
+		balance, err = client.BalanceAt(ctx, account, blockNumber)
+		return err
+	})
+	return balance, err
+}
+
+func (m *MultiEthClient) ChainID(ctx context.Context) (*big.Int, error) {
+	var chainID *big.Int
+	err := m.try(ctx, func(ctx context.Context, client *ethclient.Client) error {
+		var err error
+		chainID, err = client.ChainID(ctx)
+		return err
+	})
+	return chainID, err
+}
+
+func (m *MultiEthClient) TransactionByHash(ctx context.Context, hash ethcommon.Hash) (*types.Transaction, bool, error) {
+	var tx *types.Transaction
+	var isPending bool
+	err := m.try(ctx, func(ctx context.Context, client *ethclient.Client) error {
+		var err error
+		tx, isPending, err = client.TransactionByHash(ctx, hash)
+		return err
+	})
+	return tx, isPending, err
+}
+
+func (m *MultiEthClient) TransactionReceipt(ctx context.Context, txHash ethcommon.Hash) (*types.Receipt, error) {
+	var receipt *types.Receipt
+	err := m.try(ctx, func(ctx context.Context, client *ethclient.Client) error {
+		var err error
+		receipt, err = client.TransactionReceipt(ctx, txHash)
+		return err
+	})
+	return receipt, err
+}
+
+func (m *MultiEthClient) PendingCallContract(ctx context.Context, msg ethereum.CallMsg) ([]byte, error) {
+	var output []byte
+	err := m.try(ctx, func(ctx context.Context, client *ethclient.Client) error {
+		var err error
+		output, err = client.PendingCallContract(ctx, msg)
+		return err
+	})
+	return output, err
+}
+
+func (m *MultiEthClient) CodeAt(ctx context.Context, contract ethcommon.Address, blockNumber *big.Int) ([]byte, error) {
+	var code []byte
+	err := m.try(ctx, func(ctx context.Context, client *ethclient.Client) error {
+		var err error
+		code, err = client.CodeAt(ctx, contract, blockNumber)
+		return err
+	})
+	return code, err
+}
+
+func (m *MultiEthClient) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	var output []byte
+	err := m.try(ctx, func(ctx context.Context, client *ethclient.Client) error {
+		var err error
+		output, err = client.CallContract(ctx, call, blockNumber)
+		return err
+	})
+	return output, err
+}
+
+func (m *MultiEthClient) PendingCodeAt(ctx context.Context, account ethcommon.Address) ([]byte, error) {
+	var code []byte
+	err := m.try(ctx, func(ctx context.Context, client *ethclient.Client) error {
+		var err error
+		code, err = client.PendingCodeAt(ctx, account)
+		return err
+	})
+	return code, err
+}
+
+func (m *MultiEthClient) PendingNonceAt(ctx context.Context, account ethcommon.Address) (uint64, error) {
+	var nonce uint64
+	err := m.try(ctx, func(ctx context.Context, client *ethclient.Client) error {
+		var err error
+		nonce, err = client.PendingNonceAt(ctx, account)
+		return err
+	})
+	return nonce, err
+}
+
+func (m *MultiEthClient) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	var price *big.Int
+	err := m.try(ctx, func(ctx context.Context, client *ethclient.Client) error {
+		var err error
+		price, err = client.SuggestGasPrice(ctx)
+		return err
+	})
+	return price, err
+}
+
+func (m *MultiEthClient) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	var gas uint64
+	err := m.try(ctx, func(ctx context.Context, client *ethclient.Client) error {
+		var err error
+		gas, err = client.EstimateGas(ctx, call)
+		return err
+	})
+	return gas, err
+}
+
+func (m *MultiEthClient) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	return m.try(ctx, func(ctx context.Context, client *ethclient.Client) error {
+		return client.SendTransaction(ctx, tx)
+	})
+}
+
+// checkLogs returns an error unless each log comes from the block that a
+// quorum of endpoints has at the log's height
+func (m *MultiEthClient) checkLogs(ctx context.Context, logs []types.Log) error {
+	blockHashes := make(map[uint64]ethcommon.Hash)
+	for _, l := range logs {
+		if l.Removed {
+			continue
+		}
+		blockHash, ok := blockHashes[l.BlockNumber]
+		if !ok {
+			number := new(big.Int).SetUint64(l.BlockNumber)
+			header, err := m.quorumHeader(ctx, func(ctx context.Context, client *ethclient.Client) (*types.Header, error) {
+				return client.HeaderByNumber(ctx, number)
+			})
+			if err != nil {
+				return err
+			}
+			blockHash = header.Hash()
+			blockHashes[l.BlockNumber] = blockHash
+		}
+		if l.BlockHash != blockHash {
+			return fmt.Errorf(
+				"log from block %v with hash %v doesn't match quorum hash %v",
+				l.BlockNumber,
+				l.BlockHash.Hex(),
+				blockHash.Hex(),
+			)
+		}
+	}
+	return nil
+}
+
+// checkSubscribedLog is like checkLogs for a single log received from a
+// subscription. Since the log's block may not have reached a quorum of
+// endpoints yet, it's checked again a few times before giving up
+func (m *MultiEthClient) checkSubscribedLog(ctx context.Context, quit <-chan struct{}, l types.Log) error {
+	for attempt := 1; ; attempt++ {
+		err := m.checkLogs(ctx, []types.Log{l})
+		if (err != ethereum.NotFound && err != errNoQuorum) || attempt >= maxFetchAttempts {
+			return err
+		}
+		select {
+		case <-time.After(headerRetryDelay):
+		case <-quit:
+			return err
+		}
+	}
+}
+
+// FilterLogs gets logs from the first endpoint that responds. If Quorum is
+// greater than 1, the logs are only used if they come from blocks that a
+// quorum of endpoints agree on and otherwise the next endpoint is tried
+func (m *MultiEthClient) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	var logs []types.Log
+	err := m.try(ctx, func(reqCtx context.Context, client *ethclient.Client) error {
+		var err error
+		logs, err = client.FilterLogs(reqCtx, query)
+		if err != nil || m.config.Quorum <= 1 {
+			return err
+		}
+		return m.checkLogs(ctx, logs)
+	})
+	return logs, err
+}
+
+// SubscribeFilterLogs subscribes using the first endpoint that supports
+// subscriptions. If the subscription later fails, the endpoint is marked as
+// unhealthy so that the caller's next subscription uses another endpoint. If
+// Quorum is greater than 1, each log is only passed on once a quorum of
+// endpoints agree on its block, and the subscription fails if they don't
+func (m *MultiEthClient) SubscribeFilterLogs(
+	ctx context.Context,
+	query ethereum.FilterQuery,
+	ch chan<- types.Log,
+) (ethereum.Subscription, error) {
+	err := errors.New("no L1 endpoints available")
+	for _, e := range m.candidates() {
+		subChan := ch
+		// unchecked stays nil unless logs need to be checked
+		var unchecked chan types.Log
+		if m.config.Quorum > 1 {
+			unchecked = make(chan types.Log, 100)
+			subChan = unchecked
+		}
+		var sub ethereum.Subscription
+		sub, err = e.client.SubscribeFilterLogs(ctx, query, subChan)
+		if err == rpc.ErrNotificationsUnsupported {
+			continue
+		}
+		if err != nil {
+			if isEndpointFailure(ctx, err) {
+				m.markFailed(e, err)
+				continue
+			}
+			return nil, err
+		}
+		e := e
+		return event.NewSubscription(func(quit <-chan struct{}) error {
+			defer sub.Unsubscribe()
+			for {
+				select {
+				case l := <-unchecked:
+					if err := m.checkSubscribedLog(ctx, quit, l); err != nil {
+						select {
+						case <-quit:
+							return nil
+						default:
+						}
+						m.markFailed(e, err)
+						return err
+					}
+					select {
+					case ch <- l:
+					case <-quit:
+						return nil
+					}
+				case err := <-sub.Err():
+					if err != nil {
+						m.markFailed(e, err)
+					}
+					return err
+				case <-quit:
+					return nil
+				}
+			}
+		}), nil
+	}
+	return nil, err
+}
+
+var _ ethutils.EthClient = (*MultiEthClient)(nil)
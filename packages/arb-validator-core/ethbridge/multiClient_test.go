@@ -0,0 +1,317 @@
+/*
+* Copyright 2020, Offchain Labs, Inc.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*    http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package ethbridge
+
+import (
+	"context"
+	"math/big"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// testChainService serves headers numbered 0 through head. If fork is set,
+// the headers after it differ from those of honest services
+type testChainService struct {
+	head uint64
+	fork *uint64
+}
+
+func (s *testChainService) GetBlockByNumber(number string, _ bool) (*types.Header, error) {
+	height := s.head
+	if number != "latest" {
+		n, err := hexutil.DecodeBig(number)
+		if err != nil {
+			return nil, err
+		}
+		height = n.Uint64()
+	}
+	if height > s.head {
+		return nil, nil
+	}
+	header := &types.Header{
+		Number:     new(big.Int).SetUint64(height),
+		Difficulty: big.NewInt(1),
+	}
+	if s.fork != nil && height > *s.fork {
+		header.Extra = []byte("fork")
+	}
+	return header, nil
+}
+
+func (s *testChainService) GetBlockByHash(hash ethcommon.Hash, full bool) (*types.Header, error) {
+	for i := uint64(0); i <= s.head; i++ {
+		header, err := s.GetBlockByNumber(hexutil.EncodeUint64(i), full)
+		if err != nil {
+			return nil, err
+		}
+		if header.Hash() == hash {
+			return header, nil
+		}
+	}
+	return nil, nil
+}
+
+// GetLogs returns a single log in the block numbered logBlock
+func (s *testChainService) GetLogs(map[string]interface{}) ([]types.Log, error) {
+	return []types.Log{s.testLog()}, nil
+}
+
+// Logs sends the same log as GetLogs to subscribers
+func (s *testChainService) Logs(ctx context.Context, _ map[string]interface{}) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+	sub := notifier.CreateSubscription()
+	go func() {
+		_ = notifier.Notify(sub.ID, s.testLog())
+	}()
+	return sub, nil
+}
+
+const testLogBlock = 9
+
+func (s *testChainService) testLog() types.Log {
+	header, _ := s.GetBlockByNumber(hexutil.EncodeUint64(testLogBlock), false)
+	return types.Log{
+		Address:     ethcommon.Address{1},
+		Topics:      []ethcommon.Hash{},
+		Data:        []byte{},
+		BlockNumber: testLogBlock,
+		BlockHash:   header.Hash(),
+	}
+}
+
+func startTestChain(t *testing.T, service *testChainService) *httptest.Server {
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", service); err != nil {
+		t.Fatal(err)
+	}
+	return httptest.NewServer(server)
+}
+
+func startTestWebsocketChain(t *testing.T, service *testChainService) *httptest.Server {
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", service); err != nil {
+		t.Fatal(err)
+	}
+	return httptest.NewServer(server.WebsocketHandler([]string{"*"}))
+}
+
+func dialTestChains(t *testing.T, quorum int, servers ...*httptest.Server) *MultiEthClient {
+	urls := make([]string, 0, len(servers))
+	for _, server := range servers {
+		urls = append(urls, server.URL)
+	}
+	config := DefaultMultiClientConfig
+	config.Quorum = quorum
+	config.HealthCheckInterval = time.Hour
+	client, err := DialMultiEthClient(context.Background(), urls, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestMultiClientFailover(t *testing.T) {
+	server1 := startTestChain(t, &testChainService{head: 10})
+	server2 := startTestChain(t, &testChainService{head: 10})
+	defer server2.Close()
+	client := dialTestChains(t, 1, server1, server2)
+	defer client.Close()
+
+	server1.Close()
+	header, err := client.HeaderByNumber(context.Background(), big.NewInt(5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header.Number.Uint64() != 5 {
+		t.Error("got header", header.Number, "instead of 5")
+	}
+	if client.endpoints[0].healthy {
+		t.Error("closed endpoint still considered healthy")
+	}
+	if client.primary != 1 {
+		t.Error("didn't fail over to working endpoint")
+	}
+}
+
+func TestMultiClientLagging(t *testing.T) {
+	server1 := startTestChain(t, &testChainService{head: 3})
+	defer server1.Close()
+	server2 := startTestChain(t, &testChainService{head: 20})
+	defer server2.Close()
+	client := dialTestChains(t, 1, server1, server2)
+	defer client.Close()
+
+	if client.endpoints[0].healthy || client.primary != 1 {
+		t.Error("lagging endpoint still used")
+	}
+}
+
+func TestMultiClientQuorum(t *testing.T) {
+	fork := uint64(8)
+	server1 := startTestChain(t, &testChainService{head: 10})
+	defer server1.Close()
+	server2 := startTestChain(t, &testChainService{head: 12})
+	defer server2.Close()
+	server3 := startTestChain(t, &testChainService{head: 12, fork: &fork})
+	defer server3.Close()
+	client := dialTestChains(t, 2, server1, server2, server3)
+	defer client.Close()
+	ctx := context.Background()
+
+	header, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header.Number.Uint64() != 10 {
+		t.Error("latest header with quorum was", header.Number, "instead of 10")
+	}
+
+	// The forked endpoint is outvoted
+	header, err = client.HeaderByNumber(ctx, big.NewInt(9))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(header.Extra) != 0 {
+		t.Error("used header from forked endpoint")
+	}
+
+	// Only one honest endpoint has block 11
+	if _, err := client.HeaderByNumber(ctx, big.NewInt(11)); err != ethereum.NotFound {
+		t.Error("expected block without quorum to be not found but got", err)
+	}
+
+	if _, err := client.HeaderByHash(ctx, header.Hash()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMultiClientNoQuorum(t *testing.T) {
+	fork := uint64(8)
+	server1 := startTestChain(t, &testChainService{head: 10})
+	defer server1.Close()
+	server2 := startTestChain(t, &testChainService{head: 10, fork: &fork})
+	defer server2.Close()
+	client := dialTestChains(t, 2, server1, server2)
+	defer client.Close()
+
+	if _, err := client.HeaderByNumber(context.Background(), big.NewInt(9)); err != errNoQuorum {
+		t.Error("expected no quorum but got", err)
+	}
+}
+
+func TestMultiClientFilterLogsQuorum(t *testing.T) {
+	fork := uint64(8)
+	honest := &testChainService{head: 10}
+	forked := startTestChain(t, &testChainService{head: 10, fork: &fork})
+	defer forked.Close()
+	server2 := startTestChain(t, honest)
+	defer server2.Close()
+	server3 := startTestChain(t, &testChainService{head: 10})
+	defer server3.Close()
+	client := dialTestChains(t, 2, forked, server2, server3)
+	defer client.Close()
+
+	logs, err := client.FilterLogs(context.Background(), ethereum.FilterQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != 1 || logs[0].BlockHash != honest.testLog().BlockHash {
+		t.Error("used logs from forked endpoint", logs)
+	}
+	if client.endpoints[0].healthy {
+		t.Error("forked endpoint still considered healthy")
+	}
+
+	noQuorum := dialTestChains(t, 2, forked, server2)
+	defer noQuorum.Close()
+	if _, err := noQuorum.FilterLogs(context.Background(), ethereum.FilterQuery{}); err == nil {
+		t.Error("used logs without quorum")
+	}
+}
+
+func TestMultiClientSubscribeLogsQuorum(t *testing.T) {
+	fork := uint64(8)
+	honest := &testChainService{head: 10}
+	server1 := startTestWebsocketChain(t, honest)
+	defer server1.Close()
+	server2 := startTestChain(t, &testChainService{head: 10})
+	defer server2.Close()
+	forked := startTestWebsocketChain(t, &testChainService{head: 10, fork: &fork})
+	defer forked.Close()
+
+	wsURL := func(server *httptest.Server) string {
+		return "ws" + strings.TrimPrefix(server.URL, "http")
+	}
+	config := DefaultMultiClientConfig
+	config.Quorum = 2
+	config.HealthCheckInterval = time.Hour
+	ctx := context.Background()
+
+	client, err := DialMultiEthClient(ctx, []string{wsURL(server1), server2.URL}, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	logs := make(chan types.Log, 1)
+	sub, err := client.SubscribeFilterLogs(ctx, ethereum.FilterQuery{}, logs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case l := <-logs:
+		if l.BlockHash != honest.testLog().BlockHash {
+			t.Error("wrong log", l)
+		}
+	case err := <-sub.Err():
+		t.Fatal(err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("didn't receive log")
+	}
+	sub.Unsubscribe()
+
+	forkedClient, err := DialMultiEthClient(ctx, []string{wsURL(forked), server2.URL, wsURL(server1)}, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer forkedClient.Close()
+	sub, err = forkedClient.SubscribeFilterLogs(ctx, ethereum.FilterQuery{}, logs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Unsubscribe()
+	select {
+	case l := <-logs:
+		t.Error("received log from forked endpoint", l)
+	case err := <-sub.Err():
+		if err == nil {
+			t.Error("subscription ended without error")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("subscription with forked log didn't fail")
+	}
+}
@@ -0,0 +1,156 @@
+/*
+* Copyright 2020, Offchain Labs, Inc.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*    http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package ethbridge
+
+import (
+	"context"
+	"log"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Number of times a transaction is retried with a fresh nonce after the node
+// rejects its nonce
+const maxNonceRetries = 3
+
+// PendingNonceGetter looks up the next nonce for an account including
+// transactions waiting in the node's transaction pool
+type PendingNonceGetter interface {
+	PendingNonceAt(ctx context.Context, account ethcommon.Address) (uint64, error)
+}
+
+// nonceManager assigns nonces to the transactions sent from one account.
+// Transactions are sent one at a time so that a transaction which fails to
+// send never leaves a gap in the account's nonces
+type nonceManager struct {
+	sync.Mutex
+	client  PendingNonceGetter
+	account ethcommon.Address
+
+	// next is the nonce for the next transaction. It is only valid if synced
+	// is true, otherwise it must be fetched from the node
+	next   uint64
+	synced bool
+}
+
+func newNonceManager(client PendingNonceGetter, account ethcommon.Address) *nonceManager {
+	return &nonceManager{
+		client:  client,
+		account: account,
+	}
+}
+
+// resync causes the next nonce to be fetched from the node. This is
+// necessary after a reorg since transactions may have been dropped
+func (n *nonceManager) resync() {
+	n.Lock()
+	n.synced = false
+	n.Unlock()
+}
+
+// send calls sendTx with a copy of opts set to use the account's next nonce.
+// If the node rejects the nonce, the nonce is refreshed and sendTx is retried.
+// If the node already has the signed transaction, it is returned as sent
+func (n *nonceManager) send(
+	ctx context.Context,
+	opts *bind.TransactOpts,
+	sendTx func(*bind.TransactOpts) (*types.Transaction, error),
+) (*types.Transaction, error) {
+	n.Lock()
+	defer n.Unlock()
+	for attempt := 0; ; attempt++ {
+		if !n.synced {
+			nonce, err := n.client.PendingNonceAt(ctx, n.account)
+			if err != nil {
+				return nil, err
+			}
+			n.next = nonce
+			n.synced = true
+		}
+
+		// Keep the signed transaction so that it can be returned if the node
+		// reports that it already has it
+		var signed *types.Transaction
+		txOpts := *opts
+		txOpts.Nonce = new(big.Int).SetUint64(n.next)
+		if opts.Signer != nil {
+			txOpts.Signer = func(signer types.Signer, addr ethcommon.Address, tx *types.Transaction) (*types.Transaction, error) {
+				signedTx, err := opts.Signer(signer, addr, tx)
+				if err == nil {
+					signed = signedTx
+				}
+				return signedTx, err
+			}
+		}
+		tx, err := sendTx(&txOpts)
+		if err == nil {
+			n.next++
+			return tx, nil
+		}
+		if isAlreadyKnown(err) && signed != nil {
+			// This exact transaction was already sent, for example before a
+			// transport error or by another client in a failover, so it
+			// mustn't be sent again with a new nonce
+			log.Println("Transaction", signed.Hash().Hex(), "already known for", n.account.Hex())
+			n.next++
+			return signed, nil
+		}
+		if attempt >= maxNonceRetries {
+			return nil, err
+		}
+
+		switch {
+		case isNonceTooLow(err):
+			// Another sender used the nonce or our view is out of date
+			log.Println("Nonce", n.next, "too low for", n.account.Hex(), "resyncing")
+			n.synced = false
+		case isNonceInUse(err):
+			// A different transaction we lost track of is already waiting in
+			// the pool with this nonce, so skip past it
+			log.Println("Nonce", n.next, "already in use for", n.account.Hex())
+			nonce, err := n.client.PendingNonceAt(ctx, n.account)
+			if err != nil {
+				return nil, err
+			}
+			if nonce <= n.next {
+				nonce = n.next + 1
+			}
+			n.next = nonce
+		default:
+			return nil, err
+		}
+	}
+}
+
+func isNonceTooLow(err error) bool {
+	return strings.Contains(err.Error(), "nonce too low")
+}
+
+func isNonceInUse(err error) bool {
+	return strings.Contains(err.Error(), "replacement transaction underpriced")
+}
+
+func isAlreadyKnown(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "already known") ||
+		strings.Contains(msg, "known transaction")
+}
@@ -0,0 +1,193 @@
+/*
+* Copyright 2020, Offchain Labs, Inc.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*    http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package ethbridge
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+type testNonceGetter struct {
+	nonce uint64
+}
+
+func (g *testNonceGetter) PendingNonceAt(context.Context, ethcommon.Address) (uint64, error) {
+	return g.nonce, nil
+}
+
+// sendWithNonce returns a send function which records the nonce it was
+// called with and fails with err if it is set
+func sendWithNonce(used *[]uint64, err error) func(*bind.TransactOpts) (*types.Transaction, error) {
+	return func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		*used = append(*used, opts.Nonce.Uint64())
+		if err != nil {
+			return nil, err
+		}
+		return testTx(1), nil
+	}
+}
+
+func checkNonces(t *testing.T, used []uint64, expected ...uint64) {
+	t.Helper()
+	if len(used) != len(expected) {
+		t.Fatalf("used nonces %v instead of %v", used, expected)
+	}
+	for i := range used {
+		if used[i] != expected[i] {
+			t.Fatalf("used nonces %v instead of %v", used, expected)
+		}
+	}
+}
+
+func TestNonceIncrement(t *testing.T) {
+	ctx := context.Background()
+	n := newNonceManager(&testNonceGetter{nonce: 5}, ethcommon.Address{})
+	var used []uint64
+	for i := 0; i < 3; i++ {
+		if _, err := n.send(ctx, &bind.TransactOpts{}, sendWithNonce(&used, nil)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A failed send must not use up a nonce
+	if _, err := n.send(ctx, &bind.TransactOpts{}, sendWithNonce(&used, errors.New("out of gas"))); err == nil {
+		t.Fatal("expected send to fail")
+	}
+	if _, err := n.send(ctx, &bind.TransactOpts{}, sendWithNonce(&used, nil)); err != nil {
+		t.Fatal(err)
+	}
+	checkNonces(t, used, 5, 6, 7, 8, 8)
+}
+
+func TestNonceRecovery(t *testing.T) {
+	ctx := context.Background()
+	getter := &testNonceGetter{nonce: 2}
+	n := newNonceManager(getter, ethcommon.Address{})
+	var used []uint64
+
+	// Another sender used nonces 2 through 3
+	failures := 0
+	sendTx := func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		used = append(used, opts.Nonce.Uint64())
+		if failures == 0 {
+			failures++
+			getter.nonce = 4
+			return nil, errors.New("nonce too low")
+		}
+		return testTx(1), nil
+	}
+	if _, err := n.send(ctx, &bind.TransactOpts{}, sendTx); err != nil {
+		t.Fatal(err)
+	}
+	checkNonces(t, used, 2, 4)
+
+	// A transaction we don't know about is pending with nonce 5
+	used = nil
+	failures = 0
+	sendTx = func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		used = append(used, opts.Nonce.Uint64())
+		if failures == 0 {
+			failures++
+			return nil, errors.New("replacement transaction underpriced")
+		}
+		return testTx(1), nil
+	}
+	if _, err := n.send(ctx, &bind.TransactOpts{}, sendTx); err != nil {
+		t.Fatal(err)
+	}
+	checkNonces(t, used, 5, 6)
+
+	// After a reorg the node's view of the nonce is used again
+	getter.nonce = 3
+	n.resync()
+	used = nil
+	if _, err := n.send(ctx, &bind.TransactOpts{}, sendWithNonce(&used, nil)); err != nil {
+		t.Fatal(err)
+	}
+	checkNonces(t, used, 3)
+}
+
+func TestNonceAlreadyKnown(t *testing.T) {
+	ctx := context.Background()
+	n := newNonceManager(&testNonceGetter{nonce: 3}, ethcommon.Address{})
+	opts := &bind.TransactOpts{
+		Signer: func(_ types.Signer, _ ethcommon.Address, tx *types.Transaction) (*types.Transaction, error) {
+			return tx, nil
+		},
+	}
+	var used []uint64
+	sent := 0
+	// The node already received the transaction, for example before a
+	// transport error made the first send look like it failed
+	sendTx := func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		used = append(used, opts.Nonce.Uint64())
+		sent++
+		tx := types.NewTransaction(opts.Nonce.Uint64(), ethcommon.Address{}, big.NewInt(0), 100000, big.NewInt(1), nil)
+		if _, err := opts.Signer(nil, ethcommon.Address{}, tx); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("already known")
+	}
+	tx, err := n.send(ctx, opts, sendTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx == nil || tx.Nonce() != 3 {
+		t.Fatal("expected the signed transaction to be returned but got", tx)
+	}
+	if _, err := n.send(ctx, opts, sendWithNonce(&used, nil)); err != nil {
+		t.Fatal(err)
+	}
+	checkNonces(t, used, 3, 4)
+	if sent != 1 {
+		t.Error("transaction was sent", sent, "times")
+	}
+}
+
+func TestMakeTxReleasesLocksOnError(t *testing.T) {
+	ctx := context.Background()
+	auth := &TransactAuth{
+		auth:   &bind.TransactOpts{},
+		nonces: newNonceManager(&testNonceGetter{nonce: 1}, ethcommon.Address{}),
+	}
+	var used []uint64
+	if _, err := auth.makeTx(ctx, sendWithNonce(&used, errors.New("insufficient funds"))); err == nil {
+		t.Fatal("expected send to fail")
+	}
+
+	// Sending after a failure must not block on a lock left held by it
+	done := make(chan error, 1)
+	go func() {
+		_, err := auth.makeTx(ctx, sendWithNonce(&used, nil))
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("send blocked after a failed send")
+	}
+	checkNonces(t, used, 1, 1)
+}
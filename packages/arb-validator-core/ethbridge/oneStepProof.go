@@ -24,18 +24,18 @@ import (
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	ethcommon "github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/ethclient"
 
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/ethbridge/executionchallenge"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/ethutils"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/valprotocol"
 )
 
 type oneStepProof struct {
 	contract *executionchallenge.OneStepProof
-	client   *ethclient.Client
+	client   ethutils.EthClient
 }
 
-func newOneStepProof(address ethcommon.Address, client *ethclient.Client) (*oneStepProof, error) {
+func newOneStepProof(address ethcommon.Address, client ethutils.EthClient) (*oneStepProof, error) {
 	contract, err := executionchallenge.NewOneStepProof(address, client)
 	if err != nil {
 		return nil, errors2.Wrap(err, "Failed to connect to oneStepProof")
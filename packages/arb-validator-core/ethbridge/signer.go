@@ -0,0 +1,230 @@
+/*
+* Copyright 2020, Offchain Labs, Inc.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*    http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package ethbridge
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	errors2 "github.com/pkg/errors"
+)
+
+// Signer signs the transactions sent from a single account
+type Signer interface {
+	// Address is the account whose transactions are signed
+	Address() ethcommon.Address
+
+	// SignTx signs tx. signer is the signing scheme requested by the caller,
+	// though remote signers may use their own configured scheme
+	SignTx(signer types.Signer, tx *types.Transaction) (*types.Transaction, error)
+}
+
+// NewTransactOpts creates transaction options which sign with signer. The
+// result can be passed to NewEthAuthClient
+func NewTransactOpts(signer Signer) *bind.TransactOpts {
+	return &bind.TransactOpts{
+		From: signer.Address(),
+		Signer: func(s types.Signer, address ethcommon.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if address != signer.Address() {
+				return nil, errors.New("not authorized to sign this account")
+			}
+			return signer.SignTx(s, tx)
+		},
+	}
+}
+
+type keySigner struct {
+	key     *ecdsa.PrivateKey
+	address ethcommon.Address
+}
+
+// NewKeySigner signs with a private key held in memory
+func NewKeySigner(key *ecdsa.PrivateKey) Signer {
+	return keySigner{
+		key:     key,
+		address: crypto.PubkeyToAddress(key.PublicKey),
+	}
+}
+
+func (s keySigner) Address() ethcommon.Address {
+	return s.address
+}
+
+func (s keySigner) SignTx(signer types.Signer, tx *types.Transaction) (*types.Transaction, error) {
+	return types.SignTx(tx, signer, s.key)
+}
+
+type keystoreSigner struct {
+	ks      *keystore.KeyStore
+	account accounts.Account
+}
+
+// NewKeystoreSigner signs with an account from a go-ethereum keystore. The
+// account must already be unlocked
+func NewKeystoreSigner(ks *keystore.KeyStore, account accounts.Account) Signer {
+	return keystoreSigner{ks: ks, account: account}
+}
+
+func (s keystoreSigner) Address() ethcommon.Address {
+	return s.account.Address
+}
+
+func (s keystoreSigner) SignTx(signer types.Signer, tx *types.Transaction) (*types.Transaction, error) {
+	signature, err := s.ks.SignHash(s.account, signer.Hash(tx).Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return tx.WithSignature(signer, signature)
+}
+
+// Time allowed for a remote signer to respond, which may include waiting for
+// an operator to approve the transaction
+var remoteSignerTimeout = time.Minute
+
+type remoteSigner struct {
+	client  *rpc.Client
+	address ethcommon.Address
+	chainID *big.Int
+}
+
+// remoteTxArgs matches the transaction argument of the account_signTransaction
+// method of Clef compatible signers
+type remoteTxArgs struct {
+	From     ethcommon.MixedcaseAddress  `json:"from"`
+	To       *ethcommon.MixedcaseAddress `json:"to"`
+	Gas      hexutil.Uint64              `json:"gas"`
+	GasPrice hexutil.Big                 `json:"gasPrice"`
+	Value    hexutil.Big                 `json:"value"`
+	Nonce    hexutil.Uint64              `json:"nonce"`
+	Data     *hexutil.Bytes              `json:"data"`
+	ChainID  *hexutil.Big                `json:"chainId,omitempty"`
+}
+
+type remoteSignResult struct {
+	Raw hexutil.Bytes `json:"raw"`
+}
+
+// NewRemoteSigner signs by sending transactions to a Clef compatible signer
+// over JSON-RPC at url so that the key never has to be stored on this host.
+// If address is the zero address, the first account listed by the signer is
+// used. Transactions are signed for chainID and are rejected if the signer
+// returns one that differs from the transaction it was sent
+func NewRemoteSigner(ctx context.Context, url string, address ethcommon.Address, chainID *big.Int) (Signer, error) {
+	client, err := rpc.DialContext(ctx, url)
+	if err != nil {
+		return nil, errors2.Wrap(err, "Failed to connect to remote signer")
+	}
+	var addresses []ethcommon.Address
+	if err := client.CallContext(ctx, &addresses, "account_list"); err != nil {
+		return nil, errors2.Wrap(err, "Failed to list remote signer accounts")
+	}
+	if address == (ethcommon.Address{}) {
+		if len(addresses) == 0 {
+			return nil, errors.New("remote signer has no accounts")
+		}
+		address = addresses[0]
+	} else {
+		found := false
+		for _, account := range addresses {
+			if account == address {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, errors.New("remote signer does not have account " + address.Hex())
+		}
+	}
+	return remoteSigner{client: client, address: address, chainID: chainID}, nil
+}
+
+func (s remoteSigner) Address() ethcommon.Address {
+	return s.address
+}
+
+func (s remoteSigner) SignTx(_ types.Signer, tx *types.Transaction) (*types.Transaction, error) {
+	data := hexutil.Bytes(tx.Data())
+	args := remoteTxArgs{
+		From:     ethcommon.NewMixedcaseAddress(s.address),
+		Gas:      hexutil.Uint64(tx.Gas()),
+		GasPrice: hexutil.Big(*tx.GasPrice()),
+		Value:    hexutil.Big(*tx.Value()),
+		Nonce:    hexutil.Uint64(tx.Nonce()),
+		Data:     &data,
+		ChainID:  (*hexutil.Big)(s.chainID),
+	}
+	if tx.To() != nil {
+		to := ethcommon.NewMixedcaseAddress(*tx.To())
+		args.To = &to
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), remoteSignerTimeout)
+	defer cancel()
+	var result remoteSignResult
+	if err := s.client.CallContext(ctx, &result, "account_signTransaction", &args); err != nil {
+		return nil, errors2.Wrap(err, "Remote signer failed to sign transaction")
+	}
+	signed := new(types.Transaction)
+	if err := rlp.DecodeBytes(result.Raw, signed); err != nil {
+		return nil, errors2.Wrap(err, "Remote signer returned invalid transaction")
+	}
+	if err := s.checkSigned(tx, signed); err != nil {
+		return nil, err
+	}
+	return signed, nil
+}
+
+// checkSigned returns an error unless signed is tx signed by the signer's
+// account for its chain
+func (s remoteSigner) checkSigned(tx, signed *types.Transaction) error {
+	sameTo := (tx.To() == nil && signed.To() == nil) ||
+		(tx.To() != nil && signed.To() != nil && *tx.To() == *signed.To())
+	if !sameTo ||
+		signed.Nonce() != tx.Nonce() ||
+		signed.Gas() != tx.Gas() ||
+		signed.GasPrice().Cmp(tx.GasPrice()) != 0 ||
+		signed.Value().Cmp(tx.Value()) != 0 ||
+		!bytes.Equal(signed.Data(), tx.Data()) {
+		return errors.New("remote signer modified transaction")
+	}
+	if !signed.Protected() || signed.ChainId().Cmp(s.chainID) != 0 {
+		return fmt.Errorf("remote signer signed transaction for chain %v instead of %v", signed.ChainId(), s.chainID)
+	}
+	sender, err := types.Sender(types.NewEIP155Signer(s.chainID), signed)
+	if err != nil {
+		return errors2.Wrap(err, "Remote signer returned invalid signature")
+	}
+	if sender != s.address {
+		return fmt.Errorf("remote signer signed transaction with %v instead of %v", sender.Hex(), s.address.Hex())
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+/*
+* Copyright 2020, Offchain Labs, Inc.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*    http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package ethbridge
+
+import (
+	"context"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// testSignerService implements the subset of the Clef API used by the remote
+// signer. If modify is set, it's applied to each transaction before it's
+// signed
+type testSignerService struct {
+	signer  Signer
+	chainID *big.Int
+	modify  func(*types.Transaction) *types.Transaction
+}
+
+func (s *testSignerService) List() []ethcommon.Address {
+	return []ethcommon.Address{s.signer.Address()}
+}
+
+func (s *testSignerService) SignTransaction(args remoteTxArgs) (*remoteSignResult, error) {
+	tx := types.NewTransaction(
+		uint64(args.Nonce),
+		args.To.Address(),
+		args.Value.ToInt(),
+		uint64(args.Gas),
+		args.GasPrice.ToInt(),
+		*args.Data,
+	)
+	if s.modify != nil {
+		tx = s.modify(tx)
+	}
+	signed, err := s.signer.SignTx(types.NewEIP155Signer(s.chainID), tx)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := rlp.EncodeToBytes(signed)
+	if err != nil {
+		return nil, err
+	}
+	return &remoteSignResult{Raw: raw}, nil
+}
+
+func checkSigner(t *testing.T, signer Signer, chainSigner types.Signer) {
+	t.Helper()
+	auth := NewTransactOpts(signer)
+	tx := types.NewTransaction(7, ethcommon.Address{1}, big.NewInt(10), 21000, big.NewInt(1000), []byte{1, 2, 3})
+	signed, err := auth.Signer(types.HomesteadSigner{}, auth.From, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender, err := types.Sender(chainSigner, signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sender != signer.Address() {
+		t.Error("transaction signed by", sender.Hex(), "instead of", signer.Address().Hex())
+	}
+	if _, err := auth.Signer(types.HomesteadSigner{}, ethcommon.Address{2}, tx); err == nil {
+		t.Error("signed transaction for wrong account")
+	}
+}
+
+func TestKeySigner(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSigner(t, NewKeySigner(key), types.HomesteadSigner{})
+}
+
+func TestRemoteSigner(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := rpc.NewServer()
+	service := &testSignerService{signer: NewKeySigner(key), chainID: big.NewInt(1)}
+	if err := server.RegisterName("account", service); err != nil {
+		t.Fatal(err)
+	}
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	ctx := context.Background()
+	signer, err := NewRemoteSigner(ctx, httpServer.URL, ethcommon.Address{}, big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSigner(t, signer, types.NewEIP155Signer(big.NewInt(1)))
+
+	if _, err := NewRemoteSigner(ctx, httpServer.URL, ethcommon.Address{3}, big.NewInt(1)); err == nil {
+		t.Error("created remote signer for unknown account")
+	}
+}
+
+func TestRemoteSignerRejectsModifiedTx(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := rpc.NewServer()
+	service := &testSignerService{signer: NewKeySigner(key), chainID: big.NewInt(1)}
+	if err := server.RegisterName("account", service); err != nil {
+		t.Fatal(err)
+	}
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	signer, err := NewRemoteSigner(context.Background(), httpServer.URL, ethcommon.Address{}, big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rebuild := func(to ethcommon.Address, value int64, gas uint64, data []byte) func(*types.Transaction) *types.Transaction {
+		return func(tx *types.Transaction) *types.Transaction {
+			return types.NewTransaction(tx.Nonce(), to, big.NewInt(value), gas, tx.GasPrice(), data)
+		}
+	}
+	tests := []struct {
+		name    string
+		signer  Signer
+		chainID *big.Int
+		modify  func(*types.Transaction) *types.Transaction
+	}{
+		{"to", service.signer, big.NewInt(1), rebuild(ethcommon.Address{9}, 10, 21000, []byte{1, 2, 3})},
+		{"value", service.signer, big.NewInt(1), rebuild(ethcommon.Address{1}, 11, 21000, []byte{1, 2, 3})},
+		{"gas", service.signer, big.NewInt(1), rebuild(ethcommon.Address{1}, 10, 30000, []byte{1, 2, 3})},
+		{"data", service.signer, big.NewInt(1), rebuild(ethcommon.Address{1}, 10, 21000, []byte{4})},
+		{"chain", service.signer, big.NewInt(2), nil},
+		{"sender", NewKeySigner(otherKey), big.NewInt(1), nil},
+	}
+	tx := types.NewTransaction(7, ethcommon.Address{1}, big.NewInt(10), 21000, big.NewInt(1000), []byte{1, 2, 3})
+	for _, test := range tests {
+		service.signer = test.signer
+		service.chainID = test.chainID
+		service.modify = test.modify
+		if _, err := signer.SignTx(types.HomesteadSigner{}, tx); err == nil {
+			t.Error("accepted transaction with modified", test.name)
+		}
+	}
+}
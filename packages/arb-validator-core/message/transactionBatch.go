@@ -95,6 +95,20 @@ func NewBatchTxFromData(data []byte, offset int) (BatchTx, error) {
 	}, nil
 }
 
+// Transaction returns the transaction that the VM receives when this batch
+// tx from the given sender is delivered to the chain. Its ReceiptHash is the
+// hash that the tx's result is reported under
+func (b BatchTx) Transaction(chain common.Address, from common.Address) Transaction {
+	return Transaction{
+		Chain:       chain,
+		To:          b.To,
+		From:        from,
+		SequenceNum: b.SeqNum,
+		Value:       b.Value,
+		Data:        b.Data,
+	}
+}
+
 func (b BatchTx) encodedLength() int {
 	return DataOffset + len(b.Data)
 }
@@ -140,17 +154,8 @@ func (m DeliveredTransactionBatch) getTransactions() []DeliveredTransaction {
 		}
 
 		from := common.NewAddressFromEth(crypto.PubkeyToAddress(*pubkey))
-		tx := Transaction{
-			Chain:       m.Chain,
-			To:          batch.To,
-			From:        from,
-			SequenceNum: batch.SeqNum,
-			Value:       batch.Value,
-			Data:        batch.Data,
-		}
-
 		txes = append(txes, DeliveredTransaction{
-			Transaction: tx,
+			Transaction: batch.Transaction(m.Chain, from),
 			BlockNum:    m.BlockNum,
 			Timestamp:   m.Timestamp,
 		})
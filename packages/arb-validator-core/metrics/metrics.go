@@ -0,0 +1,74 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package metrics holds the registry that validator and aggregator components
+// report their metrics to, and serves it in the Prometheus text format.
+//
+// Metric names use slashes as separators (e.g. "arbitrum/rollup/blocks")
+// which are converted to underscores when exported.
+package metrics
+
+import (
+	"net/http"
+
+	gethmetrics "github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/metrics/prometheus"
+)
+
+func init() {
+	// go-ethereum hands out no-op metrics unless collection is enabled, and
+	// it must be enabled before any package level metrics are constructed.
+	// Go initializes this package before any package that imports it so
+	// every metric below is real.
+	gethmetrics.Enabled = true
+}
+
+// Registry contains every metric created through this package
+var Registry = gethmetrics.NewRegistry()
+
+func NewCounter(name string) gethmetrics.Counter {
+	return gethmetrics.NewRegisteredCounter(name, Registry)
+}
+
+func NewGauge(name string) gethmetrics.Gauge {
+	return gethmetrics.NewRegisteredGauge(name, Registry)
+}
+
+func NewTimer(name string) gethmetrics.Timer {
+	return gethmetrics.NewRegisteredTimer(name, Registry)
+}
+
+// NewHistogram creates a histogram which tracks the distribution of recent
+// values using the same exponentially decaying sample as NewTimer
+func NewHistogram(name string) gethmetrics.Histogram {
+	return gethmetrics.NewRegisteredHistogram(
+		name,
+		Registry,
+		gethmetrics.NewExpDecaySample(1028, 0.015),
+	)
+}
+
+// Handler serves the contents of Registry in the Prometheus text format
+func Handler() http.Handler {
+	return prometheus.Handler(Registry)
+}
+
+// LaunchMetrics serves Handler at /metrics on the given port
+func LaunchMetrics(port string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
+	return http.ListenAndServe(":"+port, mux)
+}
@@ -0,0 +1,51 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metrics
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandler(t *testing.T) {
+	counter := NewCounter("test/handler/counter")
+	counter.Inc(3)
+	gauge := NewGauge("test/handler/gauge")
+	gauge.Update(7)
+	timer := NewTimer("test/handler/timer")
+	timer.Update(time.Millisecond)
+	histogram := NewHistogram("test/handler/histogram")
+	histogram.Update(12)
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body := rec.Body.String()
+
+	expected := []string{
+		"test_handler_counter 3",
+		"test_handler_gauge 7",
+		"test_handler_timer_count 1",
+		"test_handler_histogram_count 1",
+	}
+	for _, line := range expected {
+		if !strings.Contains(body, line) {
+			t.Errorf("metrics output missing %q:\n%v", line, body)
+		}
+	}
+}
@@ -75,6 +75,10 @@ func (c *ArbClient) GetBalance(ctx context.Context, account common.Address) (*bi
 	return nil, errors.New("unimplemented")
 }
 
+func (c *ArbClient) GetTxStatus(ctx context.Context, txHash common.Hash) (arbbridge.TxStatus, error) {
+	return arbbridge.TxSucceeded, nil
+}
+
 func (c *ArbClient) CurrentBlockId(ctx context.Context) (*common.BlockId, error) {
 	return c.client.CurrentBlockId(ctx)
 }
@@ -227,7 +227,7 @@ func (vm *EthRollupWatcher) InboxAddress(ctx context.Context) (common.Address, e
 	return common.Address{}, nil
 }
 
-func (vm *EthRollupWatcher) GetCreationInfo(ctx context.Context) (*common.BlockId, common.Hash, error) {
+func (vm *EthRollupWatcher) GetCreationInfo(ctx context.Context, fromBlock *common.TimeBlocks) (*common.BlockId, common.Hash, error) {
 	return nil, common.Hash{}, nil
 }
 
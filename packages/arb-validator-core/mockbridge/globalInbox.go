@@ -62,8 +62,8 @@ func (con *GlobalInbox) DeliverTransactionBatchNoWait(
 	ctx context.Context,
 	chain common.Address,
 	transactions []message.BatchTx,
-) error {
-	return nil
+) (common.Hash, error) {
+	return common.Hash{}, nil
 }
 
 func (con *GlobalInbox) DepositEthMessage(
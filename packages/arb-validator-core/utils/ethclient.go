@@ -0,0 +1,65 @@
+/*
+* Copyright 2020, Offchain Labs, Inc.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*    http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package utils
+
+import (
+	"context"
+	"flag"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/ethbridge"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/ethutils"
+)
+
+type EthClientFlags struct {
+	quorum     *int
+	maxLogSpan *uint64
+}
+
+func AddEthClientFlags(fs *flag.FlagSet) EthClientFlags {
+	quorum := fs.Int(
+		"eth-quorum",
+		1,
+		"eth-quorum=NumEndpoints",
+	)
+	maxLogSpan := fs.Uint64(
+		"eth-max-log-span",
+		5000,
+		"eth-max-log-span=NumBlocks",
+	)
+	return EthClientFlags{quorum: quorum, maxLogSpan: maxLogSpan}
+}
+
+// DialEthClient connects to the ethereum node at ethURL. If ethURL is a comma
+// separated list of URLs, the returned client fails over between them and
+// only uses block headers that at least "eth-quorum" of them agree on. Log
+// queries over a range of blocks request at most "eth-max-log-span" blocks at
+// a time
+func DialEthClient(ctx context.Context, ethURL string, args EthClientFlags) (ethutils.EthClient, error) {
+	ethbridge.SetMaxLogBlockSpan(*args.maxLogSpan)
+	urls := strings.Split(ethURL, ",")
+	if len(urls) == 1 && *args.quorum <= 1 {
+		return ethclient.DialContext(ctx, ethURL)
+	}
+	config := ethbridge.DefaultMultiClientConfig
+	config.Quorum = *args.quorum
+	return ethbridge.DialMultiEthClient(ctx, urls, config)
+}
+
+const EthClientArgsString = "[--eth-quorum=NumEndpoints] [--eth-max-log-span=NumBlocks]"
@@ -17,26 +17,73 @@
 package utils
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
 	"flag"
 	"fmt"
+	"io/ioutil"
 	"math"
 	"math/big"
+	"os"
 	"path/filepath"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/accounts/keystore"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	errors2 "github.com/pkg/errors"
 	"golang.org/x/crypto/ssh/terminal"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/ethbridge"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/ethutils"
 )
 
 type WalletFlags struct {
-	passphrase *string
-	gasPrice   *float64
+	signer             *string
+	privateKeyFile     *string
+	signerURL          *string
+	signerAccount      *string
+	passphrase         *string
+	gasPrice           *float64
+	gasPriceStrategy   *string
+	gasPricePercentile *int
+	maxGasPrice        *float64
+	stuckAfter         *time.Duration
 }
 
+// Number of recent blocks sampled by the percentile gas price strategy
+const gasPriceSampleBlocks = 20
+
+// PrivateKeyEnv is the environment variable read by the "key" signer when no
+// private key file is given
+const PrivateKeyEnv = "ARB_PRIVATE_KEY"
+
 func AddFlags(fs *flag.FlagSet) WalletFlags {
+	signer := fs.String(
+		"signer",
+		"keystore",
+		"signer=keystore|key|remote",
+	)
+	privateKeyFile := fs.String(
+		"private-key-file",
+		"",
+		"private-key-file=Path",
+	)
+	signerURL := fs.String(
+		"signer-url",
+		"",
+		"signer-url=URL",
+	)
+	signerAccount := fs.String(
+		"signer-account",
+		"",
+		"signer-account=Address",
+	)
 	passphrase := fs.String(
 		"password",
 		"",
@@ -48,12 +95,173 @@ func AddFlags(fs *flag.FlagSet) WalletFlags {
 		"gasprice=FloatInGwei",
 	)
 
+	gasPriceStrategy := fs.String(
+		"gasprice-strategy",
+		"fixed",
+		"gasprice-strategy=fixed|node|percentile",
+	)
+	gasPricePercentile := fs.Int(
+		"gasprice-percentile",
+		60,
+		"gasprice-percentile=Percentile",
+	)
+	maxGasPrice := fs.Float64(
+		"gasprice-max",
+		0,
+		"gasprice-max=FloatInGwei",
+	)
+	stuckAfter := fs.Duration(
+		"tx-stuck-after",
+		ethbridge.DefaultEscalationPolicy.StuckAfter,
+		"tx-stuck-after=Duration",
+	)
+
 	return WalletFlags{
-		passphrase: passphrase,
-		gasPrice:   gasPrice,
+		signer:             signer,
+		privateKeyFile:     privateKeyFile,
+		signerURL:          signerURL,
+		signerAccount:      signerAccount,
+		passphrase:         passphrase,
+		gasPrice:           gasPrice,
+		gasPriceStrategy:   gasPriceStrategy,
+		gasPricePercentile: gasPricePercentile,
+		maxGasPrice:        maxGasPrice,
+		stuckAfter:         stuckAfter,
 	}
 }
 
+func gweiToWei(gwei float64) *big.Int {
+	wei := 1e9 * gwei
+	if wei >= math.MaxInt64 {
+		return big.NewInt(math.MaxInt64)
+	}
+	return big.NewInt(int64(wei))
+}
+
+// ConfigureGasPricing sets how client prices its transactions and replaces
+// stuck ones. The "fixed" strategy uses the "gasprice" argument, "node" uses
+// the price suggested by the ethereum node and "percentile" uses the given
+// percentile of prices paid in recent blocks. If "gasprice-max" is set, it
+// caps the percentile strategy and the price of replacement transactions.
+func ConfigureGasPricing(
+	args WalletFlags,
+	ethclint ethutils.EthClient,
+	client *ethbridge.EthArbAuthClient,
+) error {
+	var maxGasPrice *big.Int
+	if *args.maxGasPrice > 0 {
+		maxGasPrice = gweiToWei(*args.maxGasPrice)
+	}
+
+	var oracle ethbridge.GasPriceOracle
+	switch *args.gasPriceStrategy {
+	case "fixed":
+		oracle = ethbridge.NewFixedGasPriceOracle(gweiToWei(*args.gasPrice))
+	case "node":
+		oracle = ethbridge.NewNodeGasPriceOracle(ethclint)
+	case "percentile":
+		var err error
+		oracle, err = ethbridge.NewPercentileGasPriceOracle(
+			ethclint,
+			gasPriceSampleBlocks,
+			*args.gasPricePercentile,
+			maxGasPrice,
+		)
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown gas price strategy %v", *args.gasPriceStrategy)
+	}
+	client.SetGasPriceOracle(oracle)
+
+	escalation := ethbridge.DefaultEscalationPolicy
+	escalation.StuckAfter = *args.stuckAfter
+	escalation.MaxGasPrice = maxGasPrice
+	client.SetEscalationPolicy(ethbridge.NewDeadlineEscalationPolicy(escalation, client))
+	return nil
+}
+
+// GetTransactAuth returns a transaction authorization using the signer chosen
+// by the "signer" command line argument. The "keystore" signer uses the
+// keystore in validatorFolder as described in GetKeystore. The "key" signer
+// uses a hex encoded private key read from the "private-key-file" argument or
+// from the ARB_PRIVATE_KEY environment variable and is intended for testing.
+// The "remote" signer sends transactions to be signed by a Clef compatible
+// signer at "signer-url" using the "signer-account" account, or the signer's
+// first account if none is given. Transactions it signs are checked against
+// the chain ID reported by ethclint.
+func GetTransactAuth(
+	ctx context.Context,
+	validatorFolder string,
+	args WalletFlags,
+	flags *flag.FlagSet,
+	ethclint ethutils.EthClient,
+) (*bind.TransactOpts, error) {
+	var signer ethbridge.Signer
+	switch *args.signer {
+	case "keystore":
+		return GetKeystore(validatorFolder, args, flags)
+	case "key":
+		var key *ecdsa.PrivateKey
+		var err error
+		if *args.privateKeyFile != "" {
+			key, err = ReadPrivateKeyFile(*args.privateKeyFile)
+		} else {
+			hexKey := os.Getenv(PrivateKeyEnv)
+			if hexKey == "" {
+				return nil, fmt.Errorf("key signer requires --private-key-file or %v", PrivateKeyEnv)
+			}
+			key, err = parsePrivateKey(hexKey)
+		}
+		if err != nil {
+			return nil, err
+		}
+		signer = ethbridge.NewKeySigner(key)
+	case "remote":
+		if *args.signerURL == "" {
+			return nil, errors.New("remote signer requires --signer-url")
+		}
+		var account ethcommon.Address
+		if *args.signerAccount != "" {
+			if !ethcommon.IsHexAddress(*args.signerAccount) {
+				return nil, fmt.Errorf("invalid signer account %v", *args.signerAccount)
+			}
+			account = ethcommon.HexToAddress(*args.signerAccount)
+		}
+		chainID, err := ethclint.ChainID(ctx)
+		if err != nil {
+			return nil, errors2.Wrap(err, "failed to get chain id")
+		}
+		signer, err = ethbridge.NewRemoteSigner(ctx, *args.signerURL, account, chainID)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown signer %v", *args.signer)
+	}
+	auth := ethbridge.NewTransactOpts(signer)
+	auth.GasPrice = gweiToWei(*args.gasPrice)
+	return auth, nil
+}
+
+// ReadPrivateKeyFile reads a hex encoded private key from path
+func ReadPrivateKeyFile(path string) (*ecdsa.PrivateKey, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parsePrivateKey(string(data))
+}
+
+func parsePrivateKey(hexKey string) (*ecdsa.PrivateKey, error) {
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(strings.TrimSpace(hexKey), "0x"))
+	if err != nil {
+		return nil, errors2.Wrap(err, "invalid private key")
+	}
+	return key, nil
+}
+
 // GetKeystore returns a transaction authorization based on an existing ethereum
 // keystore located in validatorFolder/wallets or creates one if it does not
 // exist. It accepts a password using the "password" command line argument or
@@ -110,10 +318,7 @@ func GetKeystore(
 	if err != nil {
 		return nil, err
 	}
-	auth, err := bind.NewKeyStoreTransactor(ks, account)
-	if err != nil {
-		return nil, err
-	}
+	auth := ethbridge.NewTransactOpts(ethbridge.NewKeystoreSigner(ks, account))
 
 	gasPriceAsFloat := 1e9 * (*args.gasPrice)
 	if gasPriceAsFloat < math.MaxInt64 {
@@ -122,4 +327,8 @@ func GetKeystore(
 	return auth, nil
 }
 
-const WalletArgsString = "[--password=pass] [--gasprice==FloatInGwei]"
+const WalletArgsString = "[--signer=keystore|key|remote] [--private-key-file=Path] " +
+	"[--signer-url=URL] [--signer-account=Address] " +
+	"[--password=pass] [--gasprice==FloatInGwei] " +
+	"[--gasprice-strategy=fixed|node|percentile] [--gasprice-percentile=Percentile] " +
+	"[--gasprice-max=FloatInGwei] [--tx-stuck-after=Duration]"
@@ -23,6 +23,7 @@ import (
 
 	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/arbbridge"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/metrics"
 )
 
 type ChallengeState uint8
@@ -38,6 +39,8 @@ var replayTimeout = time.Second
 
 var challengeNoEvents = errors.New("challenge event channel terminated unexpectedly")
 
+var bisectionCounter = metrics.NewCounter("arbitrum/challenges/bisections")
+
 func getSegmentCount(count, segments, index uint64) uint64 {
 	if index == 0 {
 		return count/segments + count%segments
@@ -64,11 +67,22 @@ func getAfterState(event arbbridge.Event) ChallengeState {
 	return ChallengeContinuing
 }
 
+// recordEvent updates challenge metrics for an event read from the challenge
+func recordEvent(event arbbridge.Event) {
+	switch event.(type) {
+	case arbbridge.InboxTopBisectionEvent,
+		arbbridge.MessagesBisectionEvent,
+		arbbridge.ExecutionBisectionEvent:
+		bisectionCounter.Inc(1)
+	}
+}
+
 func getNextEvent(eventChan <-chan arbbridge.Event) (arbbridge.Event, ChallengeState, error) {
 	event, ok := <-eventChan
 	if !ok {
 		return nil, 0, challengeNoEvents
 	}
+	recordEvent(event)
 	return event, getAfterState(event), nil
 }
 
@@ -100,6 +114,7 @@ func getNextEventWithTimeout(
 			if !ok {
 				return nil, 0, challengeNoEvents
 			}
+			recordEvent(event)
 			return event, getAfterState(event), nil
 		}
 	}
@@ -112,6 +127,7 @@ func getNextEventIfExists(ctx context.Context, eventChan <-chan arbbridge.Event,
 			if !ok {
 				return false, nil, 0, challengeNoEvents
 			}
+			recordEvent(event)
 			return false, event, getAfterState(event), nil
 		case <-time.After(timeout):
 			return true, nil, 0, nil
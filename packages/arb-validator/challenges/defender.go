@@ -82,16 +82,23 @@ func (ad AssertionDefender) SolidityOneStepProof() ([]byte, error) {
 	return ad.initState.MarshalForProof()
 }
 
+// ChooseAssertionToChallenge executes the segments of a bisected assertion
+// from m and asks strategy which segment to challenge. It returns the index of
+// that segment and the machine state that segment starts from
 func ChooseAssertionToChallenge(
 	m machine.Machine,
 	pre *valprotocol.Precondition,
 	assertions []*valprotocol.ExecutionAssertionStub,
 	totalSteps uint64,
+	strategy ChallengeStrategy,
 ) (uint16, machine.Machine, error) {
 	assertionCount := uint64(len(assertions))
+	initStates := make([]machine.Machine, 0, assertionCount)
+	firstInvalid := uint64(0)
+	found := false
 	for i := range assertions {
 		steps := valprotocol.CalculateBisectionStepCount(uint64(i), assertionCount, totalSteps)
-		initState := m.Clone()
+		initStates = append(initStates, m.Clone())
 		generatedAssertion, numSteps := m.ExecuteAssertion(
 			steps,
 			pre.TimeBounds,
@@ -100,9 +107,18 @@ func ChooseAssertionToChallenge(
 		)
 		stub := valprotocol.NewExecutionAssertionStubFromAssertion(generatedAssertion)
 		if uint64(numSteps) != steps || !stub.Equals(assertions[i]) {
-			return uint16(i), initState, nil
+			firstInvalid = uint64(i)
+			found = true
+			break
 		}
 		pre = pre.GeneratePostcondition(stub)
 	}
-	return 0, nil, errors.New("all segments in false ExecutionAssertion are valid")
+	choice, ok := strategy.ChooseSegment(assertionCount, firstInvalid, found)
+	if !ok {
+		return 0, nil, errors.New("all segments in false ExecutionAssertion are valid")
+	}
+	if choice >= uint64(len(initStates)) {
+		return 0, nil, errors.New("challenge strategy chose a segment after the first invalid segment")
+	}
+	return uint16(choice), initStates[choice], nil
 }
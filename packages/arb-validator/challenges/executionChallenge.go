@@ -20,7 +20,6 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"math/rand"
 
 	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
 	"github.com/offchainlabs/arbitrum/packages/arb-util/machine"
@@ -38,7 +37,7 @@ func DefendExecutionClaim(
 	precondition *valprotocol.Precondition,
 	startMachine machine.Machine,
 	numSteps uint64,
-	bisectionCount uint32,
+	strategy ChallengeStrategy,
 ) (ChallengeState, error) {
 	contractWatcher, err := client.NewExecutionChallengeWatcher(address)
 	if err != nil {
@@ -65,7 +64,7 @@ func DefendExecutionClaim(
 			numSteps,
 			startMachine,
 		),
-		bisectionCount,
+		strategy,
 	)
 }
 
@@ -77,7 +76,7 @@ func ChallengeExecutionClaim(
 	startLogIndex uint,
 	startPrecondition *valprotocol.Precondition,
 	startMachine machine.Machine,
-	challengeEverything bool,
+	strategy ChallengeStrategy,
 ) (ChallengeState, error) {
 	contractWatcher, err := client.NewExecutionChallengeWatcher(address)
 	if err != nil {
@@ -98,7 +97,7 @@ func ChallengeExecutionClaim(
 		client,
 		startMachine,
 		startPrecondition,
-		challengeEverything,
+		strategy,
 	)
 }
 
@@ -108,23 +107,30 @@ func defendExecution(
 	contract arbbridge.ExecutionChallenge,
 	client arbbridge.ArbClient,
 	startDefender AssertionDefender,
-	bisectionCount uint32,
+	strategy ChallengeStrategy,
 ) (ChallengeState, error) {
 	event, ok := <-eventChan
 	if !ok {
 		return 0, challengeNoEvents
 	}
-	_, ok = event.(arbbridge.InitiateChallengeEvent)
+	initEv, ok := event.(arbbridge.InitiateChallengeEvent)
 	if !ok {
 		return 0, fmt.Errorf("ExecutionChallenge expected InitiateChallengeEvent but got %T", event)
 	}
+	deadline := initEv.Deadline
 
 	defender := startDefender
 
 	for {
+		// Moves must be mined before the current deadline
+		responseCtx := arbbridge.WithResponseDeadline(ctx, deadline)
+
 		if defender.NumSteps() == 1 {
 			timedOut, event, state, err := getNextEventIfExists(ctx, eventChan, replayTimeout)
 			if timedOut {
+				if err := waitToRespond(ctx, client, strategy, deadline); err != nil {
+					return 0, err
+				}
 				proof, err := defender.SolidityOneStepProof()
 				if err != nil {
 					return 0, err
@@ -137,7 +143,7 @@ func defendExecution(
 					0,
 				)
 				err = contract.OneStepProof(
-					ctx,
+					responseCtx,
 					defender.GetPrecondition(),
 					valprotocol.NewExecutionAssertionStubFromAssertion(assertion),
 					proof,
@@ -160,9 +166,13 @@ func defendExecution(
 		timedOut, event, state, err := getNextEventIfExists(ctx, eventChan, replayTimeout)
 		var defenders []AssertionDefender = nil
 		if timedOut {
+			if err := waitToRespond(ctx, client, strategy, deadline); err != nil {
+				return 0, err
+			}
+			segments := strategy.SegmentCount(valprotocol.InvalidExecutionChildType, defender.NumSteps())
 			var assertions []*valprotocol.ExecutionAssertionStub
-			defenders, assertions = defender.NBisect(uint64(bisectionCount))
-			err := contract.BisectAssertion(ctx, defender.GetPrecondition(), assertions, defender.NumSteps())
+			defenders, assertions = defender.NBisect(segments)
+			err := contract.BisectAssertion(responseCtx, defender.GetPrecondition(), assertions, defender.NumSteps())
 			if err != nil {
 				return 0, err
 			}
@@ -191,6 +201,7 @@ func defendExecution(
 		if !ok {
 			return 0, fmt.Errorf("ExecutionChallenge defender expected ContinueChallengeEvent but got %T", event)
 		}
+		deadline = contEv.Deadline
 
 		if timedOut {
 			// Freshly bisected assertion
@@ -226,7 +237,7 @@ func challengeExecution(
 	client arbbridge.ArbClient,
 	startMachine machine.Machine,
 	startPrecondition *valprotocol.Precondition,
-	challengeEverything bool,
+	strategy ChallengeStrategy,
 ) (ChallengeState, error) {
 	event, ok := <-eventChan
 	if !ok {
@@ -265,30 +276,16 @@ func challengeExecution(
 		var m machine.Machine
 		if timedOut {
 			var challengedAssertionNum uint16
-			challengedAssertionNum, m, err = ChooseAssertionToChallenge(mach.Clone(), precondition, ev.Assertions, ev.TotalSteps)
-			if err != nil && challengeEverything {
-				pre := precondition
-				cMach := mach.Clone()
-				challengedAssertionNum = uint16(rand.Int31n(int32(len(ev.Assertions))))
-				for i := 0; i < len(ev.Assertions); i++ {
-					stepCount := valprotocol.CalculateBisectionStepCount(uint64(i), uint64(len(ev.Assertions)), ev.TotalSteps)
-					m = cMach.Clone()
-					assertion, _ := cMach.ExecuteAssertion(
-						stepCount,
-						pre.TimeBounds,
-						pre.BeforeInbox.(value.TupleValue),
-						0,
-					)
-					pre = pre.GeneratePostcondition(valprotocol.NewExecutionAssertionStubFromAssertion(assertion))
-				}
-				err = nil
-			}
+			challengedAssertionNum, m, err = ChooseAssertionToChallenge(mach.Clone(), precondition, ev.Assertions, ev.TotalSteps, strategy)
 			if err != nil {
 				return 0, err
 			}
+			if err := waitToRespond(ctx, client, strategy, ev.Deadline); err != nil {
+				return 0, err
+			}
 			preconditions = valprotocol.GeneratePreconditions(precondition, ev.Assertions)
 			err = contract.ChooseSegment(
-				ctx,
+				arbbridge.WithResponseDeadline(ctx, ev.Deadline),
 				challengedAssertionNum,
 				preconditions,
 				ev.Assertions,
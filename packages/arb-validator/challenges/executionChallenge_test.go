@@ -69,7 +69,7 @@ func testExecutionChallenge(t *testing.T) {
 				precondition,
 				mach.Clone(),
 				numSteps,
-				4,
+				DefaultChallengeStrategy{ExecutionSegments: 4},
 			)
 		},
 		func(challengeAddress common.Address, client *ethbridge.EthArbAuthClient, blockId *common.BlockId) (ChallengeState, error) {
@@ -81,7 +81,7 @@ func testExecutionChallenge(t *testing.T) {
 				0,
 				precondition,
 				mach.Clone(),
-				true,
+				challengeEverythingStrategy{},
 			)
 		},
 	); err != nil {
@@ -22,10 +22,10 @@ import (
 	"fmt"
 	"log"
 	"math/big"
-	"math/rand"
 
 	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/arbbridge"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/valprotocol"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator/structures"
 	errors2 "github.com/pkg/errors"
 )
@@ -39,7 +39,7 @@ func DefendInboxTopClaim(
 	inbox *structures.MessageStack,
 	afterInboxTop common.Hash,
 	messageCount *big.Int,
-	bisectionCount uint64,
+	strategy ChallengeStrategy,
 ) (ChallengeState, error) {
 	contractWatcher, err := client.NewInboxTopChallengeWatcher(address)
 	if err != nil {
@@ -62,7 +62,7 @@ func DefendInboxTopClaim(
 		inbox,
 		afterInboxTop,
 		messageCount.Uint64(),
-		bisectionCount,
+		strategy,
 	)
 }
 
@@ -73,7 +73,7 @@ func ChallengeInboxTopClaim(
 	startBlockId *common.BlockId,
 	startLogIndex uint,
 	inbox *structures.MessageStack,
-	challengeEverything bool,
+	strategy ChallengeStrategy,
 ) (ChallengeState, error) {
 	contractWatcher, err := client.NewInboxTopChallengeWatcher(address)
 	if err != nil {
@@ -93,7 +93,7 @@ func ChallengeInboxTopClaim(
 		contract,
 		client,
 		inbox,
-		challengeEverything,
+		strategy,
 	)
 }
 
@@ -105,28 +105,35 @@ func defendInboxTop(
 	inbox *structures.MessageStack,
 	afterInboxTop common.Hash,
 	messageCount uint64,
-	bisectionCount uint64,
+	strategy ChallengeStrategy,
 ) (ChallengeState, error) {
 	event, ok := <-eventChan
 	if !ok {
 		return 0, challengeNoEvents
 	}
-	_, ok = event.(arbbridge.InitiateChallengeEvent)
+	initEv, ok := event.(arbbridge.InitiateChallengeEvent)
 	if !ok {
 		return 0, fmt.Errorf("InboxTopChallenge defender expected InitiateChallengeEvent but got %T", event)
 	}
+	deadline := initEv.Deadline
 
 	startState := afterInboxTop
 
 	for {
+		// Moves must be mined before the current deadline
+		responseCtx := arbbridge.WithResponseDeadline(ctx, deadline)
+
 		if messageCount == 1 {
 			timedOut, event, state, err := getNextEventIfExists(ctx, eventChan, replayTimeout)
 			if timedOut {
+				if err := waitToRespond(ctx, client, strategy, deadline); err != nil {
+					return 0, err
+				}
 				msg, err := inbox.GenerateOneStepProof(startState)
 				if err != nil {
 					return 0, err
 				}
-				err = contract.OneStepProof(ctx, startState, msg.CommitmentHash())
+				err = contract.OneStepProof(responseCtx, startState, msg.CommitmentHash())
 				if err != nil {
 					return 0, errors2.Wrap(err, "Error making one step proof")
 				}
@@ -145,11 +152,15 @@ func defendInboxTop(
 
 		timedOut, event, state, err := getNextEventIfExists(ctx, eventChan, replayTimeout)
 		if timedOut {
-			chainHashes, err := inbox.GenerateBisection(startState, bisectionCount, messageCount)
+			if err := waitToRespond(ctx, client, strategy, deadline); err != nil {
+				return 0, err
+			}
+			segments := strategy.SegmentCount(valprotocol.InvalidInboxTopChildType, messageCount)
+			chainHashes, err := inbox.GenerateBisection(startState, segments, messageCount)
 			if err != nil {
 				return 0, err
 			}
-			err = contract.Bisect(ctx, chainHashes, new(big.Int).SetUint64(messageCount))
+			err = contract.Bisect(responseCtx, chainHashes, new(big.Int).SetUint64(messageCount))
 			if err != nil {
 				return 0, errors2.Wrap(err, "Error bisecting")
 			}
@@ -178,6 +189,7 @@ func defendInboxTop(
 		if !ok {
 			return 0, fmt.Errorf("InboxTopChallenge defender expected ContinueChallengeEvent but got %T", event)
 		}
+		deadline = contEv.Deadline
 		startState = ev.ChainHashes[contEv.SegmentIndex.Uint64()]
 		messageCount = getSegmentCount(messageCount, uint64(len(ev.ChainHashes))-1, contEv.SegmentIndex.Uint64())
 	}
@@ -189,7 +201,7 @@ func challengeInboxTop(
 	contract arbbridge.InboxTopChallenge,
 	client arbbridge.ArbClient,
 	inbox *structures.MessageStack,
-	challengeEverything bool,
+	strategy ChallengeStrategy,
 ) (ChallengeState, error) {
 	event, ok := <-eventChan
 	if !ok {
@@ -235,14 +247,14 @@ func challengeInboxTop(
 				}
 				return 0, false
 			}()
-			if !found {
-				if challengeEverything {
-					segmentToChallenge = uint64(rand.Int31n(int32(len(ev.ChainHashes) - 1)))
-				} else {
-					return 0, errors.New("can't find inbox segment to challenge")
-				}
+			segmentToChallenge, ok := strategy.ChooseSegment(uint64(len(ev.ChainHashes))-1, segmentToChallenge, found)
+			if !ok {
+				return 0, errors.New("can't find inbox segment to challenge")
+			}
+			if err := waitToRespond(ctx, client, strategy, ev.Deadline); err != nil {
+				return 0, err
 			}
-			err = contract.ChooseSegment(ctx, uint16(segmentToChallenge), ev.ChainHashes, ev.TotalLength.Uint64())
+			err = contract.ChooseSegment(arbbridge.WithResponseDeadline(ctx, ev.Deadline), uint16(segmentToChallenge), ev.ChainHashes, ev.TotalLength.Uint64())
 			if err != nil {
 				return 0, err
 			}
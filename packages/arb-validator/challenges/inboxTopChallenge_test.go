@@ -104,7 +104,7 @@ func testInboxTopChallenge(t *testing.T) {
 				messageStack,
 				bottomHash,
 				messageCount,
-				2,
+				DefaultChallengeStrategy{InboxTopSegments: 2},
 			)
 		},
 		func(challengeAddress common.Address, client *ethbridge.EthArbAuthClient, blockId *common.BlockId) (ChallengeState, error) {
@@ -115,7 +115,7 @@ func testInboxTopChallenge(t *testing.T) {
 				blockId,
 				0,
 				messageStack,
-				true,
+				challengeEverythingStrategy{},
 			)
 		},
 	); err != nil {
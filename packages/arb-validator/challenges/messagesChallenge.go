@@ -22,7 +22,6 @@ import (
 	"fmt"
 	"log"
 	"math/big"
-	"math/rand"
 
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/message"
 	errors2 "github.com/pkg/errors"
@@ -30,6 +29,7 @@ import (
 	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
 	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/arbbridge"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/valprotocol"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator/structures"
 )
 
@@ -42,7 +42,7 @@ func DefendMessagesClaim(
 	inbox *structures.MessageStack,
 	beforeInbox common.Hash,
 	messageCount *big.Int,
-	bisectionCount uint64,
+	strategy ChallengeStrategy,
 ) (ChallengeState, error) {
 	contractWatcher, err := client.NewMessagesChallengeWatcher(address)
 	if err != nil {
@@ -64,7 +64,7 @@ func DefendMessagesClaim(
 		inbox,
 		beforeInbox,
 		messageCount.Uint64(),
-		bisectionCount,
+		strategy,
 	)
 }
 
@@ -77,7 +77,7 @@ func ChallengeMessagesClaim(
 	inbox *structures.MessageStack,
 	beforeInbox common.Hash,
 	messageCount *big.Int,
-	challengeEverything bool,
+	strategy ChallengeStrategy,
 ) (ChallengeState, error) {
 	contractWatcher, err := client.NewMessagesChallengeWatcher(address)
 	if err != nil {
@@ -99,7 +99,7 @@ func ChallengeMessagesClaim(
 		inbox,
 		beforeInbox,
 		messageCount.Uint64(),
-		challengeEverything,
+		strategy,
 	)
 }
 
@@ -111,16 +111,17 @@ func defendMessages(
 	inbox *structures.MessageStack,
 	beforeInbox common.Hash,
 	messageCount uint64,
-	bisectionCount uint64,
+	strategy ChallengeStrategy,
 ) (ChallengeState, error) {
 	event, ok := <-eventChan
 	if !ok {
 		return 0, challengeNoEvents
 	}
-	_, ok = event.(arbbridge.InitiateChallengeEvent)
+	initEv, ok := event.(arbbridge.InitiateChallengeEvent)
 	if !ok {
 		return 0, fmt.Errorf("MessagesChallenge defender expected InitiateChallengeEvent but got %T", event)
 	}
+	deadline := initEv.Deadline
 
 	vmInbox, err := inbox.GenerateVMInbox(beforeInbox, messageCount)
 	if err != nil {
@@ -135,10 +136,16 @@ func defendMessages(
 	inboxStartCount := uint64(0)
 
 	for {
+		// Moves must be mined before the current deadline
+		responseCtx := arbbridge.WithResponseDeadline(ctx, deadline)
+
 		log.Println(inboxStartCount, messageCount)
 		if messageCount == 1 {
 			timedOut, event, state, err := getNextEventIfExists(ctx, eventChan, replayTimeout)
 			if timedOut {
+				if err := waitToRespond(ctx, client, strategy, deadline); err != nil {
+					return 0, err
+				}
 				msg, err := inbox.GenerateOneStepProof(startInbox)
 				if err != nil {
 					return 0, err
@@ -146,15 +153,15 @@ func defendMessages(
 
 				switch msg := msg.(type) {
 				case message.DeliveredTransaction:
-					err = contract.OneStepProofTransactionMessage(ctx, startInbox, startMessages, msg)
+					err = contract.OneStepProofTransactionMessage(responseCtx, startInbox, startMessages, msg)
 				case message.DeliveredEth:
-					err = contract.OneStepProofEthMessage(ctx, startInbox, startMessages, msg)
+					err = contract.OneStepProofEthMessage(responseCtx, startInbox, startMessages, msg)
 				case message.DeliveredERC20:
-					err = contract.OneStepProofERC20Message(ctx, startInbox, startMessages, msg)
+					err = contract.OneStepProofERC20Message(responseCtx, startInbox, startMessages, msg)
 				case message.DeliveredERC721:
-					err = contract.OneStepProofERC721Message(ctx, startInbox, startMessages, msg)
+					err = contract.OneStepProofERC721Message(responseCtx, startInbox, startMessages, msg)
 				case message.DeliveredContractTransaction:
-					err = contract.OneStepProofContractTransactionMessage(ctx, startInbox, startMessages, msg)
+					err = contract.OneStepProofContractTransactionMessage(responseCtx, startInbox, startMessages, msg)
 				case message.DeliveredTransactionBatch:
 
 				}
@@ -176,8 +183,12 @@ func defendMessages(
 
 		timedOut, event, state, err := getNextEventIfExists(ctx, eventChan, replayTimeout)
 		if timedOut {
-			chainHashes, err := inbox.GenerateBisection(startInbox, bisectionCount, messageCount)
-			inboxHashes, err := vmInbox.GenerateBisection(inboxStartCount, bisectionCount, messageCount)
+			if err := waitToRespond(ctx, client, strategy, deadline); err != nil {
+				return 0, err
+			}
+			segments := strategy.SegmentCount(valprotocol.InvalidMessagesChildType, messageCount)
+			chainHashes, err := inbox.GenerateBisection(startInbox, segments, messageCount)
+			inboxHashes, err := vmInbox.GenerateBisection(inboxStartCount, segments, messageCount)
 			if err != nil {
 				return 0, err
 			}
@@ -185,7 +196,7 @@ func defendMessages(
 			log.Println("chainHashes", chainHashes)
 			log.Println("inboxHashes", inboxHashes)
 
-			err = contract.Bisect(ctx, chainHashes, inboxHashes, new(big.Int).SetUint64(messageCount))
+			err = contract.Bisect(responseCtx, chainHashes, inboxHashes, new(big.Int).SetUint64(messageCount))
 			if err != nil {
 				return 0, errors2.Wrap(err, "failing making bisection")
 			}
@@ -215,6 +226,7 @@ func defendMessages(
 		if !ok {
 			return 0, fmt.Errorf("MessagesChallenge defender expected ContinueChallengeEvent but got %T", event)
 		}
+		deadline = contEv.Deadline
 		startInbox = ev.ChainHashes[contEv.SegmentIndex.Uint64()]
 		startMessages = ev.SegmentHashes[contEv.SegmentIndex.Uint64()]
 		inboxStartCount += getSegmentStart(messageCount, uint64(len(ev.ChainHashes))-1, contEv.SegmentIndex.Uint64())
@@ -231,7 +243,7 @@ func challengeMessages(
 	inbox *structures.MessageStack,
 	beforeInbox common.Hash,
 	messageCount uint64,
-	challengeEverything bool,
+	strategy ChallengeStrategy,
 ) (ChallengeState, error) {
 	event, ok := <-eventChan
 	if !ok {
@@ -299,15 +311,15 @@ func challengeMessages(
 				return 0, false
 			}()
 
-			if !found {
-				if challengeEverything {
-					segmentToChallenge = uint64(rand.Int31n(int32(len(ev.ChainHashes) - 1)))
-				} else {
-					return 0, errors.New("Nothing to challenge")
-				}
+			segmentToChallenge, ok := strategy.ChooseSegment(uint64(len(ev.ChainHashes))-1, segmentToChallenge, found)
+			if !ok {
+				return 0, errors.New("Nothing to challenge")
+			}
+			if err := waitToRespond(ctx, client, strategy, ev.Deadline); err != nil {
+				return 0, err
 			}
 			log.Println("ChooseSegment", uint16(segmentToChallenge), ev.ChainHashes, ev.SegmentHashes, ev.TotalLength)
-			err = contract.ChooseSegment(ctx, uint16(segmentToChallenge), ev.ChainHashes, ev.SegmentHashes, ev.TotalLength)
+			err = contract.ChooseSegment(arbbridge.WithResponseDeadline(ctx, ev.Deadline), uint16(segmentToChallenge), ev.ChainHashes, ev.SegmentHashes, ev.TotalLength)
 			if err != nil {
 				return 0, err
 			}
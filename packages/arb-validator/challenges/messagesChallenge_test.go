@@ -87,7 +87,7 @@ func testMessagesChallenge(t *testing.T) {
 				messageStack,
 				beforeInbox,
 				new(big.Int).SetUint64(messageCount),
-				2,
+				DefaultChallengeStrategy{MessagesSegments: 2},
 			)
 		},
 		func(challengeAddress common.Address, client *ethbridge.EthArbAuthClient, blockId *common.BlockId) (ChallengeState, error) {
@@ -100,7 +100,7 @@ func testMessagesChallenge(t *testing.T) {
 				messageStack,
 				beforeInbox,
 				new(big.Int).SetUint64(messageCount),
-				true,
+				challengeEverythingStrategy{},
 			)
 		},
 	); err != nil {
@@ -0,0 +1,183 @@
+/*
+* Copyright 2020, Offchain Labs, Inc.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*    http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package challenges
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/arbbridge"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/valprotocol"
+)
+
+// ChallengeStrategy decides how a validator plays its side of a challenge.
+// Bisecting into more segments makes each move more expensive but finishes
+// the challenge in fewer rounds
+type ChallengeStrategy interface {
+	// SegmentCount returns the number of segments that a defender splits a
+	// disputed claim of the given type covering total steps or messages into
+	SegmentCount(challengeType valprotocol.ChildType, total uint64) uint64
+
+	// ResponseDelay returns how long to wait before sending a move, given the
+	// time remaining until the move's deadline
+	ResponseDelay(remaining time.Duration) time.Duration
+
+	// ChooseSegment returns the segment for a challenger to challenge out of
+	// segmentCount segments. If found is true, firstInvalid is the first
+	// segment that disagrees with the challenger's calculations. A segment
+	// after firstInvalid can't be challenged since the challenger can't
+	// compute the state it starts from. Returning false ends the challenge
+	// without a move
+	ChooseSegment(segmentCount uint64, firstInvalid uint64, found bool) (uint64, bool)
+}
+
+const (
+	defaultInboxTopSegments  = 100
+	defaultMessagesSegments  = 100
+	defaultExecutionSegments = 50
+
+	// Multiple of the default segment counts used by the aggressive strategy
+	aggressiveSegmentFactor = 4
+)
+
+// DefaultChallengeStrategy bisects into a fixed number of segments for each
+// type of challenge, responds as soon as possible and only challenges
+// invalid segments. Segment counts which are zero use the package defaults
+type DefaultChallengeStrategy struct {
+	InboxTopSegments  uint64
+	MessagesSegments  uint64
+	ExecutionSegments uint64
+}
+
+func defaultSegmentCount(challengeType valprotocol.ChildType) uint64 {
+	switch challengeType {
+	case valprotocol.InvalidInboxTopChildType:
+		return defaultInboxTopSegments
+	case valprotocol.InvalidMessagesChildType:
+		return defaultMessagesSegments
+	default:
+		return defaultExecutionSegments
+	}
+}
+
+func (s DefaultChallengeStrategy) SegmentCount(challengeType valprotocol.ChildType, _ uint64) uint64 {
+	var count uint64
+	switch challengeType {
+	case valprotocol.InvalidInboxTopChildType:
+		count = s.InboxTopSegments
+	case valprotocol.InvalidMessagesChildType:
+		count = s.MessagesSegments
+	default:
+		count = s.ExecutionSegments
+	}
+	if count == 0 {
+		count = defaultSegmentCount(challengeType)
+	}
+	return count
+}
+
+func (s DefaultChallengeStrategy) ResponseDelay(time.Duration) time.Duration {
+	return 0
+}
+
+func (s DefaultChallengeStrategy) ChooseSegment(_ uint64, firstInvalid uint64, found bool) (uint64, bool) {
+	return firstInvalid, found
+}
+
+// AggressiveChallengeStrategy bisects into several times as many segments as
+// the default strategy, so challenges finish in fewer rounds at a higher gas
+// cost per move. It responds as soon as possible and only challenges invalid
+// segments
+type AggressiveChallengeStrategy struct{}
+
+func (s AggressiveChallengeStrategy) SegmentCount(challengeType valprotocol.ChildType, _ uint64) uint64 {
+	return defaultSegmentCount(challengeType) * aggressiveSegmentFactor
+}
+
+func (s AggressiveChallengeStrategy) ResponseDelay(time.Duration) time.Duration {
+	return 0
+}
+
+func (s AggressiveChallengeStrategy) ChooseSegment(_ uint64, firstInvalid uint64, found bool) (uint64, bool) {
+	return firstInvalid, found
+}
+
+// DelayedChallengeStrategy behaves like the wrapped strategy, but waits until
+// Fraction of the time remaining before each deadline has passed before it
+// responds. This lowers the rate of L1 transactions during a challenge and
+// can be used to test counterparties against a slow opponent
+type DelayedChallengeStrategy struct {
+	ChallengeStrategy
+	Fraction float64
+}
+
+func (s DelayedChallengeStrategy) ResponseDelay(remaining time.Duration) time.Duration {
+	return time.Duration(float64(remaining) * s.Fraction)
+}
+
+// ParseChallengeStrategy returns the strategy with the given name, which is
+// either default or aggressive. If delayFraction is positive, the strategy
+// waits that fraction of the time remaining before each deadline
+func ParseChallengeStrategy(name string, delayFraction float64) (ChallengeStrategy, error) {
+	var strategy ChallengeStrategy
+	switch name {
+	case "default":
+		strategy = DefaultChallengeStrategy{}
+	case "aggressive":
+		strategy = AggressiveChallengeStrategy{}
+	default:
+		return nil, fmt.Errorf("unknown challenge strategy %v", name)
+	}
+	if delayFraction < 0 || delayFraction >= 1 {
+		return nil, fmt.Errorf("challenge response delay %v must be at least 0 and less than 1", delayFraction)
+	}
+	if delayFraction > 0 {
+		strategy = DelayedChallengeStrategy{ChallengeStrategy: strategy, Fraction: delayFraction}
+	}
+	return strategy, nil
+}
+
+// waitToRespond sleeps for the delay the strategy chooses before sending a
+// move which must be made by deadline
+func waitToRespond(
+	ctx context.Context,
+	client arbbridge.ArbClient,
+	strategy ChallengeStrategy,
+	deadline common.TimeTicks,
+) error {
+	blockId, err := client.CurrentBlockId(ctx)
+	if err != nil {
+		return err
+	}
+	remaining := common.TimeTicks{Val: new(big.Int).Sub(deadline.Val, common.TicksFromBlockNum(blockId.Height).Val)}
+	if remaining.Val.Sign() < 0 {
+		remaining.Val.SetInt64(0)
+	}
+	delay := strategy.ResponseDelay(remaining.Duration())
+	if delay <= 0 {
+		return nil
+	}
+	select {
+	case <-time.After(delay):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,98 @@
+/*
+* Copyright 2020, Offchain Labs, Inc.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*    http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package challenges
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/valprotocol"
+)
+
+// challengeEverythingStrategy challenges a random segment when every segment
+// is valid, which lets tests run challenges against honest defenders
+type challengeEverythingStrategy struct {
+	DefaultChallengeStrategy
+}
+
+func (s challengeEverythingStrategy) ChooseSegment(segmentCount uint64, firstInvalid uint64, found bool) (uint64, bool) {
+	if found {
+		return firstInvalid, true
+	}
+	return uint64(rand.Int63n(int64(segmentCount))), true
+}
+
+func TestDefaultChallengeStrategy(t *testing.T) {
+	strategy := DefaultChallengeStrategy{MessagesSegments: 2}
+	if count := strategy.SegmentCount(valprotocol.InvalidMessagesChildType, 1000); count != 2 {
+		t.Error("messages segment count was", count, "instead of 2")
+	}
+	if count := strategy.SegmentCount(valprotocol.InvalidExecutionChildType, 1000); count != defaultExecutionSegments {
+		t.Error("execution segment count was", count, "instead of the default")
+	}
+	if _, ok := strategy.ChooseSegment(10, 0, false); ok {
+		t.Error("default strategy challenged a valid bisection")
+	}
+	if segment, ok := strategy.ChooseSegment(10, 3, true); !ok || segment != 3 {
+		t.Error("default strategy chose segment", segment, "instead of 3")
+	}
+}
+
+func TestAggressiveChallengeStrategy(t *testing.T) {
+	strategy := AggressiveChallengeStrategy{}
+	defaultCount := DefaultChallengeStrategy{}.SegmentCount(valprotocol.InvalidInboxTopChildType, 1000)
+	if count := strategy.SegmentCount(valprotocol.InvalidInboxTopChildType, 1000); count <= defaultCount {
+		t.Error("aggressive strategy used", count, "segments which isn't more than the default", defaultCount)
+	}
+	if _, ok := strategy.ChooseSegment(5, 0, false); ok {
+		t.Error("aggressive strategy challenged a valid bisection")
+	}
+	if segment, ok := strategy.ChooseSegment(5, 2, true); !ok || segment != 2 {
+		t.Error("aggressive strategy chose segment", segment, "instead of 2")
+	}
+}
+
+func TestParseChallengeStrategy(t *testing.T) {
+	strategy, err := ParseChallengeStrategy("aggressive", 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	delayed, ok := strategy.(DelayedChallengeStrategy)
+	if !ok || delayed.ChallengeStrategy != (AggressiveChallengeStrategy{}) {
+		t.Error("parsed strategy", strategy, "instead of delayed aggressive strategy")
+	}
+	if strategy, err := ParseChallengeStrategy("default", 0); err != nil || strategy != (DefaultChallengeStrategy{}) {
+		t.Error("parsed strategy", strategy, err, "instead of default strategy")
+	}
+	if _, err := ParseChallengeStrategy("reckless", 0); err == nil {
+		t.Error("parsed unknown strategy")
+	}
+	if _, err := ParseChallengeStrategy("default", 1); err == nil {
+		t.Error("accepted delay until the deadline")
+	}
+}
+
+func TestDelayedChallengeStrategy(t *testing.T) {
+	strategy := DelayedChallengeStrategy{ChallengeStrategy: DefaultChallengeStrategy{}, Fraction: 0.5}
+	if delay := strategy.ResponseDelay(time.Minute); delay != 30*time.Second {
+		t.Error("delay was", delay, "instead of 30s")
+	}
+	if count := strategy.SegmentCount(valprotocol.InvalidExecutionChildType, 1000); count != defaultExecutionSegments {
+		t.Error("delayed strategy didn't use wrapped strategy's segment count")
+	}
+}
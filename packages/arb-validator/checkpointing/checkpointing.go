@@ -32,6 +32,12 @@ type RollupCheckpointer interface {
 	RestoreLatestState(context.Context, arbbridge.ChainTimeGetter, func([]byte, RestoreContext) error) error
 	GetInitialMachine() (machine.Machine, error)
 	AsyncSaveCheckpoint(blockId *common.BlockId, contents []byte, cpCtx *CheckpointContext)
+	// Flush synchronously writes any checkpoint which is still waiting to be
+	// saved
+	Flush() error
+	// LatestCheckpointHeight returns the height of the most recent saved
+	// checkpoint or nil if there are none
+	LatestCheckpointHeight() *common.TimeBlocks
 }
 
 const checkpointDatabasePathBase = "/tmp/arb-validator-checkpoint-"
@@ -59,3 +59,11 @@ func (dcp *DummyCheckpointer) GetInitialMachine() (machine.Machine, error) {
 }
 
 func (dcp *DummyCheckpointer) AsyncSaveCheckpoint(_ *common.BlockId, _ []byte, _ *CheckpointContext) {}
+
+func (dcp *DummyCheckpointer) Flush() error {
+	return nil
+}
+
+func (dcp *DummyCheckpointer) LatestCheckpointHeight() *common.TimeBlocks {
+	return nil
+}
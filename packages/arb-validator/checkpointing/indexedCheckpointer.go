@@ -32,8 +32,11 @@ import (
 	"github.com/offchainlabs/arbitrum/packages/arb-util/machine"
 	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/arbbridge"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/metrics"
 )
 
+var checkpointWriteTimer = metrics.NewTimer("arbitrum/checkpoint/write")
+
 var errNoCheckpoint = errors.New("cannot restore because no checkpoint exists")
 var errNoMatchingCheckpoint = errors.New("cannot restore because no matching checkpoint exists")
 
@@ -41,6 +44,10 @@ type IndexedCheckpointer struct {
 	*sync.Mutex
 	db                    machine.CheckpointStorage
 	nextCheckpointToWrite *writableCheckpoint
+
+	// writeMutex prevents the write daemon and Flush from writing at the
+	// same time
+	writeMutex *sync.Mutex
 }
 
 func NewIndexedCheckpointerFactory(
@@ -93,6 +100,7 @@ func newIndexedCheckpointerFactory(
 		new(sync.Mutex),
 		cCheckpointer,
 		nil,
+		new(sync.Mutex),
 	}, nil
 }
 
@@ -172,19 +180,34 @@ func (cp *IndexedCheckpointer) writeDaemon() {
 	defer ticker.Stop()
 	for {
 		<-ticker.C
-		cp.Lock()
-		checkpoint := cp.nextCheckpointToWrite
-		cp.nextCheckpointToWrite = nil
-		cp.Unlock()
-		if checkpoint != nil {
-			err := writeCheckpoint(cp.db, checkpoint)
-			if err != nil {
-				log.Println("Error writing checkpoint: {}", err)
-			}
+		if err := cp.Flush(); err != nil {
+			log.Println("Error writing checkpoint: {}", err)
 		}
 	}
 }
 
+func (cp *IndexedCheckpointer) Flush() error {
+	cp.writeMutex.Lock()
+	defer cp.writeMutex.Unlock()
+	cp.Lock()
+	checkpoint := cp.nextCheckpointToWrite
+	cp.nextCheckpointToWrite = nil
+	cp.Unlock()
+	if checkpoint == nil {
+		return nil
+	}
+	start := time.Now()
+	defer checkpointWriteTimer.UpdateSince(start)
+	return writeCheckpoint(cp.db, checkpoint)
+}
+
+func (cp *IndexedCheckpointer) LatestCheckpointHeight() *common.TimeBlocks {
+	if cp.db.IsBlockStoreEmpty() {
+		return nil
+	}
+	return cp.db.MaxBlockStoreHeight()
+}
+
 func writeCheckpoint(db machine.CheckpointStorage, wc *writableCheckpoint) error {
 	// save values and machines
 	for _, val := range wc.ckpCtx.Values() {
@@ -28,8 +28,6 @@ import (
 
 	errors2 "github.com/pkg/errors"
 
-	"github.com/ethereum/go-ethereum/ethclient"
-
 	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/arbbridge"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/ethbridge"
@@ -51,6 +49,10 @@ func main() {
 		if err := cmdhelper.ValidateRollupChain("arb-validator", createManager); err != nil {
 			log.Fatal(err)
 		}
+	case "watch":
+		if err := cmdhelper.WatchRollupChain("arb-validator"); err != nil {
+			log.Fatal(err)
+		}
 	default:
 	}
 }
@@ -58,13 +60,14 @@ func main() {
 func createRollupChain() error {
 	createCmd := flag.NewFlagSet("validate", flag.ExitOnError)
 	walletVars := utils.AddFlags(createCmd)
+	ethClientVars := utils.AddEthClientFlags(createCmd)
 	err := createCmd.Parse(os.Args[2:])
 	if err != nil {
 		return err
 	}
 
 	if createCmd.NArg() != 3 {
-		return fmt.Errorf("usage: arb-validator create %v <validator_folder> <ethURL> <factoryAddress>", utils.WalletArgsString)
+		return fmt.Errorf("usage: arb-validator create %v %v <validator_folder> <ethURL> <factoryAddress>", utils.WalletArgsString, utils.EthClientArgsString)
 	}
 
 	validatorFolder := createCmd.Arg(0)
@@ -79,18 +82,21 @@ func createRollupChain() error {
 		return errors2.Wrap(err, "loader error")
 	}
 
-	auth, err := utils.GetKeystore(validatorFolder, walletVars, createCmd)
+	ethclint, err := utils.DialEthClient(context.Background(), ethURL, ethClientVars)
 	if err != nil {
 		return err
 	}
 
-	ethclint, err := ethclient.Dial(ethURL)
+	auth, err := utils.GetTransactAuth(context.Background(), validatorFolder, walletVars, createCmd, ethclint)
 	if err != nil {
 		return err
 	}
 
 	// Rollup creation
 	client := ethbridge.NewEthAuthClient(ethclint, auth)
+	if err := utils.ConfigureGasPricing(walletVars, ethclint, client); err != nil {
+		return err
+	}
 
 	if err := arbbridge.WaitForNonZeroBalance(context.Background(), client, common.NewAddressFromEth(auth.From)); err != nil {
 		return err
@@ -114,6 +120,6 @@ func createRollupChain() error {
 	return nil
 }
 
-func createManager(rollupAddress common.Address, client arbbridge.ArbAuthClient, contractFile string, dbPath string) (*rollupmanager.Manager, error) {
-	return rollupmanager.CreateManager(rollupAddress, client, contractFile, dbPath)
+func createManager(rollupAddress common.Address, client arbbridge.ArbAuthClient, contractFile string, dbPath string, confirmationDepth uint64, creationSearchStart *common.TimeBlocks) (*rollupmanager.Manager, error) {
+	return rollupmanager.CreateManager(rollupAddress, client, contractFile, dbPath, confirmationDepth, creationSearchStart)
 }
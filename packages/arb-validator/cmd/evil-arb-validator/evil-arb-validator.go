@@ -51,7 +51,7 @@ func main() {
 	}
 }
 
-func createEvilManager(rollupAddress common.Address, client arbbridge.ArbAuthClient, contractFile string, dbPath string) (*rollupmanager.Manager, error) {
+func createEvilManager(rollupAddress common.Address, client arbbridge.ArbAuthClient, contractFile string, dbPath string, confirmationDepth uint64, creationSearchStart *common.TimeBlocks) (*rollupmanager.Manager, error) {
 	return rollupmanager.CreateManagerAdvanced(
 		context.Background(),
 		rollupAddress,
@@ -64,5 +64,7 @@ func createEvilManager(rollupAddress common.Address, client arbbridge.ArbAuthCli
 			big.NewInt(100),
 			false,
 		),
+		confirmationDepth,
+		creationSearchStart,
 	)
 }
@@ -21,19 +21,27 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"math/big"
+	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/utils"
 
-	"github.com/ethereum/go-ethereum/ethclient"
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
 	"github.com/gorilla/rpc"
 	"github.com/gorilla/rpc/json"
 
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/code"
 	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/machine"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/arbbridge"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/ethbridge"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/metrics"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator/challenges"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator/rollup"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator/rollupmanager"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator/rollupvalidator"
@@ -48,18 +56,81 @@ func ValidateRollupChain(
 		rollupAddress common.Address,
 		client arbbridge.ArbAuthClient,
 		contractFile string, dbPath string,
+		confirmationDepth uint64,
+		creationSearchStart *common.TimeBlocks,
 	) (*rollupmanager.Manager, error),
 ) error {
 	// Check number of args
 
 	validateCmd := flag.NewFlagSet("validate", flag.ExitOnError)
 	walletVars := utils.AddFlags(validateCmd)
+	ethClientVars := utils.AddEthClientFlags(validateCmd)
 	rpcEnable := validateCmd.Bool("rpc", false, "rpc")
+	ethRPCPort := validateCmd.String(
+		"eth-rpc-port",
+		"1236",
+		"eth-rpc-port=Port",
+	)
+	metricsPort := validateCmd.String(
+		"metrics-port",
+		"",
+		"metrics-port=Port",
+	)
 	blocktime := validateCmd.Int64(
 		"blocktime",
 		2,
 		"blocktime=NumSeconds",
 	)
+	confirmations := validateCmd.Uint64(
+		"confirmations",
+		0,
+		"confirmations=NumBlocks",
+	)
+	creationBlock := validateCmd.Uint64(
+		"rollup-creation-block",
+		0,
+		"rollup-creation-block=BlockNum",
+	)
+	stakingPolicyName := validateCmd.String(
+		"staking-policy",
+		"full",
+		"staking-policy=full|assert|challenge|watch",
+	)
+	maxStakesAtRisk := validateCmd.Int(
+		"max-stakes-at-risk",
+		0,
+		"max-stakes-at-risk=NumKeys",
+	)
+	challengeOnly := validateCmd.String(
+		"challenge-only",
+		"",
+		"challenge-only=Address,...",
+	)
+	neverChallenge := validateCmd.String(
+		"never-challenge",
+		"",
+		"never-challenge=Address,...",
+	)
+	stakingKeysFile := validateCmd.String(
+		"staking-keys",
+		"",
+		"staking-keys=File",
+	)
+	challengeStrategyName := validateCmd.String(
+		"challenge-strategy",
+		"default",
+		"challenge-strategy=default|aggressive",
+	)
+	challengeDelay := validateCmd.Float64(
+		"challenge-delay",
+		0,
+		"challenge-delay=FractionOfTimeRemaining",
+	)
+	profilePort := validateCmd.String(
+		"profile-port",
+		"",
+		"profile-port=Port",
+	)
 	err := validateCmd.Parse(os.Args[2:])
 	if err != nil {
 		return err
@@ -67,32 +138,58 @@ func ValidateRollupChain(
 
 	if validateCmd.NArg() != 3 {
 		return fmt.Errorf(
-			"usage: %v validate %v [--rpc] [--blocktime=NumSeconds] %v",
+			"usage: %v validate %v %v [--rpc] [--eth-rpc-port=Port] [--blocktime=NumSeconds] [--confirmations=NumBlocks] [--rollup-creation-block=BlockNum] [--staking-policy=full|assert|challenge|watch] [--max-stakes-at-risk=NumKeys] [--challenge-only=Address,...] [--never-challenge=Address,...] [--staking-keys=File] [--challenge-strategy=default|aggressive] [--challenge-delay=FractionOfTimeRemaining] [--metrics-port=Port] [--profile-port=Port] %v",
 			execName,
 			utils.WalletArgsString,
+			utils.EthClientArgsString,
 			utils.RollupArgsString,
 		)
 	}
 
+	stakingPolicy, err := rollup.StakingPolicyConfig{
+		Policy:          *stakingPolicyName,
+		MaxStakesAtRisk: *maxStakesAtRisk,
+		ChallengeOnly:   splitAddresses(*challengeOnly),
+		NeverChallenge:  splitAddresses(*neverChallenge),
+	}.StakingPolicy()
+	if err != nil {
+		return err
+	}
+	challengeStrategy, err := challenges.ParseChallengeStrategy(*challengeStrategyName, *challengeDelay)
+	if err != nil {
+		return err
+	}
+
 	common.SetDurationPerBlock(time.Duration(*blocktime) * time.Second)
 
+	if *metricsPort != "" {
+		go func() {
+			log.Fatal(metrics.LaunchMetrics(*metricsPort))
+		}()
+	}
+
 	rollupArgs := utils.ParseRollupCommand(validateCmd, 0)
 
-	auth, err := utils.GetKeystore(
+	// Rollup creation
+	ethclint, err := utils.DialEthClient(context.Background(), rollupArgs.EthURL, ethClientVars)
+	if err != nil {
+		return err
+	}
+
+	auth, err := utils.GetTransactAuth(
+		context.Background(),
 		rollupArgs.ValidatorFolder,
 		walletVars,
 		validateCmd,
+		ethclint,
 	)
 	if err != nil {
 		return err
 	}
-
-	// Rollup creation
-	ethclint, err := ethclient.Dial(rollupArgs.EthURL)
-	if err != nil {
+	client := ethbridge.NewEthAuthClient(ethclint, auth)
+	if err := utils.ConfigureGasPricing(walletVars, ethclint, client); err != nil {
 		return err
 	}
-	client := ethbridge.NewEthAuthClient(ethclint, auth)
 
 	if err := arbbridge.WaitForNonZeroBalance(
 		context.Background(),
@@ -112,10 +209,23 @@ func ValidateRollupChain(
 		rollupArgs.Address,
 		rollupActor,
 	)
-	err = validatorListener.AddStaker(client)
+	validatorListener.SetChallengeStrategy(challengeStrategy)
+	err = validatorListener.AddStakerWithPolicy(client, stakingPolicy)
 	if err != nil {
 		return err
 	}
+	if *stakingKeysFile != "" {
+		err = addStakingKeys(
+			context.Background(),
+			*stakingKeysFile,
+			walletVars,
+			ethclint,
+			validatorListener,
+		)
+		if err != nil {
+			return err
+		}
+	}
 
 	contractFile := filepath.Join(rollupArgs.ValidatorFolder, "contract.ao")
 	dbPath := filepath.Join(rollupArgs.ValidatorFolder, "checkpoint_db")
@@ -125,6 +235,8 @@ func ValidateRollupChain(
 		client,
 		contractFile,
 		dbPath,
+		*confirmations,
+		common.NewTimeBlocks(new(big.Int).SetUint64(*creationBlock)),
 	)
 
 	if err != nil {
@@ -133,8 +245,38 @@ func ValidateRollupChain(
 	manager.AddListener(&rollup.AnnouncerListener{})
 	manager.AddListener(validatorListener)
 
+	if *profilePort != "" {
+		profile := machine.NewProfile()
+		manager.SetCallProfile(profile)
+		go func() {
+			log.Fatal(launchCallProfile(profile, *profilePort))
+		}()
+	}
+
 	if *rpcEnable {
-		validatorServer := rollupvalidator.NewRPCServer(manager, time.Second*60)
+		go func() {
+			if err := <-manager.Errors(); err != nil {
+				log.Fatal(err)
+			}
+		}()
+
+		validatorServer, err := rollupvalidator.NewRPCServer(
+			manager,
+			time.Second*60,
+			filepath.Join(rollupArgs.ValidatorFolder, "tx_db"),
+		)
+		if err != nil {
+			return err
+		}
+
+		go func() {
+			if err := launchEthRPC(
+				rollupvalidator.NewEthServer(validatorServer.Server),
+				*ethRPCPort,
+			); err != nil {
+				log.Fatal(err)
+			}
+		}()
 
 		if err := launchRPC(
 			validatorServer,
@@ -144,12 +286,108 @@ func ValidateRollupChain(
 			log.Fatal(err)
 		}
 	} else {
-		wait := make(chan bool)
-		<-wait
+		return <-manager.Errors()
 	}
 	return nil
 }
 
+// WatchRollupChain follows a rollup chain without a staking key and reports
+// stakers that are staked on assertions which disagree with the locally
+// calculated valid node
+func WatchRollupChain(execName string) error {
+	watchCmd := flag.NewFlagSet("watch", flag.ExitOnError)
+	ethClientVars := utils.AddEthClientFlags(watchCmd)
+	webhook := watchCmd.String(
+		"webhook",
+		"",
+		"webhook=URL",
+	)
+	metricsPort := watchCmd.String(
+		"metrics-port",
+		"",
+		"metrics-port=Port",
+	)
+	blocktime := watchCmd.Int64(
+		"blocktime",
+		2,
+		"blocktime=NumSeconds",
+	)
+	confirmations := watchCmd.Uint64(
+		"confirmations",
+		0,
+		"confirmations=NumBlocks",
+	)
+	creationBlock := watchCmd.Uint64(
+		"rollup-creation-block",
+		0,
+		"rollup-creation-block=BlockNum",
+	)
+	err := watchCmd.Parse(os.Args[2:])
+	if err != nil {
+		return err
+	}
+
+	if watchCmd.NArg() != 3 {
+		return fmt.Errorf(
+			"usage: %v watch %v [--webhook=URL] [--blocktime=NumSeconds] [--confirmations=NumBlocks] [--rollup-creation-block=BlockNum] [--metrics-port=Port] %v",
+			execName,
+			utils.EthClientArgsString,
+			utils.RollupArgsString,
+		)
+	}
+
+	common.SetDurationPerBlock(time.Duration(*blocktime) * time.Second)
+
+	if *metricsPort != "" {
+		go func() {
+			log.Fatal(metrics.LaunchMetrics(*metricsPort))
+		}()
+	}
+
+	rollupArgs := utils.ParseRollupCommand(watchCmd, 0)
+
+	ethclint, err := utils.DialEthClient(context.Background(), rollupArgs.EthURL, ethClientVars)
+	if err != nil {
+		return err
+	}
+	client := ethbridge.NewEthClient(ethclint)
+
+	manager, err := rollupmanager.CreateManager(
+		rollupArgs.Address,
+		client,
+		filepath.Join(rollupArgs.ValidatorFolder, "contract.ao"),
+		filepath.Join(rollupArgs.ValidatorFolder, "checkpoint_db"),
+		*confirmations,
+		common.NewTimeBlocks(new(big.Int).SetUint64(*creationBlock)),
+	)
+	if err != nil {
+		return err
+	}
+	manager.AddListener(&rollup.AnnouncerListener{})
+	manager.AddListener(rollup.NewWatchtowerListener(*webhook))
+	return <-manager.Errors()
+}
+
+// launchCallProfile serves a report of the ArbGas used by calls at
+// /arbgas and the same profile in pprof's format at /arbgas/pprof
+func launchCallProfile(profile *machine.Profile, port string) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/arbgas", func(w http.ResponseWriter, r *http.Request) {
+		top, err := strconv.Atoi(r.URL.Query().Get("top"))
+		if err != nil {
+			top = 100
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_ = profile.WriteReport(w, code.InstructionNames, top)
+	})
+	mux.HandleFunc("/arbgas/pprof", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Content-Disposition", `attachment; filename="arbgas.pb.gz"`)
+		_ = profile.WritePprof(w, code.InstructionNames, "contract.ao")
+	})
+	return http.ListenAndServe(":"+port, mux)
+}
+
 func launchRPC(receiver interface{}, name string, port string) error {
 	// Run server
 	s := rpc.NewServer()
@@ -168,3 +406,22 @@ func launchRPC(receiver interface{}, name string, port string) error {
 
 	return utils.LaunchRPC(s, port)
 }
+
+func launchEthRPC(receiver interface{}, port string) error {
+	s := ethrpc.NewServer()
+	if err := s.RegisterName("eth", receiver); err != nil {
+		return err
+	}
+
+	// Serve WebSocket connections on the same port so that clients can use
+	// eth_subscribe
+	wsHandler := s.WebsocketHandler([]string{"*"})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+			wsHandler.ServeHTTP(w, r)
+			return
+		}
+		s.ServeHTTP(w, r)
+	})
+	return utils.LaunchRPC(handler, port)
+}
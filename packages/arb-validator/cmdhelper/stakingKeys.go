@@ -0,0 +1,126 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmdhelper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/ethbridge"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/ethutils"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/utils"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator/rollup"
+)
+
+// stakingKeyConfig describes one of the additional staking keys listed in
+// the file given by --staking-keys. The key is read from PrivateKeyFile or,
+// if SignerURL is set instead, is held by a Clef compatible remote signer
+type stakingKeyConfig struct {
+	rollup.StakingPolicyConfig
+	PrivateKeyFile string `json:"privateKeyFile"`
+	SignerURL      string `json:"signerURL"`
+	SignerAccount  string `json:"signerAccount"`
+}
+
+func (c stakingKeyConfig) transactAuth(ctx context.Context, ethclint ethutils.EthClient) (*bind.TransactOpts, error) {
+	var signer ethbridge.Signer
+	switch {
+	case c.PrivateKeyFile != "" && c.SignerURL != "":
+		return nil, errors.New("staking key has both a privateKeyFile and a signerURL")
+	case c.PrivateKeyFile != "":
+		key, err := utils.ReadPrivateKeyFile(c.PrivateKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		signer = ethbridge.NewKeySigner(key)
+	case c.SignerURL != "":
+		var account ethcommon.Address
+		if c.SignerAccount != "" {
+			if !ethcommon.IsHexAddress(c.SignerAccount) {
+				return nil, fmt.Errorf("invalid signer account %v", c.SignerAccount)
+			}
+			account = ethcommon.HexToAddress(c.SignerAccount)
+		}
+		chainID, err := ethclint.ChainID(ctx)
+		if err != nil {
+			return nil, err
+		}
+		signer, err = ethbridge.NewRemoteSigner(ctx, c.SignerURL, account, chainID)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.New("staking key needs a privateKeyFile or a signerURL")
+	}
+	return ethbridge.NewTransactOpts(signer), nil
+}
+
+// addStakingKeys adds each staking key listed in the JSON file at path to
+// listener with its own policy. The keys price their transactions the same
+// way as the validator's main key
+func addStakingKeys(
+	ctx context.Context,
+	path string,
+	walletVars utils.WalletFlags,
+	ethclint ethutils.EthClient,
+	listener *rollup.ValidatorChainListener,
+) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var configs []stakingKeyConfig
+	if err := json.Unmarshal(data, &configs); err != nil {
+		return err
+	}
+	for i, config := range configs {
+		policy, err := config.StakingPolicy()
+		if err != nil {
+			return fmt.Errorf("staking key %v: %v", i, err)
+		}
+		auth, err := config.transactAuth(ctx, ethclint)
+		if err != nil {
+			return fmt.Errorf("staking key %v: %v", i, err)
+		}
+		client := ethbridge.NewEthAuthClient(ethclint, auth)
+		if err := utils.ConfigureGasPricing(walletVars, ethclint, client); err != nil {
+			return err
+		}
+		if err := listener.AddStakerWithPolicy(client, policy); err != nil {
+			return err
+		}
+		log.Println("Added staking key", client.Address())
+	}
+	return nil
+}
+
+// splitAddresses splits a comma separated list of addresses given on the
+// command line
+func splitAddresses(list string) []string {
+	if list == "" {
+		return nil
+	}
+	return strings.Split(list, ",")
+}
@@ -0,0 +1,63 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package proofmachine
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/protocol"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/arbbridge"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/valprotocol"
+)
+
+// ProofChecker validates single step proofs with the one step proof
+// contract. It implements fuzz.ProofChecker so that fuzzed programs can be
+// checked against the contract
+type ProofChecker struct {
+	osp arbbridge.OneStepProof
+}
+
+func NewProofChecker(osp arbbridge.OneStepProof) *ProofChecker {
+	return &ProofChecker{osp: osp}
+}
+
+func (c *ProofChecker) CheckProof(
+	beforeHash common.Hash,
+	timeBounds *protocol.TimeBounds,
+	inbox value.TupleValue,
+	assertion *protocol.ExecutionAssertion,
+	proof []byte,
+) error {
+	precond := valprotocol.NewPrecondition(beforeHash, timeBounds, inbox)
+	res, err := c.osp.ValidateProof(
+		context.Background(),
+		precond,
+		valprotocol.NewExecutionAssertionStubFromAssertion(assertion),
+		proof,
+	)
+	if err != nil {
+		return err
+	}
+	if res.Cmp(big.NewInt(0)) != 0 {
+		return fmt.Errorf("one step proof contract rejected the proof with code %v", res)
+	}
+	return nil
+}
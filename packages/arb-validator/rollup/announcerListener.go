@@ -43,6 +43,10 @@ func (al *AnnouncerListener) StakeMoved(ctx context.Context, observer *ChainObse
 	log.Printf("%v Staker %v moved to location: %v\n", al.Prefix, ev.Staker, ev.Location)
 }
 
+func (al *AnnouncerListener) StartedChain(context.Context, *ChainObserver) {
+	log.Println(al.Prefix, "StartedChain")
+}
+
 func (al *AnnouncerListener) StartedChallenge(context.Context, *ChainObserver, *Challenge) {
 	log.Println(al.Prefix, "StartedChallenge")
 }
@@ -29,10 +29,27 @@ import (
 type FinalizedAssertion struct {
 	Assertion     *protocol.ExecutionAssertion // Disputable assertion
 	OnChainTxHash common.Hash                  // Disputable assertion on-chain Tx hash
+	NodeHash      common.Hash                  // Hash of the node created by the assertion
+	NodeDepth     uint64                       // Depth of the node created by the assertion
 }
 
 type AssertionListener struct {
 	CompletedAssertionChan chan FinalizedAssertion
+	// RolledBackChan receives the depth of the calculated valid node each
+	// time the chain starts. Assertions deeper than that which were already
+	// finalized may have been removed by a reorg, and the ones which weren't
+	// are sent to CompletedAssertionChan again
+	RolledBackChan chan uint64
+}
+
+func (al *AssertionListener) StartedChain(_ context.Context, chain *ChainObserver) {
+	if al.RolledBackChan == nil {
+		return
+	}
+	chain.RLock()
+	depth := chain.calculatedValidNode.depth
+	chain.RUnlock()
+	al.RolledBackChan <- depth
 }
 
 func (al *AssertionListener) StakeCreated(context.Context, *ChainObserver, arbbridge.StakeCreatedEvent) {
@@ -67,8 +84,13 @@ func (al *AssertionListener) OldStakes(context.Context, *ChainObserver, []recove
 func (al *AssertionListener) AdvancedCalculatedValidNode(context.Context, *ChainObserver, common.Hash) {
 }
 func (al *AssertionListener) AdvancedKnownAssertion(ctx context.Context, chain *ChainObserver, assertion *protocol.ExecutionAssertion, txHash common.Hash) {
+	// The opinion thread sets the calculated valid node before notifying
+	// listeners so it is the node created by this assertion
+	node := chain.calculatedValidNode
 	al.CompletedAssertionChan <- FinalizedAssertion{
 		Assertion:     assertion,
 		OnChainTxHash: txHash,
+		NodeHash:      node.hash,
+		NodeDepth:     node.depth,
 	}
 }
@@ -30,13 +30,32 @@ import (
 	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
 	"github.com/offchainlabs/arbitrum/packages/arb-util/protocol"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/arbbridge"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/metrics"
 )
 
 const (
 	PruneSizeLimit = 120
+
+	// Number of blocks after a failed transaction during which a staking key
+	// is only used if no other key can act instead
+	stakingKeyBackoffBlocks = 3
+)
+
+var (
+	assertionsPreparedCounter = metrics.NewCounter("arbitrum/rollup/assertions/prepared")
+	assertionsMadeCounter     = metrics.NewCounter("arbitrum/rollup/assertions/made")
+	challengesStartedCounter  = metrics.NewCounter("arbitrum/rollup/challenges/started")
+	challengesWonCounter      = metrics.NewCounter("arbitrum/rollup/challenges/won")
+	challengesLostCounter     = metrics.NewCounter("arbitrum/rollup/challenges/lost")
+	executeAssertionTimer     = metrics.NewTimer("arbitrum/rollup/assertions/execute")
 )
 
 type ChainListener interface {
+	// StartedChain is called each time the chain starts following the L1
+	// chain, which is after startup and after the chain is rebuilt from a
+	// checkpoint because of a reorg. Anything the listener learned about
+	// nodes after the chain's calculated valid node may no longer be valid
+	StartedChain(context.Context, *ChainObserver)
 	StakeCreated(context.Context, *ChainObserver, arbbridge.StakeCreatedEvent)
 	StakeRemoved(context.Context, *ChainObserver, arbbridge.StakeRefundedEvent)
 	StakeMoved(context.Context, *ChainObserver, arbbridge.StakeMovedEvent)
@@ -61,6 +80,10 @@ type ChainListener interface {
 type StakingKey struct {
 	client   arbbridge.ArbAuthClient
 	contract arbbridge.ArbRollup
+	policy   StakingPolicy
+
+	// Block at which the key last failed to send a transaction
+	lastFailure *common.TimeBlocks
 }
 
 type ValidatorChainListener struct {
@@ -68,10 +91,13 @@ type ValidatorChainListener struct {
 	actor                  arbbridge.ArbRollup
 	rollupAddress          common.Address
 	stakingKeys            map[common.Address]*StakingKey
+	stakingKeyOrder        []common.Address
 	broadcastAssertions    map[common.Hash]*valprotocol.AssertionParams
 	broadcastConfirmations map[common.Hash]bool
 	broadcastLeafPrunes    map[common.Hash]bool
 	broadcastCreateStakes  map[common.Address]*common.TimeBlocks
+	broadcastChallenges    map[common.Address]bool
+	challengeStrategy      challenges.ChallengeStrategy
 }
 
 func NewValidatorChainListener(ctx context.Context, rollupAddress common.Address, actor arbbridge.ArbRollup) *ValidatorChainListener {
@@ -83,6 +109,8 @@ func NewValidatorChainListener(ctx context.Context, rollupAddress common.Address
 		broadcastConfirmations: make(map[common.Hash]bool),
 		broadcastLeafPrunes:    make(map[common.Hash]bool),
 		broadcastCreateStakes:  make(map[common.Address]*common.TimeBlocks),
+		broadcastChallenges:    make(map[common.Address]bool),
+		challengeStrategy:      challenges.DefaultChallengeStrategy{},
 	}
 	go func() {
 		ticker := time.NewTicker(common.NewTimeBlocksInt(30).Duration())
@@ -97,6 +125,7 @@ func NewValidatorChainListener(ctx context.Context, rollupAddress common.Address
 				ret.broadcastConfirmations = make(map[common.Hash]bool)
 				ret.broadcastLeafPrunes = make(map[common.Hash]bool)
 				ret.broadcastCreateStakes = make(map[common.Address]*common.TimeBlocks)
+				ret.broadcastChallenges = make(map[common.Address]bool)
 				ret.Unlock()
 			}
 		}
@@ -114,16 +143,94 @@ func stakeLatestValid(ctx context.Context, chain *ChainObserver, stakingKey *Sta
 	return stakingKey.contract.PlaceStake(ctx, stakeAmount, proof1, proof2)
 }
 
+// AddStaker adds a staking key that is used for all actions
 func (lis *ValidatorChainListener) AddStaker(client arbbridge.ArbAuthClient) error {
+	return lis.AddStakerWithPolicy(client, FullStakingPolicy)
+}
+
+// AddStakerWithPolicy adds a staking key that is only used for the actions
+// allowed by policy. When several keys may take an action, the key added
+// first is used unless it recently failed to send a transaction
+func (lis *ValidatorChainListener) AddStakerWithPolicy(client arbbridge.ArbAuthClient, policy StakingPolicy) error {
 	contract, err := client.NewRollup(lis.rollupAddress)
 	if err != nil {
 		return err
 	}
 
 	address := client.Address()
+	lis.Lock()
+	defer lis.Unlock()
+	if _, ok := lis.stakingKeys[address]; !ok {
+		lis.stakingKeyOrder = append(lis.stakingKeyOrder, address)
+	}
 	lis.stakingKeys[address] = &StakingKey{
 		client:   client,
 		contract: contract,
+		policy:   policy,
+	}
+	return nil
+}
+
+// SetChallengeStrategy sets the strategy that the listener's staking keys use
+// in challenges started after this is called
+func (lis *ValidatorChainListener) SetChallengeStrategy(strategy challenges.ChallengeStrategy) {
+	lis.Lock()
+	defer lis.Unlock()
+	lis.challengeStrategy = strategy
+}
+
+// orderedStakingKeys returns the staking keys in the order they were added,
+// except that keys which failed within the last few blocks come last so that
+// other keys back them up
+func (lis *ValidatorChainListener) orderedStakingKeys(chain *ChainObserver) []*StakingKey {
+	backoffStart := new(big.Int).Sub(chain.latestBlockId.Height.AsInt(), big.NewInt(stakingKeyBackoffBlocks))
+	keys := make([]*StakingKey, 0, len(lis.stakingKeyOrder))
+	var failedKeys []*StakingKey
+	lis.Lock()
+	defer lis.Unlock()
+	for _, address := range lis.stakingKeyOrder {
+		key := lis.stakingKeys[address]
+		if key.lastFailure != nil && key.lastFailure.AsInt().Cmp(backoffStart) > 0 {
+			failedKeys = append(failedKeys, key)
+		} else {
+			keys = append(keys, key)
+		}
+	}
+	return append(keys, failedKeys...)
+}
+
+func (lis *ValidatorChainListener) stakingKeyFailed(key *StakingKey, height *common.TimeBlocks) {
+	lis.Lock()
+	key.lastFailure = height
+	lis.Unlock()
+}
+
+// stakedKeyCount returns the number of the listener's keys which are staked
+// or are in the process of placing a stake
+func (lis *ValidatorChainListener) stakedKeyCount(chain *ChainObserver) int {
+	count := 0
+	lis.Lock()
+	defer lis.Unlock()
+	for address := range lis.stakingKeys {
+		_, placingStake := lis.broadcastCreateStakes[address]
+		if placingStake || chain.nodeGraph.stakers.Get(address) != nil {
+			count++
+		}
+	}
+	return count
+}
+
+// anyoneActor returns the key used for actions that don't require a stake,
+// or nil if all keys are watch only. If no keys were added, the listener's
+// actor is used
+func (lis *ValidatorChainListener) anyoneActor(chain *ChainObserver) *StakingKey {
+	if len(lis.stakingKeys) == 0 {
+		return &StakingKey{contract: lis.actor}
+	}
+	for _, key := range lis.orderedStakingKeys(chain) {
+		if !key.policy.isWatchOnly() {
+			return key
+		}
 	}
 	return nil
 }
@@ -145,6 +252,7 @@ func makeAssertion(ctx context.Context, rollup arbbridge.ArbRollup, prepared *pr
 func (lis *ValidatorChainListener) AssertionPrepared(ctx context.Context, chain *ChainObserver, prepared *preparedAssertion) {
 	// Anyone confirm a node
 	// No need to have your own stake
+	assertionsPreparedCounter.Inc(1)
 	lis.Lock()
 	prevParams, alreadySent := lis.broadcastAssertions[prepared.leafHash]
 	lis.Unlock()
@@ -158,7 +266,13 @@ func (lis *ValidatorChainListener) AssertionPrepared(ctx context.Context, chain
 		return
 	}
 
-	for stakingAddress, stakingKey := range lis.stakingKeys {
+	currentHeight := chain.latestBlockId.Height
+	stakingKeys := lis.orderedStakingKeys(chain)
+	for _, stakingKey := range stakingKeys {
+		if !stakingKey.policy.Assert {
+			continue
+		}
+		stakingAddress := stakingKey.client.Address()
 		stakerPos := chain.nodeGraph.stakers.Get(stakingAddress)
 		if stakerPos == nil {
 			// stakingKey is not staked
@@ -177,18 +291,25 @@ func (lis *ValidatorChainListener) AssertionPrepared(ctx context.Context, chain
 			err := makeAssertion(ctx, stakingKey.contract, prepared.Clone(), proof)
 			if err != nil {
 				log.Println("Error making assertion", err)
+				lis.stakingKeyFailed(stakingKey, currentHeight)
 				lis.Lock()
 				delete(lis.broadcastAssertions, prepared.leafHash)
 				lis.Unlock()
 			} else {
 				log.Println("Successfully made assertion")
+				assertionsMadeCounter.Inc(1)
 			}
 		}()
 		return
 	}
 
 	log.Println("Maybe putting down stake")
-	for stakingAddress, stakingKey := range lis.stakingKeys {
+	stakedCount := lis.stakedKeyCount(chain)
+	for _, stakingKey := range stakingKeys {
+		if !stakingKey.policy.mayStake(stakedCount) {
+			continue
+		}
+		stakingAddress := stakingKey.client.Address()
 		stakerPos := chain.nodeGraph.stakers.Get(stakingAddress)
 		if stakerPos != nil {
 			// stakingKey is already down
@@ -207,6 +328,7 @@ func (lis *ValidatorChainListener) AssertionPrepared(ctx context.Context, chain
 			go func() {
 				err := stakeLatestValid(ctx, chain, stakingKey)
 				if err != nil {
+					lis.stakingKeyFailed(stakingKey, currentHeight)
 					lis.Lock()
 					delete(lis.broadcastCreateStakes, stakingAddress)
 					lis.Unlock()
@@ -220,23 +342,77 @@ func (lis *ValidatorChainListener) AssertionPrepared(ctx context.Context, chain
 	}
 }
 
-func (lis *ValidatorChainListener) initiateChallenge(ctx context.Context, opp *challengeOpportunity) error {
-	return lis.actor.StartChallenge(
-		ctx,
-		opp.asserter,
-		opp.challenger,
-		opp.prevNodeHash,
-		opp.deadlineTicks.Val,
-		opp.asserterNodeType,
-		opp.challengerNodeType,
-		opp.asserterVMProtoHash,
-		opp.challengerVMProtoHash,
-		opp.asserterProof,
-		opp.challengerProof,
-		opp.asserterNodeHash,
-		opp.challengerDataHash,
-		opp.challengerPeriodTicks,
-	)
+// challengeSender returns the key that initiates the challenge in opp, or
+// nil if no key's policy allows it. If one of the listener's keys is a party
+// to the challenge, only that key may initiate it. If no keys were added, the
+// listener's actor is used
+func (lis *ValidatorChainListener) challengeSender(opp *challengeOpportunity) *StakingKey {
+	if len(lis.stakingKeys) == 0 {
+		return &StakingKey{contract: lis.actor}
+	}
+	if key, ok := lis.stakingKeys[opp.asserter]; ok {
+		if key.policy.mayChallenge(opp.challenger) {
+			return key
+		}
+		return nil
+	}
+	if key, ok := lis.stakingKeys[opp.challenger]; ok {
+		if key.policy.mayChallenge(opp.asserter) {
+			return key
+		}
+		return nil
+	}
+	for _, address := range lis.stakingKeyOrder {
+		key := lis.stakingKeys[address]
+		if key.policy.mayChallenge(opp.asserter) && key.policy.mayChallenge(opp.challenger) {
+			return key
+		}
+	}
+	return nil
+}
+
+// initiateChallenge starts the challenge in opp if a key may initiate it and
+// it hasn't already been started
+func (lis *ValidatorChainListener) initiateChallenge(ctx context.Context, opp *challengeOpportunity) {
+	key := lis.challengeSender(opp)
+	if key == nil {
+		return
+	}
+	lis.Lock()
+	if lis.broadcastChallenges[opp.asserter] || lis.broadcastChallenges[opp.challenger] {
+		lis.Unlock()
+		return
+	}
+	lis.broadcastChallenges[opp.asserter] = true
+	lis.broadcastChallenges[opp.challenger] = true
+	lis.Unlock()
+	go func() {
+		err := key.contract.StartChallenge(
+			ctx,
+			opp.asserter,
+			opp.challenger,
+			opp.prevNodeHash,
+			opp.deadlineTicks.Val,
+			opp.asserterNodeType,
+			opp.challengerNodeType,
+			opp.asserterVMProtoHash,
+			opp.challengerVMProtoHash,
+			opp.asserterProof,
+			opp.challengerProof,
+			opp.asserterNodeHash,
+			opp.challengerDataHash,
+			opp.challengerPeriodTicks,
+		)
+		if err != nil {
+			log.Println("Failed to initiate challenge", err)
+			lis.Lock()
+			delete(lis.broadcastChallenges, opp.asserter)
+			delete(lis.broadcastChallenges, opp.challenger)
+			lis.Unlock()
+		} else {
+			log.Println("Successfully initiated challenge")
+		}
+	}()
 }
 
 func (lis *ValidatorChainListener) StakeCreated(ctx context.Context, chain *ChainObserver, ev arbbridge.StakeCreatedEvent) {
@@ -248,14 +424,7 @@ func (lis *ValidatorChainListener) StakeCreated(ctx context.Context, chain *Chai
 		}
 		opp := chain.nodeGraph.checkChallengeOpportunityAny(staker)
 		if opp != nil {
-			go func() {
-				err := lis.initiateChallenge(ctx, opp)
-				if err != nil {
-					log.Println("Failed to initiate challenge", err)
-				} else {
-					log.Println("Successfully initiated challenge")
-				}
-			}()
+			lis.initiateChallenge(ctx, opp)
 		}
 	} else {
 		lis.challengeStakerIfPossible(ctx, chain, ev.Staker)
@@ -278,35 +447,24 @@ func (lis *ValidatorChainListener) challengeStakerIfPossible(ctx context.Context
 		log.Fatalf("Nonexistant staker moved %v", stakerAddr)
 	}
 
-	// Search for an already staked staking key
-	for myAddr := range lis.stakingKeys {
-		meAsStaker := chain.nodeGraph.stakers.Get(myAddr)
+	// Search for an already staked staking key that may challenge
+	for _, stakingKey := range lis.orderedStakingKeys(chain) {
+		if !stakingKey.policy.mayChallenge(stakerAddr) {
+			continue
+		}
+		meAsStaker := chain.nodeGraph.stakers.Get(stakingKey.client.Address())
 		if meAsStaker == nil {
 			continue
 		}
 		opp := chain.nodeGraph.checkChallengeOpportunityPair(newStaker, meAsStaker)
 		if opp != nil {
-			go func() {
-				err := lis.initiateChallenge(ctx, opp)
-				if err != nil {
-					log.Println("Failed to initiate challenge", err)
-				} else {
-					log.Println("Successfully initiated challenge")
-				}
-			}()
+			lis.initiateChallenge(ctx, opp)
 			return
 		}
 	}
 	opp := chain.nodeGraph.checkChallengeOpportunityAny(newStaker)
 	if opp != nil {
-		go func() {
-			err := lis.initiateChallenge(ctx, opp)
-			if err != nil {
-				log.Println("Failed to initiate challenge", err)
-			} else {
-				log.Println("Successfully initiated challenge")
-			}
-		}()
+		lis.initiateChallenge(ctx, opp)
 		return
 	}
 }
@@ -314,9 +472,18 @@ func (lis *ValidatorChainListener) challengeStakerIfPossible(ctx context.Context
 // All functions below are either only called if you have a stake down, or don't require a stake
 
 func (lis *ValidatorChainListener) StartedChallenge(ctx context.Context, chain *ChainObserver, chal *Challenge) {
+	lis.Lock()
+	_, isAsserter := lis.stakingKeys[chal.asserter]
+	_, isChallenger := lis.stakingKeys[chal.challenger]
+	lis.Unlock()
+	if isAsserter || isChallenger {
+		challengesStartedCounter.Inc(1)
+	}
 	lis.launchChallenge(ctx, chain, chal)
 }
 
+func (lis *ValidatorChainListener) StartedChain(context.Context, *ChainObserver) {}
+
 func (lis *ValidatorChainListener) ResumedChallenge(ctx context.Context, chain *ChainObserver, chal *Challenge) {
 	lis.launchChallenge(ctx, chain, chal)
 }
@@ -325,8 +492,12 @@ func (lis *ValidatorChainListener) launchChallenge(ctx context.Context, chain *C
 	// Must already be staked to be challenged
 	startBlockId := chal.blockId
 	startLogIndex := chal.logIndex - 1
-	asserterKey, ok := lis.stakingKeys[chal.asserter]
-	if ok {
+	lis.Lock()
+	strategy := lis.challengeStrategy
+	asserterKey, isAsserter := lis.stakingKeys[chal.asserter]
+	challenger, isChallenger := lis.stakingKeys[chal.challenger]
+	lis.Unlock()
+	if isAsserter {
 		switch chal.conflictNode.linkType {
 		case valprotocol.InvalidInboxTopChildType:
 			go func() {
@@ -342,7 +513,7 @@ func (lis *ValidatorChainListener) launchChallenge(ctx context.Context, chain *C
 						chal.conflictNode.disputable.MaxInboxCount,
 						new(big.Int).Add(chal.conflictNode.prev.vmProtoData.InboxCount, chal.conflictNode.disputable.AssertionParams.ImportedMessageCount),
 					),
-					100,
+					strategy,
 				)
 				if err != nil {
 					log.Println("Failed defending inbox top claim", err)
@@ -361,7 +532,7 @@ func (lis *ValidatorChainListener) launchChallenge(ctx context.Context, chain *C
 					chain.inbox.MessageStack,
 					chal.conflictNode.vmProtoData.InboxTop,
 					chal.conflictNode.disputable.AssertionParams.ImportedMessageCount,
-					100,
+					strategy,
 				)
 				if err != nil {
 					log.Println("Failed defending messages claim", err)
@@ -380,7 +551,7 @@ func (lis *ValidatorChainListener) launchChallenge(ctx context.Context, chain *C
 					chain.executionPrecondition(chal.conflictNode),
 					chal.conflictNode.prev.machine,
 					chal.conflictNode.disputable.AssertionParams.NumSteps,
-					50,
+					strategy,
 				)
 				if err != nil {
 					log.Println("Failed defending execution claim", err)
@@ -393,8 +564,7 @@ func (lis *ValidatorChainListener) launchChallenge(ctx context.Context, chain *C
 		}
 	}
 
-	challenger, ok := lis.stakingKeys[chal.challenger]
-	if ok {
+	if isChallenger {
 		switch chal.conflictNode.linkType {
 		case valprotocol.InvalidInboxTopChildType:
 			go func() {
@@ -405,7 +575,7 @@ func (lis *ValidatorChainListener) launchChallenge(ctx context.Context, chain *C
 					startBlockId,
 					startLogIndex,
 					chain.inbox.MessageStack,
-					false,
+					strategy,
 				)
 				if err != nil {
 					log.Println("Failed challenging inbox top claim", err)
@@ -424,7 +594,7 @@ func (lis *ValidatorChainListener) launchChallenge(ctx context.Context, chain *C
 					chain.inbox.MessageStack,
 					chal.conflictNode.vmProtoData.InboxTop,
 					chal.conflictNode.disputable.AssertionParams.ImportedMessageCount,
-					false,
+					strategy,
 				)
 				if err != nil {
 					log.Println("Failed challenging messages claim", err)
@@ -442,7 +612,7 @@ func (lis *ValidatorChainListener) launchChallenge(ctx context.Context, chain *C
 					startLogIndex,
 					chain.executionPrecondition(chal.conflictNode),
 					chal.conflictNode.prev.machine,
-					false,
+					strategy,
 				)
 				if err != nil {
 					log.Println("Failed challenging execution claim", err)
@@ -481,11 +651,17 @@ func (lis *ValidatorChainListener) ConfirmableNodes(ctx context.Context, observe
 	}
 	lis.broadcastConfirmations[conf.CurrentLatestConfirmed] = true
 	lis.Unlock()
+	key := lis.anyoneActor(observer)
+	if key == nil {
+		return
+	}
+	currentHeight := observer.latestBlockId.Height
 	confClone := conf.Clone()
 	go func() {
-		err := lis.actor.Confirm(ctx, confClone)
+		err := key.contract.Confirm(ctx, confClone)
 		if err != nil {
 			log.Println("Failed to confirm valid node", err)
+			lis.stakingKeyFailed(key, currentHeight)
 			lis.Lock()
 			delete(lis.broadcastConfirmations, confClone.CurrentLatestConfirmed)
 			lis.Unlock()
@@ -495,6 +671,11 @@ func (lis *ValidatorChainListener) ConfirmableNodes(ctx context.Context, observe
 
 func (lis *ValidatorChainListener) PrunableLeafs(ctx context.Context, observer *ChainObserver, params []valprotocol.PruneParams) {
 	// Anyone can prune a leaf
+	key := lis.anyoneActor(observer)
+	if key == nil {
+		return
+	}
+	currentHeight := observer.latestBlockId.Height
 	leavesToPrune := make([]valprotocol.PruneParams, 0, len(params))
 	lis.Lock()
 	totalSize := 0
@@ -512,9 +693,10 @@ func (lis *ValidatorChainListener) PrunableLeafs(ctx context.Context, observer *
 	}
 	lis.Unlock()
 	go func() {
-		err := lis.actor.PruneLeaves(ctx, leavesToPrune)
+		err := key.contract.PruneLeaves(ctx, leavesToPrune)
 		if err != nil {
 			log.Println("Failed pruning leaves", err)
+			lis.stakingKeyFailed(key, currentHeight)
 			lis.Lock()
 			for _, prune := range leavesToPrune {
 				delete(lis.broadcastLeafPrunes, prune.LeafHash)
@@ -526,9 +708,13 @@ func (lis *ValidatorChainListener) PrunableLeafs(ctx context.Context, observer *
 
 func (lis *ValidatorChainListener) MootableStakes(ctx context.Context, observer *ChainObserver, params []recoverStakeMootedParams) {
 	// Anyone can moot any stake
+	key := lis.anyoneActor(observer)
+	if key == nil {
+		return
+	}
 	for _, moot := range params {
 		go func() {
-			lis.actor.RecoverStakeMooted(
+			key.contract.RecoverStakeMooted(
 				ctx,
 				moot.ancestorHash,
 				moot.addr,
@@ -541,9 +727,13 @@ func (lis *ValidatorChainListener) MootableStakes(ctx context.Context, observer
 
 func (lis *ValidatorChainListener) OldStakes(ctx context.Context, observer *ChainObserver, params []recoverStakeOldParams) {
 	// Anyone can remove an old stake
+	key := lis.anyoneActor(observer)
+	if key == nil {
+		return
+	}
 	for _, old := range params {
 		go func() {
-			lis.actor.RecoverStakeOld(
+			key.contract.RecoverStakeOld(
 				ctx,
 				old.addr,
 				old.proof,
@@ -553,7 +743,10 @@ func (lis *ValidatorChainListener) OldStakes(ctx context.Context, observer *Chai
 }
 
 func (lis *ValidatorChainListener) AdvancedCalculatedValidNode(ctx context.Context, chain *ChainObserver, nodeHash common.Hash) {
-	for stakingAddress, _ := range lis.stakingKeys {
+	// Every key keeps its existing stake on the valid branch, including watch
+	// only keys, since a stake that is left behind can be lost
+	for _, stakingKey := range lis.orderedStakingKeys(chain) {
+		stakingAddress := stakingKey.client.Address()
 		staker := chain.nodeGraph.stakers.idx[stakingAddress]
 		if staker == nil {
 			continue
@@ -562,15 +755,21 @@ func (lis *ValidatorChainListener) AdvancedCalculatedValidNode(ctx context.Conte
 		if newValidNode.depth > staker.location.depth {
 			proof1 := GeneratePathProof(staker.location, newValidNode)
 			proof2 := GeneratePathProof(newValidNode, chain.nodeGraph.getLeaf(newValidNode))
-			lis.actor.MoveStake(ctx, proof1, proof2)
+			// Each key moves its own stake
+			stakingKey.contract.MoveStake(ctx, proof1, proof2)
 		}
 	}
 }
 
 func (lis *ValidatorChainListener) StakeRemoved(context.Context, *ChainObserver, arbbridge.StakeRefundedEvent) {
 }
-func (lis *ValidatorChainListener) lostChallenge(arbbridge.ChallengeCompletedEvent) {}
-func (lis *ValidatorChainListener) wonChallenge(arbbridge.ChallengeCompletedEvent)  {}
+func (lis *ValidatorChainListener) lostChallenge(arbbridge.ChallengeCompletedEvent) {
+	challengesLostCounter.Inc(1)
+}
+
+func (lis *ValidatorChainListener) wonChallenge(arbbridge.ChallengeCompletedEvent) {
+	challengesWonCounter.Inc(1)
+}
 func (lis *ValidatorChainListener) SawAssertion(context.Context, *ChainObserver, arbbridge.AssertedEvent) {
 }
 func (lis *ValidatorChainListener) ConfirmedNode(context.Context, *ChainObserver, arbbridge.ConfirmedEvent) {
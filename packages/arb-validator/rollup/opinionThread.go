@@ -243,7 +243,9 @@ func (chain *ChainObserver) prepareAssertion() *preparedAssertion {
 
 	beforeHash := mach.Hash()
 
+	executionStart := time.Now()
 	assertion, stepsRun := mach.ExecuteAssertion(maxSteps, timeBounds, messagesVal, runDuration)
+	executeAssertionTimer.UpdateSince(executionStart)
 
 	afterHash := mach.Hash()
 
@@ -49,6 +49,10 @@ type ChainObserver struct {
 	checkpointer        checkpointing.RollupCheckpointer
 	isOpinionated       bool
 	atHead              bool
+
+	// unconfirmedBlockId is the newest L1 block when the chain only
+	// processes blocks once they're confirmed. It's never checkpointed
+	unconfirmedBlockId *common.BlockId
 }
 
 func NewChain(
@@ -85,6 +89,9 @@ func NewChain(
 }
 
 func (chain *ChainObserver) Start(ctx context.Context) {
+	for _, listener := range chain.listeners {
+		listener.StartedChain(ctx, chain)
+	}
 	chain.nodeGraph.challenges.forall(func(c *Challenge) {
 		for _, listener := range chain.listeners {
 			listener.ResumedChallenge(ctx, chain, c)
@@ -198,6 +205,28 @@ func (chain *ChainObserver) NotifyNewBlock(blockId *common.BlockId) {
 	chain.checkpointer.AsyncSaveCheckpoint(blockId.Clone(), buf, ckptCtx)
 }
 
+// NotifyUnconfirmedBlock tells the chain about a block at the head of L1
+// which isn't deep enough to be processed yet. Its events aren't handled and
+// it isn't checkpointed, but deadlines are judged against it speculatively so
+// that they're acted on as soon as they pass on L1 rather than once the block
+// is confirmed. Anything sent early because of a block that is later
+// reorged away is rejected by the rollup contract
+func (chain *ChainObserver) NotifyUnconfirmedBlock(blockId *common.BlockId) {
+	chain.Lock()
+	chain.unconfirmedBlockId = blockId
+	chain.Unlock()
+}
+
+// deadlineHeight is the height that node deadlines are compared with, which
+// is the newest known block whether or not it has been processed. It must be
+// called with the chain locked
+func (chain *ChainObserver) deadlineHeight() *common.TimeBlocks {
+	if chain.unconfirmedBlockId != nil && chain.unconfirmedBlockId.Height.Cmp(chain.latestBlockId.Height) > 0 {
+		return chain.unconfirmedBlockId.Height
+	}
+	return chain.latestBlockId.Height
+}
+
 func (chain *ChainObserver) CurrentBlockId() *common.BlockId {
 	chain.RLock()
 	blockId := chain.latestBlockId
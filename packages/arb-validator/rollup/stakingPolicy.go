@@ -0,0 +1,149 @@
+/*
+* Copyright 2020, Offchain Labs, Inc.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*    http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package rollup
+
+import (
+	"errors"
+	"fmt"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+)
+
+// StakingPolicy controls which actions the ValidatorChainListener takes with
+// a staking key. Regardless of its policy, a key always takes part in
+// challenges that it is already in and moves a stake that it already has
+// forward along the valid branch, since otherwise it would lose its stake
+type StakingPolicy struct {
+	// Assert allows the key to make assertions
+	Assert bool
+
+	// Challenge allows the key to initiate challenges
+	Challenge bool
+
+	// MaxStakesAtRisk is the number of the listener's keys that may be staked
+	// at once. The key doesn't place a stake while that many are staked. Zero
+	// means no limit
+	MaxStakesAtRisk int
+
+	// ChallengeOnly restricts the stakers that the key initiates challenges
+	// against. If empty, any staker may be challenged
+	ChallengeOnly map[common.Address]bool
+
+	// NeverChallenge lists stakers that the key never initiates challenges
+	// against
+	NeverChallenge map[common.Address]bool
+}
+
+var (
+	FullStakingPolicy   = StakingPolicy{Assert: true, Challenge: true}
+	AssertOnlyPolicy    = StakingPolicy{Assert: true}
+	ChallengeOnlyPolicy = StakingPolicy{Challenge: true}
+	WatchOnlyPolicy     = StakingPolicy{}
+)
+
+// ParseStakingPolicy returns the policy with the given name, which is one of
+// full, assert, challenge or watch
+func ParseStakingPolicy(name string) (StakingPolicy, error) {
+	switch name {
+	case "full":
+		return FullStakingPolicy, nil
+	case "assert":
+		return AssertOnlyPolicy, nil
+	case "challenge":
+		return ChallengeOnlyPolicy, nil
+	case "watch":
+		return WatchOnlyPolicy, nil
+	default:
+		return StakingPolicy{}, fmt.Errorf("unknown staking policy %v", name)
+	}
+}
+
+// StakingPolicyConfig describes a StakingPolicy as it is given on the command
+// line or in a staking keys file
+type StakingPolicyConfig struct {
+	// Policy is one of the names accepted by ParseStakingPolicy. If empty,
+	// the full policy is used
+	Policy string `json:"policy"`
+
+	MaxStakesAtRisk int `json:"maxStakesAtRisk"`
+
+	// ChallengeOnly and NeverChallenge are lists of hex encoded addresses
+	ChallengeOnly  []string `json:"challengeOnly"`
+	NeverChallenge []string `json:"neverChallenge"`
+}
+
+// StakingPolicy returns the policy described by the config
+func (c StakingPolicyConfig) StakingPolicy() (StakingPolicy, error) {
+	name := c.Policy
+	if name == "" {
+		name = "full"
+	}
+	policy, err := ParseStakingPolicy(name)
+	if err != nil {
+		return policy, err
+	}
+	if c.MaxStakesAtRisk < 0 {
+		return policy, errors.New("max stakes at risk can't be negative")
+	}
+	policy.MaxStakesAtRisk = c.MaxStakesAtRisk
+	policy.ChallengeOnly, err = parseAddressSet(c.ChallengeOnly)
+	if err != nil {
+		return policy, err
+	}
+	policy.NeverChallenge, err = parseAddressSet(c.NeverChallenge)
+	return policy, err
+}
+
+func parseAddressSet(addresses []string) (map[common.Address]bool, error) {
+	if len(addresses) == 0 {
+		return nil, nil
+	}
+	set := make(map[common.Address]bool, len(addresses))
+	for _, address := range addresses {
+		if !ethcommon.IsHexAddress(address) {
+			return nil, fmt.Errorf("invalid address %v", address)
+		}
+		set[common.HexToAddress(address)] = true
+	}
+	return set, nil
+}
+
+// isWatchOnly returns whether the key never sends transactions except to
+// protect a stake that it already has
+func (p StakingPolicy) isWatchOnly() bool {
+	return !p.Assert && !p.Challenge
+}
+
+// mayStake returns whether the key places a stake given that staked of the
+// listener's keys are currently staked
+func (p StakingPolicy) mayStake(staked int) bool {
+	if p.isWatchOnly() {
+		return false
+	}
+	return p.MaxStakesAtRisk == 0 || staked < p.MaxStakesAtRisk
+}
+
+// mayChallenge returns whether the key may initiate a challenge against
+// staker
+func (p StakingPolicy) mayChallenge(staker common.Address) bool {
+	if !p.Challenge || p.NeverChallenge[staker] {
+		return false
+	}
+	return len(p.ChallengeOnly) == 0 || p.ChallengeOnly[staker]
+}
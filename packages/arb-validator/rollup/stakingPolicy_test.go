@@ -0,0 +1,250 @@
+/*
+* Copyright 2020, Offchain Labs, Inc.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*    http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package rollup
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/arbbridge"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/valprotocol"
+)
+
+// testStakingClient is a staking key whose rollup contract records the
+// transactions that the listener sends with it
+type testStakingClient struct {
+	arbbridge.ArbAuthClient
+	rollup *testStakingRollup
+}
+
+func (c *testStakingClient) Address() common.Address {
+	return c.rollup.address
+}
+
+func (c *testStakingClient) NewRollup(common.Address) (arbbridge.ArbRollup, error) {
+	return c.rollup, nil
+}
+
+type testStakingRollup struct {
+	arbbridge.ArbRollup
+	address common.Address
+	sent    chan string
+}
+
+func (r *testStakingRollup) MoveStake(context.Context, []common.Hash, []common.Hash) error {
+	r.sent <- "MoveStake"
+	return nil
+}
+
+func (r *testStakingRollup) StartChallenge(
+	context.Context,
+	common.Address,
+	common.Address,
+	common.Hash,
+	*big.Int,
+	valprotocol.ChildType,
+	valprotocol.ChildType,
+	common.Hash,
+	common.Hash,
+	[]common.Hash,
+	[]common.Hash,
+	common.Hash,
+	common.Hash,
+	common.TimeTicks,
+) error {
+	r.sent <- "StartChallenge"
+	return nil
+}
+
+func addTestStakingKey(t *testing.T, lis *ValidatorChainListener, address common.Address, policy StakingPolicy) *testStakingRollup {
+	rollup := &testStakingRollup{address: address, sent: make(chan string, 10)}
+	if err := lis.AddStakerWithPolicy(&testStakingClient{rollup: rollup}, policy); err != nil {
+		t.Fatal(err)
+	}
+	return rollup
+}
+
+func expectSent(t *testing.T, rollup *testStakingRollup, method string) {
+	t.Helper()
+	select {
+	case sent := <-rollup.sent:
+		if sent != method {
+			t.Error(rollup.address, "sent", sent, "instead of", method)
+		}
+	case <-time.After(time.Second):
+		t.Error(rollup.address, "didn't send", method)
+	}
+}
+
+func expectNothingSent(t *testing.T, rollup *testStakingRollup) {
+	t.Helper()
+	if len(rollup.sent) != 0 {
+		t.Error(rollup.address, "sent", <-rollup.sent)
+	}
+}
+
+// testStakingChain returns a chain with a single branch of three nodes, in
+// which the given stakers are staked on the first node
+func testStakingChain(stakerAddresses ...common.Address) (*ChainObserver, []*Node) {
+	nodes := make([]*Node, 3)
+	nodeFromHash := make(map[common.Hash]*Node)
+	for i := range nodes {
+		nodes[i] = &Node{depth: uint64(i), hash: common.Hash{byte(i + 1)}, innerHash: common.Hash{byte(i + 1)}}
+		if i > 0 {
+			nodes[i].prev = nodes[i-1]
+			nodes[i].linkType = valprotocol.ValidChildType
+			nodes[i-1].successorHashes[valprotocol.ValidChildType] = nodes[i].hash
+		}
+		nodeFromHash[nodes[i].hash] = nodes[i]
+	}
+	stakers := NewStakerSet()
+	for _, address := range stakerAddresses {
+		stakers.Add(&Staker{address: address, location: nodes[0]})
+	}
+	chain := &ChainObserver{
+		nodeGraph: &StakedNodeGraph{
+			NodeGraph: &NodeGraph{latestConfirmed: nodes[0], nodeFromHash: nodeFromHash},
+			stakers:   stakers,
+		},
+		latestBlockId: &common.BlockId{Height: common.NewTimeBlocksInt(10)},
+	}
+	return chain, nodes
+}
+
+func TestStakingPolicyRoles(t *testing.T) {
+	staker := common.Address{5}
+	for _, name := range []string{"full", "assert", "challenge", "watch"} {
+		policy, err := ParseStakingPolicy(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		canAssert := name == "full" || name == "assert"
+		canChallenge := name == "full" || name == "challenge"
+		if policy.Assert != canAssert {
+			t.Error(name, "policy has Assert", policy.Assert)
+		}
+		if policy.mayChallenge(staker) != canChallenge {
+			t.Error(name, "policy allows challenge", policy.mayChallenge(staker))
+		}
+		if policy.mayStake(0) != (name != "watch") {
+			t.Error(name, "policy allows staking", policy.mayStake(0))
+		}
+	}
+	if _, err := ParseStakingPolicy("evil"); err == nil {
+		t.Error("parsed unknown policy")
+	}
+}
+
+func TestStakingPolicyLimits(t *testing.T) {
+	friend := common.Address{1}
+	target := common.Address{2}
+	other := common.Address{3}
+
+	policy := FullStakingPolicy
+	policy.MaxStakesAtRisk = 2
+	policy.NeverChallenge = map[common.Address]bool{friend: true}
+	if !policy.mayStake(1) || policy.mayStake(2) {
+		t.Error("stake limit not applied")
+	}
+	if policy.mayChallenge(friend) || !policy.mayChallenge(other) {
+		t.Error("excluded staker not respected")
+	}
+
+	policy.ChallengeOnly = map[common.Address]bool{target: true}
+	if !policy.mayChallenge(target) || policy.mayChallenge(other) {
+		t.Error("challenge targets not respected")
+	}
+}
+
+func TestListenerMovesStakesOfAllKeys(t *testing.T) {
+	full := common.Address{1}
+	watch := common.Address{2}
+	unstaked := common.Address{3}
+	lis := NewValidatorChainListener(context.Background(), common.Address{}, nil)
+	fullRollup := addTestStakingKey(t, lis, full, FullStakingPolicy)
+	watchRollup := addTestStakingKey(t, lis, watch, WatchOnlyPolicy)
+	unstakedRollup := addTestStakingKey(t, lis, unstaked, FullStakingPolicy)
+
+	chain, nodes := testStakingChain(full, watch)
+	lis.AdvancedCalculatedValidNode(context.Background(), chain, nodes[1].hash)
+	expectSent(t, fullRollup, "MoveStake")
+	expectSent(t, watchRollup, "MoveStake")
+	expectNothingSent(t, unstakedRollup)
+}
+
+func TestListenerChallengeSender(t *testing.T) {
+	friend := common.Address{1}
+	enemy := common.Address{2}
+	lis := NewValidatorChainListener(context.Background(), common.Address{}, nil)
+
+	friendly := FullStakingPolicy
+	friendly.NeverChallenge = map[common.Address]bool{friend: true}
+	friendlyRollup := addTestStakingKey(t, lis, common.Address{3}, friendly)
+	targeted := ChallengeOnlyPolicy
+	targeted.ChallengeOnly = map[common.Address]bool{friend: true, enemy: true}
+	targetedRollup := addTestStakingKey(t, lis, common.Address{4}, targeted)
+	addTestStakingKey(t, lis, common.Address{5}, WatchOnlyPolicy)
+
+	// The first key won't challenge its friend, so the second key does
+	opp := &challengeOpportunity{asserter: friend, challenger: enemy, deadlineTicks: common.TimeTicks{Val: big.NewInt(0)}}
+	lis.initiateChallenge(context.Background(), opp)
+	expectSent(t, targetedRollup, "StartChallenge")
+	expectNothingSent(t, friendlyRollup)
+
+	// The challenge is only started once
+	lis.initiateChallenge(context.Background(), opp)
+	expectNothingSent(t, targetedRollup)
+
+	// A key that is a party to the challenge must initiate it itself, even
+	// if another key would be allowed to
+	opp = &challengeOpportunity{asserter: common.Address{3}, challenger: friend}
+	if key := lis.challengeSender(opp); key != nil {
+		t.Error("challenge sent by", key.client.Address(), "against its friend")
+	}
+	opp = &challengeOpportunity{asserter: common.Address{4}, challenger: common.Address{6}}
+	if key := lis.challengeSender(opp); key != nil {
+		t.Error("challenge sent by", key.client.Address(), "outside its targets")
+	}
+	opp = &challengeOpportunity{asserter: common.Address{5}, challenger: enemy}
+	if key := lis.challengeSender(opp); key != nil {
+		t.Error("watch only key challenged")
+	}
+}
+
+func TestListenerStakedKeyCount(t *testing.T) {
+	staked := common.Address{1}
+	placing := common.Address{2}
+	lis := NewValidatorChainListener(context.Background(), common.Address{}, nil)
+	limited := FullStakingPolicy
+	limited.MaxStakesAtRisk = 2
+	for _, address := range []common.Address{staked, placing, {3}} {
+		addTestStakingKey(t, lis, address, limited)
+	}
+	lis.broadcastCreateStakes[placing] = common.NewTimeBlocksInt(9)
+
+	chain, _ := testStakingChain(staked, common.Address{4})
+	count := lis.stakedKeyCount(chain)
+	if count != 2 {
+		t.Error("counted", count, "staked keys instead of 2")
+	}
+	if limited.mayStake(count) {
+		t.Error("third key may stake despite limit")
+	}
+}
@@ -0,0 +1,245 @@
+/*
+* Copyright 2020, Offchain Labs, Inc.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*    http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package rollup
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/protocol"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/arbbridge"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/metrics"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/valprotocol"
+)
+
+var (
+	invalidStakeAlertsCounter = metrics.NewCounter("arbitrum/watchtower/alerts")
+	invalidStakersGauge       = metrics.NewGauge("arbitrum/watchtower/invalidstakers")
+)
+
+const webhookTimeout = time.Second * 10
+
+// InvalidStakeAlert describes a staker whose stake is on a node that
+// conflicts with the locally calculated valid node
+type InvalidStakeAlert struct {
+	Staker common.Address
+	// StakerNode is the node that the staker is staked on
+	StakerNode common.Hash
+	// InvalidNode is the first node on the staker's branch that conflicts
+	// with the valid branch
+	InvalidNode common.Hash
+	// ValidNode is the node on the valid branch which InvalidNode conflicts
+	// with
+	ValidNode common.Hash
+	// BlocksUntilConfirmable is the number of blocks until InvalidNode's
+	// deadline passes, after which it can be confirmed unless a staker on the
+	// valid branch is staked on a conflicting node
+	BlocksUntilConfirmable *big.Int
+	// InChallenge is true if the staker is currently being challenged
+	InChallenge bool
+}
+
+func (a InvalidStakeAlert) String() string {
+	return fmt.Sprintf(
+		"staker %v is staked on %v which is on an invalid branch starting at %v instead of %v, confirmable in %v blocks, in challenge: %v",
+		a.Staker,
+		a.StakerNode,
+		a.InvalidNode,
+		a.ValidNode,
+		a.BlocksUntilConfirmable,
+		a.InChallenge,
+	)
+}
+
+func (a InvalidStakeAlert) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Staker                 string `json:"staker"`
+		StakerNode             string `json:"stakerNode"`
+		InvalidNode            string `json:"invalidNode"`
+		ValidNode              string `json:"validNode"`
+		BlocksUntilConfirmable string `json:"blocksUntilConfirmable"`
+		InChallenge            bool   `json:"inChallenge"`
+	}{
+		Staker:                 a.Staker.Hex(),
+		StakerNode:             a.StakerNode.String(),
+		InvalidNode:            a.InvalidNode.String(),
+		ValidNode:              a.ValidNode.String(),
+		BlocksUntilConfirmable: a.BlocksUntilConfirmable.String(),
+		InChallenge:            a.InChallenge,
+	})
+}
+
+// WatchtowerListener checks the stakers of a chain against the chain's
+// calculated valid node without taking any action on L1. Each staker found on
+// an invalid branch is reported once per node it is staked on by logging it,
+// counting it in the watchtower metrics and, if WebhookURL is set, posting
+// it there as JSON
+type WatchtowerListener struct {
+	WebhookURL string
+
+	sync.Mutex
+	alerted map[common.Address]common.Hash
+}
+
+func NewWatchtowerListener(webhookURL string) *WatchtowerListener {
+	return &WatchtowerListener{
+		WebhookURL: webhookURL,
+		alerted:    make(map[common.Address]common.Hash),
+	}
+}
+
+// invalidStakers returns an alert for each staker that is staked on a node
+// which conflicts with the chain's calculated valid node. Stakers ahead of the
+// calculated valid node on the same branch haven't been judged yet and aren't
+// included. It must be called with the chain locked
+func invalidStakers(chain *ChainObserver) []InvalidStakeAlert {
+	validNode := chain.calculatedValidNode
+	currentTicks := common.TicksFromBlockNum(chain.deadlineHeight())
+	var alerts []InvalidStakeAlert
+	chain.nodeGraph.stakers.forall(func(staker *Staker) {
+		invalidNode, validAncestor, err := GetConflictAncestor(staker.location, validNode)
+		if err != nil {
+			// No conflict with the valid branch
+			return
+		}
+		blocksLeft := new(big.Int).Sub(invalidNode.deadline.Val, currentTicks.Val)
+		blocksLeft.Div(blocksLeft, big.NewInt(common.TicksPerBlock))
+		if blocksLeft.Sign() < 0 {
+			blocksLeft.SetInt64(0)
+		}
+		alerts = append(alerts, InvalidStakeAlert{
+			Staker:                 staker.address,
+			StakerNode:             staker.location.hash,
+			InvalidNode:            invalidNode.hash,
+			ValidNode:              validAncestor.hash,
+			BlocksUntilConfirmable: blocksLeft,
+			InChallenge:            !staker.challenge.IsZero(),
+		})
+	})
+	return alerts
+}
+
+func (wl *WatchtowerListener) checkStakers(ctx context.Context, chain *ChainObserver) {
+	alerts := invalidStakers(chain)
+	invalidStakersGauge.Update(int64(len(alerts)))
+
+	wl.Lock()
+	defer wl.Unlock()
+	for _, alert := range alerts {
+		if prevNode, ok := wl.alerted[alert.Staker]; ok && prevNode == alert.StakerNode {
+			continue
+		}
+		wl.alerted[alert.Staker] = alert.StakerNode
+		invalidStakeAlertsCounter.Inc(1)
+		log.Println("Watchtower alert:", alert)
+		if wl.WebhookURL != "" {
+			go wl.postAlert(ctx, alert)
+		}
+	}
+}
+
+func (wl *WatchtowerListener) postAlert(ctx context.Context, alert InvalidStakeAlert) {
+	data, err := json.Marshal(alert)
+	if err != nil {
+		log.Println("Failed to encode watchtower alert", err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, webhookTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, wl.WebhookURL, bytes.NewReader(data))
+	if err != nil {
+		log.Println("Failed to create watchtower webhook request", err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		log.Println("Failed to send watchtower alert", err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		log.Println("Watchtower webhook returned status", resp.Status)
+	}
+}
+
+func (wl *WatchtowerListener) StakeCreated(ctx context.Context, chain *ChainObserver, _ arbbridge.StakeCreatedEvent) {
+	wl.checkStakers(ctx, chain)
+}
+
+func (wl *WatchtowerListener) StakeRemoved(ctx context.Context, chain *ChainObserver, ev arbbridge.StakeRefundedEvent) {
+	wl.Lock()
+	delete(wl.alerted, ev.Staker)
+	wl.Unlock()
+	wl.checkStakers(ctx, chain)
+}
+
+func (wl *WatchtowerListener) StakeMoved(ctx context.Context, chain *ChainObserver, _ arbbridge.StakeMovedEvent) {
+	wl.checkStakers(ctx, chain)
+}
+
+func (wl *WatchtowerListener) StartedChain(context.Context, *ChainObserver) {}
+
+func (wl *WatchtowerListener) StartedChallenge(context.Context, *ChainObserver, *Challenge) {}
+
+func (wl *WatchtowerListener) ResumedChallenge(context.Context, *ChainObserver, *Challenge) {}
+
+func (wl *WatchtowerListener) CompletedChallenge(ctx context.Context, chain *ChainObserver, _ arbbridge.ChallengeCompletedEvent) {
+	wl.checkStakers(ctx, chain)
+}
+
+func (wl *WatchtowerListener) SawAssertion(ctx context.Context, chain *ChainObserver, _ arbbridge.AssertedEvent) {
+	wl.checkStakers(ctx, chain)
+}
+
+func (wl *WatchtowerListener) ConfirmedNode(context.Context, *ChainObserver, arbbridge.ConfirmedEvent) {
+}
+
+func (wl *WatchtowerListener) PrunedLeaf(context.Context, *ChainObserver, arbbridge.PrunedEvent) {}
+
+func (wl *WatchtowerListener) MessageDelivered(context.Context, *ChainObserver, arbbridge.MessageDeliveredEvent) {
+}
+
+func (wl *WatchtowerListener) AssertionPrepared(context.Context, *ChainObserver, *preparedAssertion) {
+}
+
+func (wl *WatchtowerListener) ConfirmableNodes(context.Context, *ChainObserver, *valprotocol.ConfirmOpportunity) {
+}
+
+func (wl *WatchtowerListener) PrunableLeafs(context.Context, *ChainObserver, []valprotocol.PruneParams) {
+}
+
+func (wl *WatchtowerListener) MootableStakes(context.Context, *ChainObserver, []recoverStakeMootedParams) {
+}
+
+func (wl *WatchtowerListener) OldStakes(context.Context, *ChainObserver, []recoverStakeOldParams) {}
+
+func (wl *WatchtowerListener) AdvancedCalculatedValidNode(ctx context.Context, chain *ChainObserver, _ common.Hash) {
+	// Called whenever an opinion is formed on a new assertion
+	wl.checkStakers(ctx, chain)
+}
+
+func (wl *WatchtowerListener) AdvancedKnownAssertion(context.Context, *ChainObserver, *protocol.ExecutionAssertion, common.Hash) {
+}
@@ -0,0 +1,117 @@
+/*
+* Copyright 2020, Offchain Labs, Inc.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*    http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package rollup
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/valprotocol"
+)
+
+func testWatchedChain() (*ChainObserver, *Node, *Node) {
+	deadline := common.TicksFromBlockNum(common.NewTimeBlocksInt(20))
+	root := &Node{hash: common.Hash{1}, deadline: deadline}
+	valid := &Node{prev: root, depth: 1, hash: common.Hash{2}, deadline: deadline, linkType: valprotocol.ValidChildType}
+	invalid := &Node{prev: root, depth: 1, hash: common.Hash{3}, deadline: deadline, linkType: valprotocol.InvalidExecutionChildType}
+	invalidChild := &Node{prev: invalid, depth: 2, hash: common.Hash{4}, deadline: deadline, linkType: valprotocol.ValidChildType}
+	ahead := &Node{prev: valid, depth: 2, hash: common.Hash{5}, deadline: deadline, linkType: valprotocol.ValidChildType}
+
+	stakers := NewStakerSet()
+	stakers.Add(&Staker{address: common.Address{1}, location: invalidChild})
+	stakers.Add(&Staker{address: common.Address{2}, location: valid})
+	stakers.Add(&Staker{address: common.Address{3}, location: ahead})
+	chain := &ChainObserver{
+		nodeGraph:           &StakedNodeGraph{stakers: stakers},
+		calculatedValidNode: valid,
+		latestBlockId:       &common.BlockId{Height: common.NewTimeBlocksInt(5)},
+	}
+	return chain, valid, invalid
+}
+
+func TestInvalidStakers(t *testing.T) {
+	chain, valid, invalid := testWatchedChain()
+	alerts := invalidStakers(chain)
+	if len(alerts) != 1 {
+		t.Fatal("found", len(alerts), "invalid stakers instead of 1")
+	}
+	alert := alerts[0]
+	if alert.Staker != (common.Address{1}) {
+		t.Error("wrong staker reported", alert.Staker)
+	}
+	if alert.InvalidNode != invalid.hash || alert.ValidNode != valid.hash {
+		t.Error("wrong conflict reported", alert)
+	}
+	if alert.BlocksUntilConfirmable.Int64() != 15 {
+		t.Error("confirmable in", alert.BlocksUntilConfirmable, "blocks instead of 15")
+	}
+}
+
+func TestInvalidStakersUnconfirmedHead(t *testing.T) {
+	chain, _, _ := testWatchedChain()
+	// Deadlines are judged against the head even if it isn't processed yet
+	chain.unconfirmedBlockId = &common.BlockId{Height: common.NewTimeBlocksInt(8)}
+	alerts := invalidStakers(chain)
+	if len(alerts) != 1 || alerts[0].BlocksUntilConfirmable.Int64() != 12 {
+		t.Error("expected invalid staker confirmable in 12 blocks but got", alerts)
+	}
+
+	// A reorg can leave the head behind the processed blocks for a moment
+	chain.unconfirmedBlockId = &common.BlockId{Height: common.NewTimeBlocksInt(3)}
+	alerts = invalidStakers(chain)
+	if len(alerts) != 1 || alerts[0].BlocksUntilConfirmable.Int64() != 15 {
+		t.Error("expected invalid staker confirmable in 15 blocks but got", alerts)
+	}
+}
+
+func TestWatchtowerWebhook(t *testing.T) {
+	received := make(chan map[string]interface{}, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error(err)
+		}
+		received <- body
+	}))
+	defer server.Close()
+
+	chain, _, _ := testWatchedChain()
+	listener := NewWatchtowerListener(server.URL)
+	listener.checkStakers(context.Background(), chain)
+	// The same stake is only reported once
+	listener.checkStakers(context.Background(), chain)
+
+	select {
+	case body := <-received:
+		if body["staker"] != (common.Address{1}).Hex() {
+			t.Error("webhook received alert for", body["staker"])
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook didn't receive alert")
+	}
+	// Alerts are posted asynchronously, so give a duplicate time to arrive
+	select {
+	case body := <-received:
+		t.Error("stake was reported more than once", body)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
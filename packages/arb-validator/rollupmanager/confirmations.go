@@ -0,0 +1,117 @@
+/*
+* Copyright 2020, Offchain Labs, Inc.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*    http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package rollupmanager
+
+import (
+	"context"
+	"log"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/arbbridge"
+)
+
+// confirmedHeaders relays the headers of the blocks after start once they
+// are depth blocks deep. The most recent depth headers are held back and
+// passed to onUnconfirmed as they arrive so that the caller can handle them
+// speculatively. If the subscription fails, for example because of a reorg,
+// the unconfirmed headers are discarded and the subscription is restarted
+// from the last confirmed block. Only if that fails without producing a new
+// header, which happens if the reorg was deeper than depth, is the error
+// passed on.
+//
+// Like SubscribeBlockHeaders, the first header sent is start itself
+func confirmedHeaders(
+	ctx context.Context,
+	client arbbridge.ArbClient,
+	start *common.BlockId,
+	depth uint64,
+	onUnconfirmed func(*common.BlockId),
+) (<-chan arbbridge.MaybeBlockId, error) {
+	headers, err := client.SubscribeBlockHeaders(ctx, start)
+	if err != nil {
+		return nil, err
+	}
+	confirmedChan := make(chan arbbridge.MaybeBlockId, 100)
+	go func() {
+		defer close(confirmedChan)
+
+		send := func(maybeBlockId arbbridge.MaybeBlockId) bool {
+			select {
+			case confirmedChan <- maybeBlockId:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		// The start block is already part of the caller's state
+		first, ok := <-headers
+		if !ok || !send(first) || first.Err != nil {
+			return
+		}
+
+		lastConfirmed := start
+		var unconfirmed []arbbridge.MaybeBlockId
+		receivedSinceRestart := false
+		for {
+			maybeBlockId, ok := <-headers
+			if ok && maybeBlockId.Err == nil {
+				receivedSinceRestart = true
+				onUnconfirmed(maybeBlockId.BlockId)
+				unconfirmed = append(unconfirmed, maybeBlockId)
+				for uint64(len(unconfirmed)) > depth {
+					if !send(unconfirmed[0]) {
+						return
+					}
+					lastConfirmed = unconfirmed[0].BlockId
+					unconfirmed = unconfirmed[1:]
+				}
+				continue
+			}
+			if !ok || ctx.Err() != nil {
+				return
+			}
+			if !receivedSinceRestart {
+				// Even the confirmed blocks are no longer valid
+				send(maybeBlockId)
+				return
+			}
+
+			if maybeBlockId.Err == arbbridge.ReorgError {
+				reorgsCounter.Inc(1)
+			}
+			log.Println("Discarding", len(unconfirmed), "unconfirmed blocks after error", maybeBlockId.Err)
+			unconfirmed = nil
+			receivedSinceRestart = false
+			headers, err = client.SubscribeBlockHeaders(ctx, lastConfirmed)
+			if err != nil {
+				send(arbbridge.MaybeBlockId{Err: err})
+				return
+			}
+			// Skip lastConfirmed, which has already been sent
+			first, ok := <-headers
+			if !ok {
+				return
+			}
+			if first.Err != nil {
+				send(first)
+				return
+			}
+		}
+	}()
+	return confirmedChan, nil
+}
@@ -0,0 +1,132 @@
+/*
+* Copyright 2020, Offchain Labs, Inc.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*    http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package rollupmanager
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/arbbridge"
+)
+
+var errTestReorg = errors.New("reorg")
+
+func testBlockId(height int64, fork byte) *common.BlockId {
+	return &common.BlockId{
+		Height:     common.NewTimeBlocks(big.NewInt(height)),
+		HeaderHash: common.Hash{byte(height), fork},
+	}
+}
+
+// testHeaderClient serves one scripted header subscription per call to
+// SubscribeBlockHeaders
+type testHeaderClient struct {
+	arbbridge.ArbClient
+	subscriptions [][]arbbridge.MaybeBlockId
+	starts        []*common.BlockId
+}
+
+func (c *testHeaderClient) SubscribeBlockHeaders(_ context.Context, start *common.BlockId) (<-chan arbbridge.MaybeBlockId, error) {
+	c.starts = append(c.starts, start)
+	headers := c.subscriptions[0]
+	c.subscriptions = c.subscriptions[1:]
+	ch := make(chan arbbridge.MaybeBlockId, len(headers)+1)
+	ch <- arbbridge.MaybeBlockId{BlockId: start}
+	for _, header := range headers {
+		ch <- header
+	}
+	close(ch)
+	return ch, nil
+}
+
+func blocks(fork byte, heights ...int64) []arbbridge.MaybeBlockId {
+	var ret []arbbridge.MaybeBlockId
+	for _, height := range heights {
+		ret = append(ret, arbbridge.MaybeBlockId{BlockId: testBlockId(height, fork)})
+	}
+	return ret
+}
+
+func TestConfirmedHeadersShallowReorg(t *testing.T) {
+	client := &testHeaderClient{
+		subscriptions: [][]arbbridge.MaybeBlockId{
+			append(blocks(0, 1, 2, 3, 4), arbbridge.MaybeBlockId{Err: errTestReorg}),
+			// Blocks 3 and 4 were replaced
+			blocks(1, 3, 4, 5),
+		},
+	}
+	var latest *common.BlockId
+	headers, err := confirmedHeaders(context.Background(), client, testBlockId(0, 0), 2, func(blockId *common.BlockId) {
+		latest = blockId
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var received []*common.BlockId
+	for maybeBlockId := range headers {
+		if maybeBlockId.Err != nil {
+			t.Fatal(maybeBlockId.Err)
+		}
+		received = append(received, maybeBlockId.BlockId)
+	}
+
+	expected := []*common.BlockId{
+		testBlockId(0, 0),
+		testBlockId(1, 0),
+		testBlockId(2, 0),
+		testBlockId(3, 1),
+	}
+	if len(received) != len(expected) {
+		t.Fatal("received", len(received), "blocks instead of", len(expected))
+	}
+	for i := range expected {
+		if !received[i].Equals(expected[i]) {
+			t.Error("received block", received[i], "instead of", expected[i])
+		}
+	}
+	if !client.starts[1].Equals(testBlockId(2, 0)) {
+		t.Error("resubscribed from", client.starts[1], "instead of last confirmed block")
+	}
+	if !latest.Equals(testBlockId(5, 1)) {
+		t.Error("latest unconfirmed block was", latest)
+	}
+}
+
+func TestConfirmedHeadersDeepReorg(t *testing.T) {
+	client := &testHeaderClient{
+		subscriptions: [][]arbbridge.MaybeBlockId{
+			append(blocks(0, 1, 2, 3), arbbridge.MaybeBlockId{Err: errTestReorg}),
+			// The confirmed block 1 was also replaced
+			{{Err: errTestReorg}},
+		},
+	}
+	headers, err := confirmedHeaders(context.Background(), client, testBlockId(0, 0), 2, func(*common.BlockId) {})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var lastErr error
+	for maybeBlockId := range headers {
+		lastErr = maybeBlockId.Err
+	}
+	if lastErr != errTestReorg {
+		t.Error("expected deep reorg to be reported but got", lastErr)
+	}
+}
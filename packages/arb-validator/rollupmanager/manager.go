@@ -18,17 +18,22 @@ package rollupmanager
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
 	"math/big"
 	"sync"
 	"time"
 
+	errors2 "github.com/pkg/errors"
 	"google.golang.org/protobuf/proto"
 
 	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/machine"
 	"github.com/offchainlabs/arbitrum/packages/arb-util/protocol"
 	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/arbbridge"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/metrics"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator/checkpointing"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator/rollup"
 )
@@ -37,6 +42,11 @@ const (
 	ValidEthBridgeVersion = "2"
 )
 
+var (
+	blocksCounter = metrics.NewCounter("arbitrum/rollup/blocks")
+	reorgsCounter = metrics.NewCounter("arbitrum/rollup/reorgs")
+)
+
 type Manager struct {
 	sync.Mutex
 	RollupAddress common.Address
@@ -46,8 +56,48 @@ type Manager struct {
 	listenerAddChan chan rollup.ChainListener
 	actionChan      chan func(*rollup.ChainObserver)
 	ckpFac          checkpointing.RollupCheckpointerFactory
+
+	cancelFunc        context.CancelFunc
+	errChan           chan error
+	done              chan struct{}
+	confirmationDepth uint64
+
+	// Only accessed by the manager's goroutine. The lowest block searched
+	// for the rollup's creation, which is moved up to the creation block
+	// once it has been found so that restarts don't search again
+	creationSearchStart *common.TimeBlocks
+
+	// Protected by the Mutex and updated by the manager's goroutine
+	checkpointer       checkpointing.RollupCheckpointer
+	currentBlock       *common.BlockId
+	latestBlock        *common.BlockId
+	atHead             bool
+	lastAssertionBlock *common.BlockId
+	callProfile        *machine.Profile
+}
+
+// Status is a snapshot of the manager's progress through the chain
+type Status struct {
+	// CurrentBlock is the latest block processed by the manager, or nil if
+	// the manager has not started processing blocks yet
+	CurrentBlock *common.BlockId
+	// LatestBlock is the latest block seen by the manager. When the manager
+	// has a confirmation depth, this block may not have been processed yet
+	// since it isn't deep enough
+	LatestBlock *common.BlockId
+	// AtHead is true once the manager has caught up to the head of the
+	// L1 chain
+	AtHead bool
+	// LastAssertionBlock is the block containing the most recent assertion
+	// that the manager has seen, or nil if it hasn't seen any
+	LastAssertionBlock *common.BlockId
+	// LastCheckpointHeight is the height of the most recently written
+	// checkpoint, or nil if nothing has been checkpointed
+	LastCheckpointHeight *common.TimeBlocks
 }
 
+var ErrManagerStopped = errors.New("rollup manager stopped")
+
 const defaultMaxReorgDepth = 100
 
 func CreateManager(
@@ -55,6 +105,8 @@ func CreateManager(
 	clnt arbbridge.ArbClient,
 	aoFilePath string,
 	dbPath string,
+	confirmationDepth uint64,
+	creationSearchStart *common.TimeBlocks,
 ) (*Manager, error) {
 	return CreateManagerAdvanced(
 		context.Background(),
@@ -68,170 +120,335 @@ func CreateManager(
 			big.NewInt(defaultMaxReorgDepth),
 			false,
 		),
+		confirmationDepth,
+		creationSearchStart,
 	)
 }
 
+// CreateManagerAdvanced launches a manager which follows the rollup chain
+// until ctx is cancelled or Stop is called. Errors which prevent the manager
+// from continuing are delivered through Errors rather than terminating the
+// process.
+//
+// If confirmationDepth is greater than zero, the events in a block are only
+// processed once it is confirmationDepth blocks deep, so stakes and
+// assertions are never based on events that are removed by a reorg
+// shallower than that. Such reorgs are absorbed without restarting the
+// manager. The most recent confirmationDepth blocks are handled
+// speculatively: the chain judges node deadlines against the newest block,
+// so it confirms nodes as soon as their deadlines pass on L1 instead of
+// confirmationDepth blocks later. A reorg deeper than confirmationDepth
+// still restarts the manager from its latest checkpoint.
+//
+// The rollup's creation is searched for from creationSearchStart onwards, or
+// from the genesis block if it is nil.
 func CreateManagerAdvanced(
 	ctx context.Context,
 	rollupAddr common.Address,
 	updateOpinion bool,
 	clnt arbbridge.ArbClient,
 	ckpFac checkpointing.RollupCheckpointerFactory,
+	confirmationDepth uint64,
+	creationSearchStart *common.TimeBlocks,
 ) (*Manager, error) {
+	ctx, cancelFunc := context.WithCancel(ctx)
 	man := &Manager{
-		RollupAddress:   rollupAddr,
-		client:          clnt,
-		listenerAddChan: make(chan rollup.ChainListener, 10),
-		actionChan:      make(chan func(*rollup.ChainObserver), 10),
-		ckpFac:          ckpFac,
+		RollupAddress:       rollupAddr,
+		client:              clnt,
+		listenerAddChan:     make(chan rollup.ChainListener, 10),
+		actionChan:          make(chan func(*rollup.ChainObserver), 10),
+		ckpFac:              ckpFac,
+		cancelFunc:          cancelFunc,
+		errChan:             make(chan error, 1),
+		done:                make(chan struct{}),
+		confirmationDepth:   confirmationDepth,
+		creationSearchStart: creationSearchStart,
 	}
 	go func() {
+		defer close(man.errChan)
+		defer close(man.done)
 		for {
-			runCtx, cancelFunc := context.WithCancel(ctx)
+			err := man.run(ctx, updateOpinion)
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				log.Println("Rollup manager stopping with error", err)
+				man.errChan <- err
+				return
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(10 * time.Second): // give time for things to settle, post-reorg, before restarting stuff
+			}
+		}
+	}()
 
-			checkpointer := man.ckpFac.New(runCtx)
+	return man, nil
+}
 
-			var chain *rollup.ChainObserver
+// run follows the chain until it hits a reorg or other recoverable
+// problem, in which case it returns nil so that the chain can be rebuilt. It
+// returns an error if the manager cannot continue.
+func (man *Manager) run(ctx context.Context, updateOpinion bool) error {
+	runCtx, cancelFunc := context.WithCancel(ctx)
+	defer cancelFunc()
 
-			watcher, err := clnt.NewRollupWatcher(rollupAddr)
-			if err != nil {
-				log.Fatal(err)
-			}
+	checkpointer := man.ckpFac.New(runCtx)
+	man.Lock()
+	man.checkpointer = checkpointer
+	man.atHead = false
+	man.Unlock()
 
-			ethbridgeVersion, err := watcher.GetVersion(runCtx)
-			if err != nil {
-				log.Fatal(err)
-			}
+	watcher, chain, err := man.startChain(runCtx, checkpointer, updateOpinion)
+	if err != nil {
+		return err
+	}
 
-			if ethbridgeVersion != ValidEthBridgeVersion {
-				log.Fatalf("VM has EthBridge version %v, but validator implements version %v."+
-					" To find a validator version which supports your EthBridge, visit "+
-					"https://offchainlabs.com/ethbridge-version-support",
-					ethbridgeVersion, ValidEthBridgeVersion)
-			}
+	log.Println("Starting validator from", chain.CurrentBlockId())
 
-			blockId, initialVMHash, err := watcher.GetCreationInfo(runCtx)
-			if err != nil {
-				log.Fatal(err)
-			}
+	man.Lock()
+	// Clear pending listeners
+	for len(man.listenerAddChan) > 0 {
+		<-man.listenerAddChan
+	}
+	// Add manager's listeners
+	for _, listener := range man.listeners {
+		chain.AddListener(listener)
+	}
+	man.currentBlock = chain.CurrentBlockId()
+	man.Unlock()
 
-			initialMachine, err := checkpointer.GetInitialMachine()
-			if err != nil {
-				log.Fatal(err)
-			}
+	chain.Start(runCtx)
 
-			if initialMachine.Hash() != initialVMHash {
-				log.Fatal("ArbChain was initialized with different VM")
-			}
+	current, err := man.client.CurrentBlockId(runCtx)
+	if err != nil {
+		return err
+	}
+	// The head of the chain that the manager can reach without processing
+	// unconfirmed blocks
+	headHeight := new(big.Int).Sub(current.Height.AsInt(), new(big.Int).SetUint64(man.confirmationDepth))
+
+	var headersChan <-chan arbbridge.MaybeBlockId
+	if man.confirmationDepth > 0 {
+		headersChan, err = confirmedHeaders(
+			runCtx,
+			man.client,
+			chain.CurrentBlockId(),
+			man.confirmationDepth,
+			func(blockId *common.BlockId) {
+				chain.NotifyUnconfirmedBlock(blockId)
+				man.Lock()
+				man.latestBlock = blockId
+				man.Unlock()
+			},
+		)
+	} else {
+		headersChan, err = man.client.SubscribeBlockHeaders(runCtx, chain.CurrentBlockId())
+	}
+	if err != nil {
+		blockId, err2 := man.client.BlockIdForHeight(runCtx, common.NewTimeBlocks(big.NewInt(0)))
+		if err2 != nil {
+			return err2
+		}
+		log.Println("Error subscribing to block headers", chain.CurrentBlockId().HeaderHash, chain.CurrentBlockId().Height.AsInt(), blockId.HeaderHash, blockId.Height.AsInt(), err)
 
-			if checkpointer.HasCheckpointedState() {
-				err := checkpointer.RestoreLatestState(runCtx, clnt, func(chainObserverBytes []byte, restoreCtx checkpointing.RestoreContext) error {
-					chainObserverBuf := &rollup.ChainObserverBuf{}
-					if err := proto.Unmarshal(chainObserverBytes, chainObserverBuf); err != nil {
-						log.Fatal(err)
-					}
-					var err error
-					chain, err = chainObserverBuf.UnmarshalFromCheckpoint(runCtx, restoreCtx, checkpointer)
-					return err
-				})
-				if err != nil {
-					log.Fatal(err)
-				}
-			} else {
-				params, err := watcher.GetParams(ctx)
-				if err != nil {
-					log.Fatal(err)
-				}
-				chain, err = rollup.NewChain(rollupAddr, checkpointer, params, updateOpinion, blockId)
-				if err != nil {
-					log.Fatal(err)
+		cancelFunc()
+		select {
+		case <-ctx.Done():
+		case <-time.After(2 * time.Second):
+		}
+		return nil
+	}
+	reachedHead := false
+runLoop:
+	for {
+		select {
+		case <-runCtx.Done():
+			break runLoop
+		case maybeBlockId, ok := <-headersChan:
+			if !ok {
+				log.Println("Manager stopped receiving headers")
+				break runLoop
+			}
+			if maybeBlockId.Err != nil {
+				if maybeBlockId.Err == arbbridge.ReorgError {
+					reorgsCounter.Inc(1)
 				}
+				log.Println("Error getting new header", maybeBlockId.Err)
+				break runLoop
 			}
 
-			log.Println("Starting validator from", chain.CurrentBlockId())
+			blockId := maybeBlockId.BlockId
+			timestamp := maybeBlockId.Timestamp
 
-			man.Lock()
-			// Clear pending listeners
-			for len(man.listenerAddChan) > 0 {
-				<-man.listenerAddChan
-			}
-			// Add manager's listeners
-			for _, listener := range man.listeners {
-				chain.AddListener(listener)
+			if !reachedHead && blockId.Height.AsInt().Cmp(headHeight) >= 0 {
+				log.Println("Reached head")
+				reachedHead = true
+				chain.NowAtHead()
+				log.Println("Now at head")
 			}
-			man.Unlock()
 
-			chain.Start(runCtx)
+			chain.NotifyNewBlock(blockId.Clone())
+			log.Print(chain.DebugString("== "))
 
-			current, err := clnt.CurrentBlockId(runCtx)
+			events, err := watcher.GetEvents(runCtx, blockId, timestamp)
 			if err != nil {
-				log.Fatal(err)
+				log.Println("Manager hit error getting events", err)
+				break runLoop
 			}
-
-			headersChan, err := clnt.SubscribeBlockHeaders(runCtx, chain.CurrentBlockId())
-			if err != nil {
-				blockId, err := clnt.BlockIdForHeight(ctx, common.NewTimeBlocks(big.NewInt(0)))
-				if err != nil {
-					panic(err)
+			var lastAssertionBlock *common.BlockId
+			for _, event := range events {
+				chain.HandleNotification(runCtx, event)
+				if ev, ok := event.(arbbridge.AssertedEvent); ok {
+					lastAssertionBlock = ev.BlockId
 				}
-				log.Println("Error subscribing to block headers", chain.CurrentBlockId().HeaderHash, chain.CurrentBlockId().Height.AsInt(), blockId.HeaderHash, blockId.Height.AsInt(), err)
+			}
+
+			blocksCounter.Inc(1)
 
-				cancelFunc()
-				time.Sleep(2 * time.Second)
-				continue
+			man.Lock()
+			man.currentBlock = blockId
+			if man.confirmationDepth == 0 {
+				man.latestBlock = blockId
 			}
-			reachedHead := false
-		runLoop:
-			for {
-				select {
-				case maybeBlockId, ok := <-headersChan:
-					if !ok {
-						log.Println("Manager stopped receiving headers")
-						break runLoop
-					}
-					if maybeBlockId.Err != nil {
-						log.Println("Error getting new header", maybeBlockId.Err)
-						break runLoop
-					}
-
-					blockId := maybeBlockId.BlockId
-					timestamp := maybeBlockId.Timestamp
-
-					if !reachedHead && blockId.Height.Cmp(current.Height) >= 0 {
-						log.Println("Reached head")
-						reachedHead = true
-						chain.NowAtHead()
-						log.Println("Now at head")
-					}
-
-					chain.NotifyNewBlock(blockId.Clone())
-					log.Print(chain.DebugString("== "))
-
-					events, err := watcher.GetEvents(runCtx, blockId, timestamp)
-					if err != nil {
-						log.Println("Manager hit error getting events", err)
-						break runLoop
-					}
-					for _, event := range events {
-						chain.HandleNotification(runCtx, event)
-					}
-				case action := <-man.actionChan:
-					action(chain)
-				}
+			man.atHead = reachedHead
+			if lastAssertionBlock != nil {
+				man.lastAssertionBlock = lastAssertionBlock
 			}
+			man.Unlock()
+		case action := <-man.actionChan:
+			action(chain)
+		}
+	}
 
-			cancelFunc()
-
+	if ctx.Err() != nil {
+		// The manager is shutting down so run any actions that are still
+		// waiting rather than leaving their callers blocked
+		for {
 			select {
-			case <-ctx.Done():
-				return
+			case action := <-man.actionChan:
+				action(chain)
 			default:
-				time.Sleep(10 * time.Second) // give time for things to settle, post-reorg, before restarting stuff
+				return nil
 			}
 		}
-	}()
+	}
+	return nil
+}
 
-	return man, nil
+// startChain checks that the on-chain rollup matches this validator and
+// then either restores the chain from the latest checkpoint or creates it
+// fresh
+func (man *Manager) startChain(
+	ctx context.Context,
+	checkpointer checkpointing.RollupCheckpointer,
+	updateOpinion bool,
+) (arbbridge.ArbRollupWatcher, *rollup.ChainObserver, error) {
+	watcher, err := man.client.NewRollupWatcher(man.RollupAddress)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ethbridgeVersion, err := watcher.GetVersion(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if ethbridgeVersion != ValidEthBridgeVersion {
+		return nil, nil, fmt.Errorf("VM has EthBridge version %v, but validator implements version %v."+
+			" To find a validator version which supports your EthBridge, visit "+
+			"https://offchainlabs.com/ethbridge-version-support",
+			ethbridgeVersion, ValidEthBridgeVersion)
+	}
+
+	blockId, initialVMHash, err := watcher.GetCreationInfo(ctx, man.creationSearchStart)
+	if err != nil {
+		return nil, nil, err
+	}
+	if blockId != nil {
+		man.creationSearchStart = blockId.Height
+	}
+
+	initialMachine, err := checkpointer.GetInitialMachine()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if initialMachine.Hash() != initialVMHash {
+		return nil, nil, errors.New("ArbChain was initialized with different VM")
+	}
+
+	var chain *rollup.ChainObserver
+	if checkpointer.HasCheckpointedState() {
+		err := checkpointer.RestoreLatestState(ctx, man.client, func(chainObserverBytes []byte, restoreCtx checkpointing.RestoreContext) error {
+			chainObserverBuf := &rollup.ChainObserverBuf{}
+			if err := proto.Unmarshal(chainObserverBytes, chainObserverBuf); err != nil {
+				return err
+			}
+			var err error
+			chain, err = chainObserverBuf.UnmarshalFromCheckpoint(ctx, restoreCtx, checkpointer)
+			return err
+		})
+		if err != nil {
+			return nil, nil, errors2.Wrap(err, "failed to restore from checkpoint")
+		}
+	} else {
+		params, err := watcher.GetParams(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		chain, err = rollup.NewChain(man.RollupAddress, checkpointer, params, updateOpinion, blockId)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return watcher, chain, nil
+}
+
+// Errors returns a channel which receives the error that caused the manager
+// to stop. The channel is closed once the manager has stopped, so a nil
+// value means that it was stopped without error.
+func (man *Manager) Errors() <-chan error {
+	return man.errChan
+}
+
+// Stop shuts down the manager, running any queued actions and flushing the
+// most recent checkpoint to disk. It returns early with ctx's error if ctx
+// expires before the manager has finished shutting down.
+func (man *Manager) Stop(ctx context.Context) error {
+	man.cancelFunc()
+	select {
+	case <-man.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	man.Lock()
+	checkpointer := man.checkpointer
+	man.Unlock()
+	if checkpointer == nil {
+		return nil
+	}
+	return checkpointer.Flush()
+}
+
+// Status reports how far the manager has progressed through the chain
+func (man *Manager) Status() Status {
+	man.Lock()
+	status := Status{
+		CurrentBlock:       man.currentBlock,
+		LatestBlock:        man.latestBlock,
+		AtHead:             man.atHead,
+		LastAssertionBlock: man.lastAssertionBlock,
+	}
+	checkpointer := man.checkpointer
+	man.Unlock()
+	if checkpointer != nil {
+		status.LastCheckpointHeight = checkpointer.LatestCheckpointHeight()
+	}
+	return status
 }
 
 func (man *Manager) AddListener(listener rollup.ChainListener) {
@@ -241,38 +458,75 @@ func (man *Manager) AddListener(listener rollup.ChainListener) {
 	man.Unlock()
 }
 
-func (man *Manager) ExecuteCall(messages value.TupleValue, maxTime time.Duration) (*protocol.ExecutionAssertion, uint64) {
-	retChan := make(chan struct {
-		*protocol.ExecutionAssertion
-		uint64
-	}, 1)
-	man.actionChan <- func(chain *rollup.ChainObserver) {
-		mach := chain.LatestKnownValidMachine()
+// withChain runs f against the current chain on the manager's goroutine. It
+// returns ErrManagerStopped if the manager stops before f could be run.
+func (man *Manager) withChain(f func(*rollup.ChainObserver)) error {
+	finished := make(chan struct{})
+	action := func(chain *rollup.ChainObserver) {
+		f(chain)
+		close(finished)
+	}
+	select {
+	case man.actionChan <- action:
+	case <-man.done:
+		return ErrManagerStopped
+	}
+	select {
+	case <-finished:
+		return nil
+	case <-man.done:
+		// Queued actions are run before done is closed
+		select {
+		case <-finished:
+			return nil
+		default:
+			return ErrManagerStopped
+		}
+	}
+}
+
+// SetCallProfile records the cost of every instruction run by ExecuteCall
+// into profile, or stops recording if profile is nil
+func (man *Manager) SetCallProfile(profile *machine.Profile) {
+	man.Lock()
+	man.callProfile = profile
+	man.Unlock()
+}
+
+func (man *Manager) ExecuteCall(messages value.TupleValue, maxTime time.Duration) (*protocol.ExecutionAssertion, uint64, error) {
+	var mach machine.Machine
+	var timeBounds *protocol.TimeBounds
+	err := man.withChain(func(chain *rollup.ChainObserver) {
+		mach = chain.LatestKnownValidMachine()
 		latestBlock := chain.CurrentBlockId().Height
 		latestTime := big.NewInt(time.Now().Unix())
-		timeBounds := &protocol.TimeBounds{latestBlock, latestBlock, latestTime, latestTime}
-		go func() {
-			assertion, numSteps := mach.ExecuteAssertion(
-				// Call execution is only limited by wall time, so use a massive max steps as an approximation to infinity
-				10000000000000000,
-				timeBounds,
-				messages,
-				maxTime,
-			)
-			retChan <- struct {
-				*protocol.ExecutionAssertion
-				uint64
-			}{assertion, numSteps}
-		}()
+		timeBounds = &protocol.TimeBounds{latestBlock, latestBlock, latestTime, latestTime}
+	})
+	if err != nil {
+		return nil, 0, err
 	}
-	ret := <-retChan
-	return ret.ExecutionAssertion, ret.uint64
+	man.Lock()
+	callProfile := man.callProfile
+	man.Unlock()
+	if callProfile != nil {
+		if profiled, ok := mach.(machine.ProfiledMachine); ok {
+			profiled.SetProfile(callProfile)
+		}
+	}
+	assertion, numSteps := mach.ExecuteAssertion(
+		// Call execution is only limited by wall time, so use a massive max steps as an approximation to infinity
+		10000000000000000,
+		timeBounds,
+		messages,
+		maxTime,
+	)
+	return assertion, numSteps, nil
 }
 
-func (man *Manager) CurrentBlockId() *common.BlockId {
-	retChan := make(chan *common.BlockId, 1)
-	man.actionChan <- func(chain *rollup.ChainObserver) {
-		retChan <- chain.CurrentBlockId()
-	}
-	return <-retChan
+func (man *Manager) CurrentBlockId() (*common.BlockId, error) {
+	var blockId *common.BlockId
+	err := man.withChain(func(chain *rollup.ChainObserver) {
+		blockId = chain.CurrentBlockId()
+	})
+	return blockId, err
 }
@@ -18,7 +18,6 @@ package rollupmanager
 
 import (
 	"context"
-	"errors"
 	"log"
 	"time"
 
@@ -35,8 +34,6 @@ func NewStressTestClient(client arbbridge.ArbClient, reorgInterval time.Duration
 	return &ArbClientStressTest{client, reorgInterval}
 }
 
-var reorgError = errors.New("reorg occured")
-
 func (st *ArbClientStressTest) SubscribeBlockHeaders(ctx context.Context, startBlockId *common.BlockId) (<-chan arbbridge.MaybeBlockId, error) {
 	rawHeadersChan, err := st.ArbClient.SubscribeBlockHeaders(ctx, startBlockId)
 	if err != nil {
@@ -59,7 +56,7 @@ func (st *ArbClientStressTest) SubscribeBlockHeaders(ctx context.Context, startB
 
 			case <-ticker.C:
 				log.Println("Manually triggering reorg")
-				headerChan <- arbbridge.MaybeBlockId{Err: reorgError}
+				headerChan <- arbbridge.MaybeBlockId{Err: arbbridge.ReorgError}
 				return
 			}
 		}
@@ -107,3 +107,11 @@ func (e evilRollupCheckpointer) GetInitialMachine() (machine.Machine, error) {
 func (e evilRollupCheckpointer) AsyncSaveCheckpoint(blockId *common.BlockId, contents []byte, cpCtx *checkpointing.CheckpointContext) {
 	e.cp.AsyncSaveCheckpoint(blockId, contents, cpCtx)
 }
+
+func (e evilRollupCheckpointer) Flush() error {
+	return e.cp.Flush()
+}
+
+func (e evilRollupCheckpointer) LatestCheckpointHeight() *common.TimeBlocks {
+	return e.cp.LatestCheckpointHeight()
+}
@@ -0,0 +1,328 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rollupvalidator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth/filters"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/evm"
+)
+
+// ChainId is the chain id reported to standard Ethereum clients. It matches
+// the id used by arb-provider-ethers
+const ChainId = 123456789
+
+var arbSysAddress = common.HexToAddress("0x0000000000000000000000000000000000000064")
+var arbInfoAddress = common.HexToAddress("0x0000000000000000000000000000000000000065")
+
+const arbSysABI = `[{"constant":true,"inputs":[{"internalType":"address","name":"account","type":"address"}],"name":"getTransactionCount","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"payable":false,"stateMutability":"view","type":"function"}]`
+const arbInfoABI = `[{"constant":true,"inputs":[{"internalType":"address","name":"account","type":"address"}],"name":"getBalance","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[{"internalType":"address","name":"account","type":"address"}],"name":"getCode","outputs":[{"internalType":"bytes","name":"o_code","type":"bytes"}],"payable":false,"stateMutability":"view","type":"function"}]`
+
+var arbSys abi.ABI
+var arbInfo abi.ABI
+
+func init() {
+	parsedSys, err := abi.JSON(strings.NewReader(arbSysABI))
+	if err != nil {
+		panic(err)
+	}
+	parsedInfo, err := abi.JSON(strings.NewReader(arbInfoABI))
+	if err != nil {
+		panic(err)
+	}
+	arbSys = parsedSys
+	arbInfo = parsedInfo
+}
+
+// CallArgs represents the arguments for an eth_call request
+type CallArgs struct {
+	From     *ethcommon.Address `json:"from"`
+	To       *ethcommon.Address `json:"to"`
+	Gas      *hexutil.Uint64    `json:"gas"`
+	GasPrice *hexutil.Big       `json:"gasPrice"`
+	Value    *hexutil.Big       `json:"value"`
+	Data     *hexutil.Bytes     `json:"data"`
+}
+
+// EthServer exposes the validator through the standard Ethereum JSON-RPC
+// interface so that unmodified Ethereum clients can query the chain. Each
+// finalized assertion is presented as a block
+type EthServer struct {
+	server *Server
+}
+
+// NewEthServer returns a new instance of the EthServer class
+func NewEthServer(server *Server) *EthServer {
+	return &EthServer{server: server}
+}
+
+// ChainId returns the chain id of the Arbitrum chain
+func (s *EthServer) ChainId() *hexutil.Big {
+	return (*hexutil.Big)(big.NewInt(ChainId))
+}
+
+// BlockNumber returns the index of the latest finalized assertion
+func (s *EthServer) BlockNumber() hexutil.Uint64 {
+	count := <-s.server.tracker.AssertionCount()
+	if count < 0 {
+		return 0
+	}
+	return hexutil.Uint64(count)
+}
+
+// Call executes the given call against the latest known valid machine and
+// returns the data returned by the contract
+func (s *EthServer) Call(ctx context.Context, args CallArgs, blockNr *rpc.BlockNumberOrHash) (hexutil.Bytes, error) {
+	if args.To == nil {
+		return nil, errors.New("call must have a destination address")
+	}
+	var from common.Address
+	if args.From != nil {
+		from = common.NewAddressFromEth(*args.From)
+	}
+	var data []byte
+	if args.Data != nil {
+		data = *args.Data
+	}
+	return s.call(common.NewAddressFromEth(*args.To), from, data)
+}
+
+// GetBalance returns the balance of the given account
+func (s *EthServer) GetBalance(ctx context.Context, address ethcommon.Address, blockNr *rpc.BlockNumberOrHash) (*hexutil.Big, error) {
+	var balance *big.Int
+	if err := s.callABI(arbInfo, arbInfoAddress, &balance, "getBalance", address); err != nil {
+		return nil, err
+	}
+	return (*hexutil.Big)(balance), nil
+}
+
+// GetTransactionCount returns the number of transactions the given account
+// has sent
+func (s *EthServer) GetTransactionCount(ctx context.Context, address ethcommon.Address, blockNr *rpc.BlockNumberOrHash) (*hexutil.Uint64, error) {
+	var count *big.Int
+	if err := s.callABI(arbSys, arbSysAddress, &count, "getTransactionCount", address); err != nil {
+		return nil, err
+	}
+	nonce := hexutil.Uint64(count.Uint64())
+	return &nonce, nil
+}
+
+// GetCode returns the code stored at the given address
+func (s *EthServer) GetCode(ctx context.Context, address ethcommon.Address, blockNr *rpc.BlockNumberOrHash) (hexutil.Bytes, error) {
+	var code []byte
+	if err := s.callABI(arbInfo, arbInfoAddress, &code, "getCode", address); err != nil {
+		return nil, err
+	}
+	return code, nil
+}
+
+// RPCLog is a log in the format used by the Ethereum JSON-RPC interface.
+// Assertions have no block hash, so that field is always null. The
+// transaction index is the position of the transaction's result in the
+// assertion and the log index counts the logs of the whole assertion
+type RPCLog struct {
+	Address     ethcommon.Address `json:"address"`
+	Topics      []ethcommon.Hash  `json:"topics"`
+	Data        hexutil.Bytes     `json:"data"`
+	BlockNumber hexutil.Uint64    `json:"blockNumber"`
+	TxHash      ethcommon.Hash    `json:"transactionHash"`
+	TxIndex     hexutil.Uint      `json:"transactionIndex"`
+	BlockHash   *ethcommon.Hash   `json:"blockHash"`
+	Index       hexutil.Uint      `json:"logIndex"`
+	Removed     bool              `json:"removed"`
+}
+
+// RPCReceipt is a transaction receipt in the format used by the Ethereum
+// JSON-RPC interface. The validator doesn't track the block hash or the gas
+// usage of transactions, so the block hash is null and the gas used is zero
+type RPCReceipt struct {
+	TxHash            ethcommon.Hash     `json:"transactionHash"`
+	TransactionIndex  hexutil.Uint       `json:"transactionIndex"`
+	BlockHash         *ethcommon.Hash    `json:"blockHash"`
+	BlockNumber       *hexutil.Big       `json:"blockNumber"`
+	CumulativeGasUsed hexutil.Uint64     `json:"cumulativeGasUsed"`
+	GasUsed           hexutil.Uint64     `json:"gasUsed"`
+	ContractAddress   *ethcommon.Address `json:"contractAddress"`
+	Logs              []*RPCLog          `json:"logs"`
+	Bloom             types.Bloom        `json:"logsBloom"`
+	Status            hexutil.Uint64     `json:"status"`
+}
+
+// GetLogs returns all logs matching the given filter criteria
+func (s *EthServer) GetLogs(ctx context.Context, crit filters.FilterCriteria) ([]*RPCLog, error) {
+	if crit.BlockHash != nil {
+		return nil, errors.New("filtering by block hash is not supported")
+	}
+	latest := int64(s.BlockNumber())
+	fromHeight := resolveBlockNumber(crit.FromBlock, latest)
+	toHeight := resolveBlockNumber(crit.ToBlock, latest)
+	addresses, topics := filterArgs(crit)
+
+	results := <-s.server.tracker.FindLogs(&fromHeight, &toHeight, addresses, topics)
+	logs := make([]*RPCLog, 0, len(results))
+	for _, result := range results {
+		logs = append(logs, result.EthLog())
+	}
+	return logs, nil
+}
+
+// GetTransactionReceipt returns the receipt of the transaction with the given
+// hash or nil if the transaction has not been included in a finalized
+// assertion
+func (s *EthServer) GetTransactionReceipt(ctx context.Context, txHash ethcommon.Hash) (*RPCReceipt, error) {
+	info := <-s.server.tracker.TxInfo(common.NewHashFromEth(txHash))
+	if !info.Found {
+		return nil, nil
+	}
+	return s.receipt(txHash, info)
+}
+
+func (s *EthServer) receipt(txHash ethcommon.Hash, info txInfo) (*RPCReceipt, error) {
+	processed, err := evm.ProcessLog(info.RawVal, s.server.rollupAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	status := types.ReceiptStatusFailed
+	var evmLogs []evm.Log
+	switch res := processed.(type) {
+	case evm.Return:
+		status = types.ReceiptStatusSuccessful
+		evmLogs = res.Logs
+	case evm.Stop:
+		status = types.ReceiptStatusSuccessful
+		evmLogs = res.Logs
+	}
+
+	logs := make([]*RPCLog, 0, len(evmLogs))
+	bloomLogs := make([]*types.Log, 0, len(evmLogs))
+	for i, evmLog := range evmLogs {
+		l := logResponse{
+			Log:            evmLog,
+			Msg:            processed.GetEthMsg(),
+			AssertionIndex: int64(info.assertionIndex),
+			TxIndex:        int64(info.txIndex),
+			LogIndex:       int64(info.firstLogIndex + i),
+		}.EthLog()
+		l.TxHash = txHash
+		logs = append(logs, l)
+		bloomLogs = append(bloomLogs, &types.Log{Address: l.Address, Topics: l.Topics})
+	}
+
+	return &RPCReceipt{
+		TxHash:           txHash,
+		TransactionIndex: hexutil.Uint(info.txIndex),
+		BlockNumber:      (*hexutil.Big)(big.NewInt(int64(info.assertionIndex))),
+		Logs:             logs,
+		Bloom:            types.CreateBloom(types.Receipts{{Logs: bloomLogs}}),
+		Status:           hexutil.Uint64(status),
+	}, nil
+}
+
+// EthLog converts the log into the format used by the Ethereum JSON-RPC
+// interface
+func (l logResponse) EthLog() *RPCLog {
+	addressBytes := l.Log.ContractID.ToBytes()
+	topics := make([]ethcommon.Hash, 0, len(l.Log.Topics))
+	for _, topic := range l.Log.Topics {
+		topics = append(topics, topic.ToEthHash())
+	}
+	return &RPCLog{
+		Address:     ethcommon.BytesToAddress(addressBytes[12:]),
+		Topics:      topics,
+		Data:        l.Log.Data,
+		BlockNumber: hexutil.Uint64(l.AssertionIndex),
+		TxHash:      l.Msg.TxHash.ToEthHash(),
+		TxIndex:     hexutil.Uint(l.TxIndex),
+		Index:       hexutil.Uint(l.LogIndex),
+	}
+}
+
+func filterArgs(crit filters.FilterCriteria) ([]*big.Int, [][]common.Hash) {
+	addresses := make([]*big.Int, 0, len(crit.Addresses))
+	for _, address := range crit.Addresses {
+		addresses = append(addresses, new(big.Int).SetBytes(address[:]))
+	}
+	topics := make([][]common.Hash, 0, len(crit.Topics))
+	for _, options := range crit.Topics {
+		topicOptions := make([]common.Hash, 0, len(options))
+		for _, topic := range options {
+			topicOptions = append(topicOptions, common.NewHashFromEth(topic))
+		}
+		topics = append(topics, topicOptions)
+	}
+	return addresses, topics
+}
+
+func resolveBlockNumber(num *big.Int, latest int64) int64 {
+	if num == nil || num.Sign() < 0 {
+		// Latest and pending both refer to the most recent assertion
+		return latest
+	}
+	return num.Int64()
+}
+
+func (s *EthServer) call(to common.Address, from common.Address, data []byte) ([]byte, error) {
+	result, err := s.server.executeCall(to, from, data)
+	if err != nil {
+		return nil, err
+	}
+	processed, err := evm.ProcessLog(result, s.server.rollupAddress)
+	if err != nil {
+		return nil, err
+	}
+	switch res := processed.(type) {
+	case evm.Return:
+		return res.ReturnVal, nil
+	case evm.Stop:
+		return []byte{}, nil
+	case evm.Revert:
+		return nil, fmt.Errorf("execution reverted: %v", string(res.ReturnVal))
+	default:
+		return nil, errors.New("call failed")
+	}
+}
+
+func (s *EthServer) callABI(
+	contractABI abi.ABI,
+	contract common.Address,
+	result interface{},
+	method string,
+	params ...interface{},
+) error {
+	input, err := contractABI.Pack(method, params...)
+	if err != nil {
+		return err
+	}
+	output, err := s.call(contract, common.Address{}, input)
+	if err != nil {
+		return err
+	}
+	return contractABI.Unpack(result, method, output)
+}
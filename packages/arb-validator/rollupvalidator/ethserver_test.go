@@ -0,0 +1,184 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rollupvalidator
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/protocol"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/evm"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/message"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator/rollup"
+)
+
+var testRollupAddress = common.HexToAddress("0x1000000000000000000000000000000000000001")
+var testContract = common.HexToAddress("0x2000000000000000000000000000000000000002")
+var testTopic = common.NewHashFromEth(ethcommon.HexToHash("0x03"))
+
+func newTestTracker(t *testing.T) (*txTracker, chan rollup.FinalizedAssertion, chan uint64) {
+	db, err := newTxDB("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tracker := newTxTracker(db, testRollupAddress)
+	assertions := make(chan rollup.FinalizedAssertion)
+	rollbacks := make(chan uint64)
+	go tracker.handleTxResults(assertions, rollbacks)
+	return tracker, assertions, rollbacks
+}
+
+func newTestEthClient(t *testing.T, tracker *txTracker) *rpc.Client {
+	server := rpc.NewServer()
+	ethServer := NewEthServer(&Server{rollupAddress: testRollupAddress, tracker: tracker})
+	if err := server.RegisterName("eth", ethServer); err != nil {
+		t.Fatal(err)
+	}
+	return rpc.DialInProc(server)
+}
+
+// returnResult builds the evm result of an eth transfer with the given
+// message number which returned successfully after emitting one log
+func returnResult(t *testing.T, messageNum int64) (value.Value, common.Hash) {
+	msg := message.DeliveredEth{
+		Eth: message.Eth{
+			To:    testContract,
+			From:  common.HexToAddress("0x4000000000000000000000000000000000000004"),
+			Value: big.NewInt(1),
+		},
+		BlockNum:   common.NewTimeBlocks(big.NewInt(1)),
+		Timestamp:  big.NewInt(1),
+		MessageNum: big.NewInt(messageNum),
+	}
+	logVal, err := value.NewTupleFromSlice([]value.Value{
+		value.NewIntValue(new(big.Int).SetBytes(testContract[:])),
+		message.BytesToByteStack([]byte{1, 2, 3}),
+		value.NewIntValue(new(big.Int).SetBytes(testTopic[:])),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := value.NewTupleFromSlice([]value.Value{
+		message.DeliveredValue(msg),
+		message.ListToStackValue([]value.Value{logVal}),
+		message.BytesToByteStack(nil),
+		value.NewInt64Value(evm.ReturnCode),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result, msg.ReceiptHash()
+}
+
+func finalizedAssertion(depth uint64, logs ...value.Value) rollup.FinalizedAssertion {
+	return rollup.FinalizedAssertion{
+		Assertion: &protocol.ExecutionAssertion{Logs: logs},
+		NodeHash:  common.Hash{byte(depth)},
+		NodeDepth: depth,
+	}
+}
+
+func TestEthServerRoundTrip(t *testing.T) {
+	tracker, assertions, _ := newTestTracker(t)
+	rpcClient := newTestEthClient(t, tracker)
+	defer rpcClient.Close()
+	client := ethclient.NewClient(rpcClient)
+	ctx := context.Background()
+
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chainID.Int64() != ChainId {
+		t.Error("wrong chain id", chainID)
+	}
+
+	result1, txHash1 := returnResult(t, 42)
+	result2, txHash2 := returnResult(t, 43)
+	if _, err := client.TransactionReceipt(ctx, txHash1.ToEthHash()); err != ethereum.NotFound {
+		t.Error("expected receipt of unknown tx to be not found but got", err)
+	}
+
+	assertions <- finalizedAssertion(1, result1, result2)
+
+	// ethclient doesn't have a method for eth_blockNumber in this version
+	var blockNumber hexutil.Uint64
+	if err := rpcClient.Call(&blockNumber, "eth_blockNumber"); err != nil {
+		t.Fatal(err)
+	}
+	if blockNumber != 0 {
+		t.Error("wrong block number", blockNumber)
+	}
+
+	receipt, err := client.TransactionReceipt(ctx, txHash2.ToEthHash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful || receipt.BlockNumber.Sign() != 0 {
+		t.Error("wrong receipt status or block", receipt.Status, receipt.BlockNumber)
+	}
+	if receipt.TxHash != txHash2.ToEthHash() || receipt.TransactionIndex != 1 {
+		t.Error("wrong receipt tx", receipt.TxHash, receipt.TransactionIndex)
+	}
+	if len(receipt.Logs) != 1 {
+		t.Fatal("receipt should have one log", receipt.Logs)
+	}
+	// Logs are numbered across the whole assertion
+	receiptLog := receipt.Logs[0]
+	if receiptLog.Index != 1 || receiptLog.TxIndex != 1 {
+		t.Error("wrong receipt log position", receiptLog.Index, receiptLog.TxIndex)
+	}
+
+	query := ethereum.FilterQuery{
+		FromBlock: big.NewInt(0),
+		Addresses: []ethcommon.Address{testContract.ToEthAddress()},
+		Topics:    [][]ethcommon.Hash{{testTopic.ToEthHash()}},
+	}
+	logs, err := client.FilterLogs(ctx, query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != 2 {
+		t.Fatal("expected two logs but got", logs)
+	}
+	if !bytes.Equal(logs[0].Data, []byte{1, 2, 3}) || logs[0].TxHash != txHash1.ToEthHash() {
+		t.Error("wrong log", logs[0])
+	}
+	if logs[1].Index != receiptLog.Index || logs[1].TxIndex != receiptLog.TxIndex || logs[1].TxHash != receiptLog.TxHash {
+		t.Error("log from filter", logs[1], "doesn't match receipt log", receiptLog)
+	}
+
+	query.Topics = [][]ethcommon.Hash{{common.Hash{}.ToEthHash()}}
+	logs, err = client.FilterLogs(ctx, query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != 0 {
+		t.Error("logs shouldn't match other topics", logs)
+	}
+}
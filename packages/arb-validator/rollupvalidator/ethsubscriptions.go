@@ -0,0 +1,163 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rollupvalidator
+
+import (
+	"context"
+	"log"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/eth/filters"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+)
+
+const subscriptionBufferSize = 10
+
+// AssertionHeader is sent to subscribers of newAssertions for each assertion
+// finalized by the validator
+type AssertionHeader struct {
+	Number        hexutil.Uint64 `json:"number"`
+	NodeHash      ethcommon.Hash `json:"nodeHash"`
+	OnChainTxHash ethcommon.Hash `json:"onChainTxHash"`
+	LogCount      hexutil.Uint64 `json:"logCount"`
+}
+
+// NewAssertions sends a notification each time an assertion is finalized
+func (s *EthServer) NewAssertions(ctx context.Context) (*rpc.Subscription, error) {
+	return s.subscribe(ctx, func(
+		notifier *rpc.Notifier,
+		rpcSub *rpc.Subscription,
+		ev finalizedAssertionEvent,
+	) bool {
+		header := AssertionHeader{
+			Number:        hexutil.Uint64(ev.index),
+			NodeHash:      ev.record.nodeHash.ToEthHash(),
+			OnChainTxHash: ev.record.onChainTxHash.ToEthHash(),
+			LogCount:      hexutil.Uint64(len(ev.record.logs)),
+		}
+		if err := notifier.Notify(rpcSub.ID, header); err != nil {
+			log.Println("Failed to send assertion notification", err)
+		}
+		return true
+	}, nil)
+}
+
+// Logs sends a notification for each log matching the given address and topic
+// filters as soon as the assertion containing it is finalized
+func (s *EthServer) Logs(ctx context.Context, crit filters.FilterCriteria) (*rpc.Subscription, error) {
+	addresses, topics := filterArgs(crit)
+	return s.subscribe(ctx, func(
+		notifier *rpc.Notifier,
+		rpcSub *rpc.Subscription,
+		ev finalizedAssertionEvent,
+	) bool {
+		for _, result := range ev.info.FindLogs(addresses, topics) {
+			result.AssertionIndex = int64(ev.index)
+			if err := notifier.Notify(rpcSub.ID, result.EthLog()); err != nil {
+				log.Println("Failed to send log notification", err)
+			}
+		}
+		return true
+	}, nil)
+}
+
+// TxResult sends the receipt of the transaction with the given hash once it
+// has been included in a finalized assertion. If the transaction has already
+// been finalized, the receipt is sent immediately. Only one receipt is ever
+// sent, after which the subscription stops sending notifications
+func (s *EthServer) TxResult(ctx context.Context, txHash ethcommon.Hash) (*rpc.Subscription, error) {
+	arbTxHash := common.NewHashFromEth(txHash)
+	sendReceipt := func(
+		notifier *rpc.Notifier,
+		rpcSub *rpc.Subscription,
+		info txInfo,
+	) {
+		receipt, err := s.receipt(txHash, info)
+		if err != nil {
+			log.Println("Failed to build receipt for tx result", err)
+			return
+		}
+		if err := notifier.Notify(rpcSub.ID, receipt); err != nil {
+			log.Println("Failed to send tx result notification", err)
+		}
+	}
+	return s.subscribe(ctx, func(
+		notifier *rpc.Notifier,
+		rpcSub *rpc.Subscription,
+		ev finalizedAssertionEvent,
+	) bool {
+		for i, hash := range ev.record.txHashes {
+			if hash == arbTxHash {
+				sendReceipt(notifier, rpcSub, ev.info.TxInfo(int(ev.index), i))
+				return false
+			}
+		}
+		return true
+	}, func(notifier *rpc.Notifier, rpcSub *rpc.Subscription) bool {
+		info := <-s.server.tracker.TxInfo(arbTxHash)
+		if !info.Found {
+			return true
+		}
+		sendReceipt(notifier, rpcSub, info)
+		return false
+	})
+}
+
+// subscribe creates a new subscription which calls handle for every assertion
+// finalized after the subscription is created. If initial is set, it is run
+// on the same goroutine before any events are handled, so the two never
+// notify concurrently. Either callback can return false to stop sending
+// events. If the subscriber falls too far behind, the tracker drops it and
+// no more events are sent
+func (s *EthServer) subscribe(
+	ctx context.Context,
+	handle func(*rpc.Notifier, *rpc.Subscription, finalizedAssertionEvent) bool,
+	initial func(*rpc.Notifier, *rpc.Subscription) bool,
+) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	rpcSub := notifier.CreateSubscription()
+
+	sub := s.server.tracker.SubscribeAssertions(subscriptionBufferSize)
+	go func() {
+		defer sub.Unsubscribe()
+		if initial != nil && !initial(notifier, rpcSub) {
+			return
+		}
+		for {
+			select {
+			case ev := <-sub.Events():
+				if !handle(notifier, rpcSub, ev) {
+					return
+				}
+			case <-sub.Dropped():
+				log.Println("Subscription", rpcSub.ID, "fell behind and was dropped")
+				return
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+	return rpcSub, nil
+}
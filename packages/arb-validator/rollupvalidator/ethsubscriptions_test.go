@@ -0,0 +1,138 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rollupvalidator
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// expectReceipts checks that exactly count receipts arrive on receipts
+func expectReceipts(t *testing.T, receipts chan *RPCReceipt, count int) {
+	t.Helper()
+	for i := 0; i < count; i++ {
+		select {
+		case receipt := <-receipts:
+			if receipt.Status != 1 {
+				t.Error("wrong receipt status", receipt.Status)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("expected a tx result notification")
+		}
+	}
+	select {
+	case receipt := <-receipts:
+		t.Error("tx result notified more than once", receipt.TxHash.Hex())
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestTxResultAfterFinalized(t *testing.T) {
+	tracker, assertions, _ := newTestTracker(t)
+	client := newTestEthClient(t, tracker)
+	defer client.Close()
+
+	result, txHash := returnResult(t, 1)
+	assertions <- finalizedAssertion(1, result)
+
+	receipts := make(chan *RPCReceipt, 2)
+	sub, err := client.EthSubscribe(context.Background(), receipts, "txResult", txHash.ToEthHash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Unsubscribe()
+	// Replaying the assertion must not send the result again
+	assertions <- finalizedAssertion(1, result)
+	expectReceipts(t, receipts, 1)
+}
+
+func TestTxResultBeforeFinalized(t *testing.T) {
+	tracker, assertions, _ := newTestTracker(t)
+	client := newTestEthClient(t, tracker)
+	defer client.Close()
+
+	result, txHash := returnResult(t, 1)
+	receipts := make(chan *RPCReceipt, 2)
+	sub, err := client.EthSubscribe(context.Background(), receipts, "txResult", txHash.ToEthHash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Unsubscribe()
+
+	other, _ := returnResult(t, 2)
+	assertions <- finalizedAssertion(1, other)
+	assertions <- finalizedAssertion(2, result)
+	assertions <- finalizedAssertion(2, result)
+	expectReceipts(t, receipts, 1)
+}
+
+func TestLogsOverWebSocket(t *testing.T) {
+	tracker, assertions, _ := newTestTracker(t)
+	server := rpc.NewServer()
+	ethServer := NewEthServer(&Server{rollupAddress: testRollupAddress, tracker: tracker})
+	if err := server.RegisterName("eth", ethServer); err != nil {
+		t.Fatal(err)
+	}
+	httpServer := httptest.NewServer(server.WebsocketHandler([]string{"*"}))
+	defer httpServer.Close()
+
+	client, err := ethclient.Dial("ws" + strings.TrimPrefix(httpServer.URL, "http"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	logs := make(chan types.Log, 2)
+	query := ethereum.FilterQuery{
+		Addresses: []ethcommon.Address{testContract.ToEthAddress()},
+		Topics:    [][]ethcommon.Hash{{testTopic.ToEthHash()}},
+	}
+	sub, err := client.SubscribeFilterLogs(context.Background(), query, logs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Unsubscribe()
+
+	result1, txHash1 := returnResult(t, 1)
+	result2, txHash2 := returnResult(t, 2)
+	assertions <- finalizedAssertion(1, result1, result2)
+	for i, txHash := range []ethcommon.Hash{txHash1.ToEthHash(), txHash2.ToEthHash()} {
+		select {
+		case l := <-logs:
+			if l.TxHash != txHash || l.Index != uint(i) || l.TxIndex != uint(i) {
+				t.Error("wrong log", l)
+			}
+			if l.Address != testContract.ToEthAddress() || !bytes.Equal(l.Data, []byte{1, 2, 3}) {
+				t.Error("wrong log contents", l)
+			}
+		case err := <-sub.Err():
+			t.Fatal("subscription failed", err)
+		case <-time.After(time.Second):
+			t.Fatal("expected a log notification")
+		}
+	}
+}
@@ -34,8 +34,13 @@ type RPCServer struct {
 func NewRPCServer(
 	man *rollupmanager.Manager,
 	maxCallTime time.Duration,
-) *RPCServer {
-	return &RPCServer{Server: NewServer(man, maxCallTime)}
+	txDBPath string,
+) (*RPCServer, error) {
+	server, err := NewServer(man, maxCallTime, txDBPath)
+	if err != nil {
+		return nil, err
+	}
+	return &RPCServer{Server: server}, nil
 }
 
 // FindLogs takes a set of parameters and return the list of all logs that match
@@ -48,18 +48,34 @@ type Server struct {
 	maxCallTime   time.Duration
 }
 
-// NewServer returns a new instance of the Server class
-func NewServer(man *rollupmanager.Manager, maxCallTime time.Duration) *Server {
-	completedAssertionChan := make(chan rollup.FinalizedAssertion)
-	assertionListener := &rollup.AssertionListener{completedAssertionChan}
+// NewServer returns a new instance of the Server class. The index of
+// finalized transactions is stored at txDBPath, or kept in memory if txDBPath
+// is empty
+func NewServer(
+	man *rollupmanager.Manager,
+	maxCallTime time.Duration,
+	txDBPath string,
+) (*Server, error) {
+	db, err := newTxDB(txDBPath)
+	if err != nil {
+		return nil, err
+	}
+
+	assertionListener := &rollup.AssertionListener{
+		CompletedAssertionChan: make(chan rollup.FinalizedAssertion),
+		RolledBackChan:         make(chan uint64),
+	}
 	man.AddListener(assertionListener)
 
-	tracker := newTxTracker(man.RollupAddress)
+	tracker := newTxTracker(db, man.RollupAddress)
 	go func() {
-		tracker.handleTxResults(assertionListener.CompletedAssertionChan)
+		tracker.handleTxResults(
+			assertionListener.CompletedAssertionChan,
+			assertionListener.RolledBackChan,
+		)
 	}()
 
-	return &Server{man.RollupAddress, tracker, man, maxCallTime}
+	return &Server{man.RollupAddress, tracker, man, maxCallTime}, nil
 }
 
 // FindLogs takes a set of parameters and return the list of all logs that match the query
@@ -71,13 +87,13 @@ func (m *Server) FindLogs(ctx context.Context, args *validatorserver.FindLogsArg
 	}
 	addressInt := new(big.Int).SetBytes(addressBytes[:])
 
-	topics := make([]common.Hash, 0, len(args.Topics))
+	topics := make([][]common.Hash, 0, len(args.Topics))
 	for _, topic := range args.Topics {
 		topicBytes, err := hexutil.Decode(topic)
 		if err == nil {
 			var topic common.Hash
 			copy(topic[:], topicBytes)
-			topics = append(topics, topic)
+			topics = append(topics, []common.Hash{topic})
 		}
 	}
 
@@ -87,21 +103,26 @@ func (m *Server) FindLogs(ctx context.Context, args *validatorserver.FindLogsArg
 		return nil, err
 	}
 
-	var logsChan <-chan []*validatorserver.LogInfo
+	addresses := []*big.Int{addressInt}
+	var logsChan <-chan []logResponse
 	if args.ToHeight == "latest" {
-		logsChan = m.tracker.FindLogs(&fromHeight, nil, addressInt, topics)
+		logsChan = m.tracker.FindLogs(&fromHeight, nil, addresses, topics)
 	} else {
 		toHeight, err := strconv.ParseInt(args.ToHeight[2:], 16, 64)
 		if err != nil {
 			fmt.Println("FindLogs error4", err)
 			return nil, err
 		}
-		logsChan = m.tracker.FindLogs(&fromHeight, &toHeight, addressInt, topics)
+		logsChan = m.tracker.FindLogs(&fromHeight, &toHeight, addresses, topics)
 	}
 
 	ret := <-logsChan
+	logs := make([]*validatorserver.LogInfo, 0, len(ret))
+	for _, evmLog := range ret {
+		logs = append(logs, evmLog.LogInfo())
+	}
 	return &validatorserver.FindLogsReply{
-		Logs: ret,
+		Logs: logs,
 	}, nil
 }
 
@@ -171,16 +192,42 @@ func (m *Server) CallMessage(ctx context.Context, args *validatorserver.CallMess
 	var sender common.Address
 	copy(sender[:], senderBytes)
 
+	result, err := m.executeCall(contractAddress, sender, dataBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	_ = value.MarshalValue(result, &buf) // error can only occur from writes and bytes.Buffer is safe
+	return &validatorserver.CallMessageReply{
+		RawVal: hexutil.Encode(buf.Bytes()),
+	}, nil
+}
+
+// executeCall runs a call message against the latest known valid machine and
+// returns the log value the VM produced in response to it
+func (m *Server) executeCall(
+	contractAddress common.Address,
+	sender common.Address,
+	data []byte,
+) (value.Value, error) {
+	currentBlock, err := m.man.CurrentBlockId()
+	if err != nil {
+		return nil, err
+	}
 	msg := message.Call{
 		To:        contractAddress,
 		From:      sender,
-		Data:      dataBytes,
-		BlockNum:  m.man.CurrentBlockId().Height,
+		Data:      data,
+		BlockNum:  currentBlock.Height,
 		Timestamp: big.NewInt(time.Now().Unix()),
 	}
 
 	inbox := message.AddToPrev(value.NewEmptyTuple(), msg)
-	assertion, steps := m.man.ExecuteCall(inbox, m.maxCallTime)
+	assertion, steps, err := m.man.ExecuteCall(inbox, m.maxCallTime)
+	if err != nil {
+		return nil, err
+	}
 
 	log.Println("Executed call for", steps, "steps")
 
@@ -198,11 +245,5 @@ func (m *Server) CallMessage(ctx context.Context, args *validatorserver.CallMess
 		// Last produced log is not the call we sent
 		return nil, errors.New("call took too long to execute")
 	}
-
-	result := results[len(results)-1]
-	var buf bytes.Buffer
-	_ = value.MarshalValue(result, &buf) // error can only occur from writes and bytes.Buffer is safe
-	return &validatorserver.CallMessageReply{
-		RawVal: hexutil.Encode(buf.Bytes()),
-	}, nil
+	return lastLogVal, nil
 }
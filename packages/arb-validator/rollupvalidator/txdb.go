@@ -0,0 +1,280 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rollupvalidator
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/leveldb"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
+)
+
+var (
+	assertionCountKey  = []byte("count")
+	assertionKeyPrefix = []byte("assertion")
+	txKeyPrefix        = []byte("tx")
+)
+
+var errAssertionNotFound = errors.New("assertion not found in tx index")
+
+// assertionRecord is the on-disk representation of a finalized assertion. Tx
+// hashes are kept parallel to logs with a zero hash for logs which weren't
+// valid evm results
+type assertionRecord struct {
+	nodeHash      common.Hash
+	nodeDepth     uint64
+	onChainTxHash common.Hash
+	logs          []value.Value
+	txHashes      []common.Hash
+}
+
+// txDB is a persistent index of finalized assertions and the transactions
+// they contain. Assertions are numbered sequentially in the order that they
+// were finalized
+type txDB struct {
+	db             ethdb.KeyValueStore
+	assertionCount uint64
+}
+
+// newTxDB opens the index stored at path, creating it if necessary. If path
+// is empty, the index is only held in memory
+func newTxDB(path string) (*txDB, error) {
+	var db ethdb.KeyValueStore
+	if path == "" {
+		db = memorydb.New()
+	} else {
+		var err error
+		db, err = leveldb.New(path, 16, 16, "")
+		if err != nil {
+			return nil, err
+		}
+	}
+	return newTxDBWithStore(db)
+}
+
+func newTxDBWithStore(db ethdb.KeyValueStore) (*txDB, error) {
+	ret := &txDB{db: db}
+	has, err := db.Has(assertionCountKey)
+	if err != nil {
+		return nil, err
+	}
+	if has {
+		countBytes, err := db.Get(assertionCountKey)
+		if err != nil {
+			return nil, err
+		}
+		ret.assertionCount = binary.BigEndian.Uint64(countBytes)
+	}
+	return ret, nil
+}
+
+func (tdb *txDB) Close() error {
+	return tdb.db.Close()
+}
+
+func (tdb *txDB) AssertionCount() uint64 {
+	return tdb.assertionCount
+}
+
+// AddAssertion adds the assertion to the end of the index and returns its
+// index. Any previously added assertions at the same or greater node depth
+// come from a branch of the chain which is no longer valid, either because of
+// a reorg or because the assertions are being replayed after a restart, so
+// they are removed first
+func (tdb *txDB) AddAssertion(record *assertionRecord) (uint64, error) {
+	batch := tdb.db.NewBatch()
+	count, err := tdb.removeFromDepth(batch, record.nodeDepth)
+	if err != nil {
+		return 0, err
+	}
+
+	index := count
+	recordBytes, err := marshalAssertionRecord(record)
+	if err != nil {
+		return 0, err
+	}
+	if err := batch.Put(assertionKey(index), recordBytes); err != nil {
+		return 0, err
+	}
+	for i, txHash := range record.txHashes {
+		if txHash == (common.Hash{}) {
+			continue
+		}
+		txBytes, err := proto.Marshal(&TxRecordBuf{
+			AssertionIndex: index,
+			LogIndex:       uint64(i),
+		})
+		if err != nil {
+			return 0, err
+		}
+		if err := batch.Put(txKey(txHash), txBytes); err != nil {
+			return 0, err
+		}
+	}
+	if err := batch.Put(assertionCountKey, encodeUint64(index+1)); err != nil {
+		return 0, err
+	}
+	if err := batch.Write(); err != nil {
+		return 0, err
+	}
+	tdb.assertionCount = index + 1
+	return index, nil
+}
+
+// RollBack removes every assertion whose node is deeper than nodeDepth. It's
+// called when the chain is rebuilt after a reorg so that transactions which
+// were removed from the chain stop being reported before the surviving
+// assertions are replayed
+func (tdb *txDB) RollBack(nodeDepth uint64) error {
+	batch := tdb.db.NewBatch()
+	count, err := tdb.removeFromDepth(batch, nodeDepth+1)
+	if err != nil {
+		return err
+	}
+	if count == tdb.assertionCount {
+		return nil
+	}
+	if err := batch.Put(assertionCountKey, encodeUint64(count)); err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	tdb.assertionCount = count
+	return nil
+}
+
+// removeFromDepth adds the removal of the assertions at the end of the index
+// whose node depth is at least nodeDepth to batch, and returns the number of
+// assertions that will be left
+func (tdb *txDB) removeFromDepth(batch ethdb.Batch, nodeDepth uint64) (uint64, error) {
+	count := tdb.assertionCount
+	for count > 0 {
+		prev, err := tdb.GetAssertion(count - 1)
+		if err != nil {
+			return 0, err
+		}
+		if prev.nodeDepth < nodeDepth {
+			break
+		}
+		for _, txHash := range prev.txHashes {
+			if err := batch.Delete(txKey(txHash)); err != nil {
+				return 0, err
+			}
+		}
+		if err := batch.Delete(assertionKey(count - 1)); err != nil {
+			return 0, err
+		}
+		count--
+	}
+	return count, nil
+}
+
+func (tdb *txDB) GetAssertion(index uint64) (*assertionRecord, error) {
+	if index >= tdb.assertionCount {
+		return nil, errAssertionNotFound
+	}
+	recordBytes, err := tdb.db.Get(assertionKey(index))
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalAssertionRecord(recordBytes)
+}
+
+// GetTx returns the location of the log containing the result of the
+// transaction with the given hash
+func (tdb *txDB) GetTx(txHash common.Hash) (uint64, uint64, bool, error) {
+	key := txKey(txHash)
+	has, err := tdb.db.Has(key)
+	if err != nil || !has {
+		return 0, 0, false, err
+	}
+	txBytes, err := tdb.db.Get(key)
+	if err != nil {
+		return 0, 0, false, err
+	}
+	txRecord := &TxRecordBuf{}
+	if err := proto.Unmarshal(txBytes, txRecord); err != nil {
+		return 0, 0, false, err
+	}
+	return txRecord.AssertionIndex, txRecord.LogIndex, true, nil
+}
+
+func marshalAssertionRecord(record *assertionRecord) ([]byte, error) {
+	logs := make([][]byte, 0, len(record.logs))
+	for _, logVal := range record.logs {
+		var buf bytes.Buffer
+		if err := value.MarshalValue(logVal, &buf); err != nil {
+			return nil, err
+		}
+		logs = append(logs, buf.Bytes())
+	}
+	return proto.Marshal(&AssertionRecordBuf{
+		NodeHash:      record.nodeHash.MarshalToBuf(),
+		NodeDepth:     record.nodeDepth,
+		OnChainTxHash: record.onChainTxHash.MarshalToBuf(),
+		Logs:          logs,
+		TxHashes:      common.MarshalSliceOfHashes(record.txHashes),
+	})
+}
+
+func unmarshalAssertionRecord(data []byte) (*assertionRecord, error) {
+	buf := &AssertionRecordBuf{}
+	if err := proto.Unmarshal(data, buf); err != nil {
+		return nil, err
+	}
+	logs := make([]value.Value, 0, len(buf.Logs))
+	for _, logBytes := range buf.Logs {
+		logVal, err := value.UnmarshalValue(bytes.NewReader(logBytes))
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, logVal)
+	}
+	txHashes := make([]common.Hash, 0, len(buf.TxHashes))
+	for _, txHash := range buf.TxHashes {
+		txHashes = append(txHashes, txHash.Unmarshal())
+	}
+	return &assertionRecord{
+		nodeHash:      buf.NodeHash.Unmarshal(),
+		nodeDepth:     buf.NodeDepth,
+		onChainTxHash: buf.OnChainTxHash.Unmarshal(),
+		logs:          logs,
+		txHashes:      txHashes,
+	}, nil
+}
+
+func encodeUint64(val uint64) []byte {
+	ret := make([]byte, 8)
+	binary.BigEndian.PutUint64(ret, val)
+	return ret
+}
+
+func assertionKey(index uint64) []byte {
+	return append(append([]byte{}, assertionKeyPrefix...), encodeUint64(index)...)
+}
+
+func txKey(txHash common.Hash) []byte {
+	return append(append([]byte{}, txKeyPrefix...), txHash[:]...)
+}
@@ -0,0 +1,289 @@
+//
+// Copyright 2020, Offchain Labs, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.21.0
+// 	protoc        v3.10.1
+// source: txdb.proto
+
+package rollupvalidator
+
+import (
+	proto "github.com/golang/protobuf/proto"
+	common "github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// This is a compile-time assertion that a sufficiently up-to-date version
+// of the legacy proto package is being used.
+const _ = proto.ProtoPackageIsVersion4
+
+type AssertionRecordBuf struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	NodeHash      *common.HashBuf   `protobuf:"bytes,1,opt,name=nodeHash,proto3" json:"nodeHash,omitempty"`
+	NodeDepth     uint64            `protobuf:"varint,2,opt,name=nodeDepth,proto3" json:"nodeDepth,omitempty"`
+	OnChainTxHash *common.HashBuf   `protobuf:"bytes,3,opt,name=onChainTxHash,proto3" json:"onChainTxHash,omitempty"`
+	Logs          [][]byte          `protobuf:"bytes,4,rep,name=logs,proto3" json:"logs,omitempty"`
+	TxHashes      []*common.HashBuf `protobuf:"bytes,5,rep,name=txHashes,proto3" json:"txHashes,omitempty"`
+}
+
+func (x *AssertionRecordBuf) Reset() {
+	*x = AssertionRecordBuf{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_txdb_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AssertionRecordBuf) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssertionRecordBuf) ProtoMessage() {}
+
+func (x *AssertionRecordBuf) ProtoReflect() protoreflect.Message {
+	mi := &file_txdb_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssertionRecordBuf.ProtoReflect.Descriptor instead.
+func (*AssertionRecordBuf) Descriptor() ([]byte, []int) {
+	return file_txdb_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AssertionRecordBuf) GetNodeHash() *common.HashBuf {
+	if x != nil {
+		return x.NodeHash
+	}
+	return nil
+}
+
+func (x *AssertionRecordBuf) GetNodeDepth() uint64 {
+	if x != nil {
+		return x.NodeDepth
+	}
+	return 0
+}
+
+func (x *AssertionRecordBuf) GetOnChainTxHash() *common.HashBuf {
+	if x != nil {
+		return x.OnChainTxHash
+	}
+	return nil
+}
+
+func (x *AssertionRecordBuf) GetLogs() [][]byte {
+	if x != nil {
+		return x.Logs
+	}
+	return nil
+}
+
+func (x *AssertionRecordBuf) GetTxHashes() []*common.HashBuf {
+	if x != nil {
+		return x.TxHashes
+	}
+	return nil
+}
+
+type TxRecordBuf struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	AssertionIndex uint64 `protobuf:"varint,1,opt,name=assertionIndex,proto3" json:"assertionIndex,omitempty"`
+	LogIndex       uint64 `protobuf:"varint,2,opt,name=logIndex,proto3" json:"logIndex,omitempty"`
+}
+
+func (x *TxRecordBuf) Reset() {
+	*x = TxRecordBuf{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_txdb_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TxRecordBuf) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TxRecordBuf) ProtoMessage() {}
+
+func (x *TxRecordBuf) ProtoReflect() protoreflect.Message {
+	mi := &file_txdb_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TxRecordBuf.ProtoReflect.Descriptor instead.
+func (*TxRecordBuf) Descriptor() ([]byte, []int) {
+	return file_txdb_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *TxRecordBuf) GetAssertionIndex() uint64 {
+	if x != nil {
+		return x.AssertionIndex
+	}
+	return 0
+}
+
+func (x *TxRecordBuf) GetLogIndex() uint64 {
+	if x != nil {
+		return x.LogIndex
+	}
+	return 0
+}
+
+var File_txdb_proto protoreflect.FileDescriptor
+
+var file_txdb_proto_rawDesc = []byte{
+	0x0a, 0x0a, 0x74, 0x78, 0x64, 0x62, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0f, 0x72, 0x6f,
+	0x6c, 0x6c, 0x75, 0x70, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x1a, 0x13, 0x63,
+	0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2f, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x22, 0xd7, 0x01, 0x0a, 0x12, 0x41, 0x73, 0x73, 0x65, 0x72, 0x74, 0x69, 0x6f, 0x6e,
+	0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x42, 0x75, 0x66, 0x12, 0x2b, 0x0a, 0x08, 0x6e, 0x6f, 0x64,
+	0x65, 0x48, 0x61, 0x73, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x63, 0x6f,
+	0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x48, 0x61, 0x73, 0x68, 0x42, 0x75, 0x66, 0x52, 0x08, 0x6e, 0x6f,
+	0x64, 0x65, 0x48, 0x61, 0x73, 0x68, 0x12, 0x1c, 0x0a, 0x09, 0x6e, 0x6f, 0x64, 0x65, 0x44, 0x65,
+	0x70, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x6e, 0x6f, 0x64, 0x65, 0x44,
+	0x65, 0x70, 0x74, 0x68, 0x12, 0x35, 0x0a, 0x0d, 0x6f, 0x6e, 0x43, 0x68, 0x61, 0x69, 0x6e, 0x54,
+	0x78, 0x48, 0x61, 0x73, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x63, 0x6f,
+	0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x48, 0x61, 0x73, 0x68, 0x42, 0x75, 0x66, 0x52, 0x0d, 0x6f, 0x6e,
+	0x43, 0x68, 0x61, 0x69, 0x6e, 0x54, 0x78, 0x48, 0x61, 0x73, 0x68, 0x12, 0x12, 0x0a, 0x04, 0x6c,
+	0x6f, 0x67, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x04, 0x6c, 0x6f, 0x67, 0x73, 0x12,
+	0x2b, 0x0a, 0x08, 0x74, 0x78, 0x48, 0x61, 0x73, 0x68, 0x65, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x0f, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x48, 0x61, 0x73, 0x68, 0x42,
+	0x75, 0x66, 0x52, 0x08, 0x74, 0x78, 0x48, 0x61, 0x73, 0x68, 0x65, 0x73, 0x22, 0x51, 0x0a, 0x0b,
+	0x54, 0x78, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x42, 0x75, 0x66, 0x12, 0x26, 0x0a, 0x0e, 0x61,
+	0x73, 0x73, 0x65, 0x72, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x0e, 0x61, 0x73, 0x73, 0x65, 0x72, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x6e,
+	0x64, 0x65, 0x78, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x6f, 0x67, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x6c, 0x6f, 0x67, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x42,
+	0x49, 0x5a, 0x47, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6f, 0x66,
+	0x66, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x6c, 0x61, 0x62, 0x73, 0x2f, 0x61, 0x72, 0x62, 0x69, 0x74,
+	0x72, 0x75, 0x6d, 0x2f, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x73, 0x2f, 0x61, 0x72, 0x62,
+	0x2d, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x2f, 0x72, 0x6f, 0x6c, 0x6c, 0x75,
+	0x70, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
+}
+
+var (
+	file_txdb_proto_rawDescOnce sync.Once
+	file_txdb_proto_rawDescData = file_txdb_proto_rawDesc
+)
+
+func file_txdb_proto_rawDescGZIP() []byte {
+	file_txdb_proto_rawDescOnce.Do(func() {
+		file_txdb_proto_rawDescData = protoimpl.X.CompressGZIP(file_txdb_proto_rawDescData)
+	})
+	return file_txdb_proto_rawDescData
+}
+
+var file_txdb_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_txdb_proto_goTypes = []interface{}{
+	(*AssertionRecordBuf)(nil), // 0: rollupvalidator.AssertionRecordBuf
+	(*TxRecordBuf)(nil),        // 1: rollupvalidator.TxRecordBuf
+	(*common.HashBuf)(nil),     // 2: common.HashBuf
+}
+var file_txdb_proto_depIdxs = []int32{
+	2, // 0: rollupvalidator.AssertionRecordBuf.nodeHash:type_name -> common.HashBuf
+	2, // 1: rollupvalidator.AssertionRecordBuf.onChainTxHash:type_name -> common.HashBuf
+	2, // 2: rollupvalidator.AssertionRecordBuf.txHashes:type_name -> common.HashBuf
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_txdb_proto_init() }
+func file_txdb_proto_init() {
+	if File_txdb_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_txdb_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AssertionRecordBuf); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_txdb_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TxRecordBuf); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_txdb_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_txdb_proto_goTypes,
+		DependencyIndexes: file_txdb_proto_depIdxs,
+		MessageInfos:      file_txdb_proto_msgTypes,
+	}.Build()
+	File_txdb_proto = out.File
+	file_txdb_proto_rawDesc = nil
+	file_txdb_proto_goTypes = nil
+	file_txdb_proto_depIdxs = nil
+}
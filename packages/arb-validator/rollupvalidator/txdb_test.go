@@ -0,0 +1,145 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rollupvalidator
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
+)
+
+func testRecord(depth uint64, txHashes ...common.Hash) *assertionRecord {
+	logs := make([]value.Value, 0, len(txHashes))
+	for range txHashes {
+		logs = append(logs, value.NewInt64Value(int64(depth)))
+	}
+	return &assertionRecord{
+		nodeHash:  common.Hash{byte(depth)},
+		nodeDepth: depth,
+		logs:      logs,
+		txHashes:  txHashes,
+	}
+}
+
+func addRecords(t *testing.T, db *txDB, records ...*assertionRecord) {
+	for _, record := range records {
+		if _, err := db.AddAssertion(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func checkTx(t *testing.T, db *txDB, txHash common.Hash, found bool, assertionIndex uint64) {
+	t.Helper()
+	index, _, ok, err := db.GetTx(txHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok != found {
+		t.Errorf("tx %v found is %v, expected %v", txHash, ok, found)
+	} else if found && index != assertionIndex {
+		t.Errorf("tx %v in assertion %v, expected %v", txHash, index, assertionIndex)
+	}
+}
+
+func TestTxDBReplaceAssertion(t *testing.T) {
+	db, err := newTxDB("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	txA, txB, txC := common.Hash{1}, common.Hash{2}, common.Hash{3}
+	addRecords(t, db, testRecord(1, txA), testRecord(2, txB))
+
+	index, err := db.AddAssertion(testRecord(2, txC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if index != 1 || db.AssertionCount() != 2 {
+		t.Fatal("replacement added at", index, "with count", db.AssertionCount())
+	}
+	checkTx(t, db, txA, true, 0)
+	checkTx(t, db, txB, false, 0)
+	checkTx(t, db, txC, true, 1)
+	record, err := db.GetAssertion(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(record.txHashes) != 1 || record.txHashes[0] != txC {
+		t.Error("assertion wasn't replaced", record.txHashes)
+	}
+}
+
+func TestTxDBRollBack(t *testing.T) {
+	store := memorydb.New()
+	db, err := newTxDBWithStore(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	txA, txB, txC := common.Hash{1}, common.Hash{2}, common.Hash{3}
+	addRecords(t, db, testRecord(1, txA), testRecord(2, txB), testRecord(3, txC))
+
+	if err := db.RollBack(1); err != nil {
+		t.Fatal(err)
+	}
+	if db.AssertionCount() != 1 {
+		t.Fatal("wrong count after rollback", db.AssertionCount())
+	}
+	checkTx(t, db, txA, true, 0)
+	checkTx(t, db, txB, false, 0)
+	checkTx(t, db, txC, false, 0)
+	if _, err := db.GetAssertion(1); err != errAssertionNotFound {
+		t.Error("rolled back assertion still present", err)
+	}
+
+	if err := db.RollBack(5); err != nil {
+		t.Fatal(err)
+	}
+	if db.AssertionCount() != 1 {
+		t.Error("rollback past the end removed assertions", db.AssertionCount())
+	}
+
+	reopened, err := newTxDBWithStore(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reopened.AssertionCount() != 1 {
+		t.Error("rollback wasn't persisted", reopened.AssertionCount())
+	}
+	checkTx(t, reopened, txB, false, 0)
+}
+
+func TestTrackerRollsBackOnRestart(t *testing.T) {
+	tracker, assertions, rollbacks := newTestTracker(t)
+	first, firstHash := returnResult(t, 1)
+	second, secondHash := returnResult(t, 2)
+	assertions <- finalizedAssertion(1, first)
+	assertions <- finalizedAssertion(2, second)
+
+	rollbacks <- 1
+	if info := <-tracker.TxInfo(secondHash); info.Found {
+		t.Error("tx from rolled back assertion still found")
+	}
+	if info := <-tracker.TxInfo(firstHash); !info.Found {
+		t.Error("tx from surviving assertion not found")
+	}
+	if count := <-tracker.AssertionCount(); count != 0 {
+		t.Error("wrong latest assertion after rollback", count)
+	}
+}
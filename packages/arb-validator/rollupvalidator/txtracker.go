@@ -20,6 +20,7 @@ import (
 	"log"
 	"math/big"
 	"strconv"
+	"sync"
 
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/validatorserver"
 
@@ -47,15 +48,26 @@ type txRequest struct {
 type findLogsRequest struct {
 	fromHeight *int64
 	toHeight   *int64
-	address    *big.Int
-	topics     []common.Hash
+	addresses  []*big.Int
+	topics     [][]common.Hash
 
-	resultChan chan<- []*validatorserver.LogInfo
+	resultChan chan<- []logResponse
+}
+
+// finalizedAssertionEvent is sent to subscribers of the txTracker each time
+// an assertion is added to the index
+type finalizedAssertionEvent struct {
+	index  uint64
+	record *assertionRecord
+	info   *assertionInfo
 }
 
 type logsInfo struct {
 	msg  evm.EthBridgeMessage
 	Logs []evm.Log
+
+	// txIndex is the position of the transaction's result in the assertion
+	txIndex int
 }
 
 type txInfo struct {
@@ -66,73 +78,214 @@ type txInfo struct {
 	LogsPostHash   string
 	LogsValHashes  []string
 	OnChainTxHash  string
+	txIndex        int
+
+	// firstLogIndex is the index within the assertion of the transaction's
+	// first EVM log, counted the same way as by FindLogs
+	firstLogIndex int
 }
 
 type assertionInfo struct {
-	TxLogs            []logsInfo
-	LogsAccHashes     []string
-	LogsValHashes     []string
-	SequenceNum       uint64
-	BeforeHash        common.Hash
-	OriginalInboxHash common.Hash
+	TxLogs        []logsInfo
+	LogsAccHashes []string
+	LogsValHashes []string
+	Logs          []value.Value
+	OnChainTxHash common.Hash
 }
 
 type logResponse struct {
-	Log evm.Log
-	Msg evm.EthBridgeMessage
+	Log            evm.Log
+	Msg            evm.EthBridgeMessage
+	AssertionIndex int64
+	TxIndex        int64
+	LogIndex       int64
 }
 
-func (a *assertionInfo) FindLogs(address *big.Int, topics []common.Hash) []logResponse {
-	logs := make([]logResponse, 0)
-	for _, txLogs := range a.TxLogs {
-		for _, evmLog := range txLogs.Logs {
-			if address != nil && !value.NewIntValue(address).Equal(evmLog.ContractID) {
-				continue
-			}
+func (l logResponse) LogInfo() *validatorserver.LogInfo {
+	addressBytes := l.Log.ContractID.ToBytes()
+	topicStrings := make([]string, 0, len(l.Log.Topics))
+	for _, topic := range l.Log.Topics {
+		topicStrings = append(topicStrings, hexutil.Encode(topic[:]))
+	}
+
+	return &validatorserver.LogInfo{
+		Address:          hexutil.Encode(addressBytes[12:]),
+		BlockHash:        hexutil.Encode(l.Msg.TxHash[:]),
+		BlockNumber:      "0x" + strconv.FormatInt(l.AssertionIndex, 16),
+		Data:             hexutil.Encode(l.Log.Data[:]),
+		LogIndex:         "0x" + strconv.FormatInt(l.LogIndex, 16),
+		Topics:           topicStrings,
+		TransactionIndex: "0x" + strconv.FormatInt(l.TxIndex, 16),
+		TransactionHash:  hexutil.Encode(l.Msg.TxHash[:]),
+	}
+}
 
-			if len(topics) > len(evmLog.Topics) {
-				continue
+// matchesFilter checks the log against a set of addresses and topics using
+// the same semantics as an Ethereum log filter: an empty address list
+// matches any contract and an empty topic position matches any topic
+func matchesFilter(evmLog evm.Log, addresses []*big.Int, topics [][]common.Hash) bool {
+	if len(addresses) > 0 {
+		found := false
+		for _, address := range addresses {
+			if value.NewIntValue(address).Equal(evmLog.ContractID) {
+				found = true
+				break
 			}
+		}
+		if !found {
+			return false
+		}
+	}
 
-			match := true
-			for i, topic := range topics {
-				if topic != evmLog.Topics[i] {
-					match = false
-					break
-				}
+	if len(topics) > len(evmLog.Topics) {
+		return false
+	}
+
+	for i, options := range topics {
+		if len(options) == 0 {
+			continue
+		}
+		found := false
+		for _, topic := range options {
+			if topic == evmLog.Topics[i] {
+				found = true
+				break
 			}
-			if match {
-				logs = append(logs, logResponse{evmLog, txLogs.msg})
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func (a *assertionInfo) FindLogs(addresses []*big.Int, topics [][]common.Hash) []logResponse {
+	logs := make([]logResponse, 0)
+	logIndex := int64(0)
+	for _, txLogs := range a.TxLogs {
+		for _, evmLog := range txLogs.Logs {
+			if matchesFilter(evmLog, addresses, topics) {
+				logs = append(logs, logResponse{
+					Log:      evmLog,
+					Msg:      txLogs.msg,
+					TxIndex:  int64(txLogs.txIndex),
+					LogIndex: logIndex,
+				})
 			}
+			logIndex++
 		}
 	}
 	return logs
 }
 
-func newAssertionInfo() *assertionInfo {
-	return &assertionInfo{}
+func newAssertionInfo(record *assertionRecord, vmID common.Address) *assertionInfo {
+	info := &assertionInfo{
+		Logs:          record.logs,
+		OnChainTxHash: record.onChainTxHash,
+	}
+	info.LogsValHashes = make([]string, 0, len(record.logs))
+	info.LogsAccHashes = make([]string, 0, len(record.logs))
+	acc := common.Hash{}
+	for _, logsVal := range record.logs {
+		logsValHash := logsVal.Hash()
+		info.LogsValHashes = append(info.LogsValHashes,
+			hexutil.Encode(logsValHash[:]))
+		acc = hashing.SoliditySHA3(
+			hashing.Bytes32(acc),
+			hashing.Bytes32(logsValHash),
+		)
+		info.LogsAccHashes = append(info.LogsAccHashes,
+			hexutil.Encode(acc.Bytes()))
+	}
+
+	for i, logVal := range record.logs {
+		evmVal, err := evm.ProcessLog(logVal, vmID)
+		if err != nil {
+			continue
+		}
+		switch evmVal := evmVal.(type) {
+		case evm.Stop:
+			info.TxLogs = append(info.TxLogs, logsInfo{evmVal.Msg, evmVal.Logs, i})
+		case evm.Return:
+			info.TxLogs = append(info.TxLogs, logsInfo{evmVal.Msg, evmVal.Logs, i})
+		}
+	}
+	return info
+}
+
+func (a *assertionInfo) TxInfo(assertionIndex int, logIndex int) txInfo {
+	zero := common.Hash{}
+	logsPreHash := hexutil.Encode(zero[:])
+	if logIndex > 0 {
+		logsPreHash = a.LogsAccHashes[logIndex-1] // Previous acc hash
+	}
+	logsPostHash := a.LogsAccHashes[len(a.LogsAccHashes)-1]
+	firstLogIndex := 0
+	for _, txLogs := range a.TxLogs {
+		if txLogs.txIndex >= logIndex {
+			break
+		}
+		firstLogIndex += len(txLogs.Logs)
+	}
+	return txInfo{
+		Found:          true,
+		assertionIndex: assertionIndex,
+		txIndex:        logIndex,
+		firstLogIndex:  firstLogIndex,
+		RawVal:         a.Logs[logIndex],
+		LogsPreHash:    logsPreHash,
+		LogsPostHash:   logsPostHash,
+		LogsValHashes:  a.LogsValHashes[logIndex+1:], // log acc hashes after logVal
+		OnChainTxHash:  hexutil.Encode(a.OnChainTxHash[:]),
+	}
+}
+
+// assertionSubscription receives an event for every assertion added to the
+// index after it was created. Events are buffered, and a subscriber which
+// falls further behind than the buffer is dropped rather than holding up the
+// tracker
+type assertionSubscription struct {
+	tracker *txTracker
+	events  chan finalizedAssertionEvent
+	dropped chan struct{}
+}
+
+// Events returns the channel that events are delivered on
+func (s *assertionSubscription) Events() <-chan finalizedAssertionEvent {
+	return s.events
+}
+
+// Dropped is closed if the subscriber was dropped for falling behind
+func (s *assertionSubscription) Dropped() <-chan struct{} {
+	return s.dropped
+}
+
+// Unsubscribe stops the delivery of events. It is safe to call more than once
+func (s *assertionSubscription) Unsubscribe() {
+	s.tracker.subscribersMu.Lock()
+	delete(s.tracker.subscribers, s)
+	s.tracker.subscribersMu.Unlock()
 }
 
 type txTracker struct {
-	txRequestIndex int
-	transactions   map[common.Hash]txInfo
-	assertionInfo  []*assertionInfo
-	accountNonces  map[common.Address]uint64
-	vmID           common.Address
-	requests       chan validatorRequest
+	db       *txDB
+	vmID     common.Address
+	requests chan validatorRequest
+
+	subscribersMu sync.Mutex
+	subscribers   map[*assertionSubscription]struct{}
 }
 
 func newTxTracker(
+	db *txDB,
 	vmID common.Address,
 ) *txTracker {
 	requests := make(chan validatorRequest, 100)
 	return &txTracker{
-		txRequestIndex: 0,
-		transactions:   make(map[common.Hash]txInfo),
-		assertionInfo:  make([]*assertionInfo, 0),
-		accountNonces:  make(map[common.Address]uint64),
-		vmID:           vmID,
-		requests:       requests,
+		db:          db,
+		vmID:        vmID,
+		requests:    requests,
+		subscribers: make(map[*assertionSubscription]struct{}),
 	}
 }
 
@@ -151,96 +304,114 @@ func (tr *txTracker) TxInfo(txHash common.Hash) <-chan txInfo {
 func (tr *txTracker) FindLogs(
 	fromHeight *int64,
 	toHeight *int64,
-	address *big.Int,
-	topics []common.Hash,
-) <-chan []*validatorserver.LogInfo {
-	req := make(chan []*validatorserver.LogInfo, 1)
-	tr.requests <- findLogsRequest{fromHeight, toHeight, address, topics, req}
+	addresses []*big.Int,
+	topics [][]common.Hash,
+) <-chan []logResponse {
+	req := make(chan []logResponse, 1)
+	tr.requests <- findLogsRequest{fromHeight, toHeight, addresses, topics, req}
 	return req
 }
 
 func (tr *txTracker) processFinalizedAssertion(assertion rollup.FinalizedAssertion) {
-	info := newAssertionInfo()
-
-	zero := common.Hash{}
-	logsPreHash := hexutil.Encode(zero[:])
-
-	disputableTxHash := hexutil.Encode(assertion.OnChainTxHash[:])
-
 	logs := assertion.Assertion.Logs
-	info.LogsValHashes = make([]string, 0, len(logs))
-	info.LogsAccHashes = make([]string, 0, len(logs))
-	acc := common.Hash{}
-	for _, logsVal := range logs {
-		logsValHash := logsVal.Hash()
-		info.LogsValHashes = append(info.LogsValHashes,
-			hexutil.Encode(logsValHash[:]))
-		acc = hashing.SoliditySHA3(
-			hashing.Bytes32(acc),
-			hashing.Bytes32(logsValHash),
-		)
-		info.LogsAccHashes = append(info.LogsAccHashes,
-			hexutil.Encode(acc.Bytes()))
-	}
-
-	var logsPostHash string
-	if len(logs) > 0 {
-		logsPostHash = info.LogsAccHashes[len(info.LogsAccHashes)-1]
-	} else {
-		logsPostHash = hexutil.Encode(zero[:])
-	}
-
-	for i, logVal := range logs {
-		if i > 0 {
-			logsPreHash = info.LogsAccHashes[i-1] // Previous acc hash
-		}
-		logsValHashes := info.LogsValHashes[i+1:] // log acc hashes after logVal
-
-		txInfo := txInfo{
-			Found:          true,
-			assertionIndex: len(tr.assertionInfo),
-			RawVal:         logVal,
-			LogsPreHash:    logsPreHash,
-			LogsPostHash:   logsPostHash,
-			LogsValHashes:  logsValHashes,
-			OnChainTxHash:  disputableTxHash,
-		}
-
+	txHashes := make([]common.Hash, 0, len(logs))
+	for _, logVal := range logs {
 		evmVal, err := evm.ProcessLog(logVal, tr.vmID)
 		if err != nil {
 			log.Printf("VM produced invalid evm result: %v\n", err)
+			txHashes = append(txHashes, common.Hash{})
 			continue
 		}
-		switch evmVal := evmVal.(type) {
-		case evm.Stop:
-			info.TxLogs = append(info.TxLogs, logsInfo{evmVal.Msg, evmVal.Logs})
-		case evm.Return:
-			info.TxLogs = append(info.TxLogs, logsInfo{evmVal.Msg, evmVal.Logs})
-		case evm.Revert:
+		if evmVal, ok := evmVal.(evm.Revert); ok {
 			log.Printf("*********** evm.Revert occurred with message \"%v\"\n", string(evmVal.ReturnVal))
 		}
 
 		msg := evmVal.GetEthMsg()
 		log.Println("Coordinator got response for", hexutil.Encode(msg.TxHash[:]))
-		tr.transactions[msg.TxHash] = txInfo
+		txHashes = append(txHashes, msg.TxHash)
+	}
+
+	record := &assertionRecord{
+		nodeHash:      assertion.NodeHash,
+		nodeDepth:     assertion.NodeDepth,
+		onChainTxHash: assertion.OnChainTxHash,
+		logs:          logs,
+		txHashes:      txHashes,
+	}
+	index, err := tr.db.AddAssertion(record)
+	if err != nil {
+		log.Println("Failed to record finalized assertion", err)
+		return
 	}
-	tr.assertionInfo = append(tr.assertionInfo, info)
+	tr.notifySubscribers(finalizedAssertionEvent{
+		index:  index,
+		record: record,
+		info:   newAssertionInfo(record, tr.vmID),
+	})
+}
+
+// notifySubscribers sends ev to every subscriber without blocking. Any
+// subscriber whose buffer is full is dropped
+func (tr *txTracker) notifySubscribers(ev finalizedAssertionEvent) {
+	tr.subscribersMu.Lock()
+	defer tr.subscribersMu.Unlock()
+	for sub := range tr.subscribers {
+		select {
+		case sub.events <- ev:
+		default:
+			log.Println("Dropping assertion subscriber which fell behind")
+			delete(tr.subscribers, sub)
+			close(sub.dropped)
+		}
+	}
+}
+
+// SubscribeAssertions returns a subscription to every assertion added to the
+// index from now on. At most bufferSize events are held for the subscriber
+func (tr *txTracker) SubscribeAssertions(bufferSize int) *assertionSubscription {
+	sub := &assertionSubscription{
+		tracker: tr,
+		events:  make(chan finalizedAssertionEvent, bufferSize),
+		dropped: make(chan struct{}),
+	}
+	tr.subscribersMu.Lock()
+	tr.subscribers[sub] = struct{}{}
+	tr.subscribersMu.Unlock()
+	return sub
+}
+
+func (tr *txTracker) getAssertionInfo(index uint64) (*assertionInfo, error) {
+	record, err := tr.db.GetAssertion(index)
+	if err != nil {
+		return nil, err
+	}
+	return newAssertionInfo(record, tr.vmID), nil
 }
 
 func (tr *txTracker) processRequest(request validatorRequest) {
 	switch request := request.(type) {
 	case assertionCountRequest:
-		request.resultChan <- len(tr.assertionInfo) - 1
+		request.resultChan <- int(tr.db.AssertionCount()) - 1
 	case txRequest:
-		tx, ok := tr.transactions[request.txHash]
-		if ok {
-			request.resultChan <- tx
-		} else {
+		assertionIndex, logIndex, found, err := tr.db.GetTx(request.txHash)
+		if err != nil {
+			log.Println("Error looking up tx", err)
+		}
+		if !found {
+			request.resultChan <- txInfo{Found: false}
+			break
+		}
+		info, err := tr.getAssertionInfo(assertionIndex)
+		if err != nil {
+			log.Println("Error looking up assertion", err)
 			request.resultChan <- txInfo{Found: false}
+			break
 		}
+		request.resultChan <- info.TxInfo(int(assertionIndex), int(logIndex))
 	case findLogsRequest:
+		assertionCount := int64(tr.db.AssertionCount())
 		startHeight := int64(0)
-		endHeight := int64(len(tr.assertionInfo))
+		endHeight := assertionCount
 		if request.fromHeight != nil && *request.fromHeight > int64(0) {
 			startHeight = *request.fromHeight
 		}
@@ -250,43 +421,35 @@ func (tr *txTracker) processRequest(request validatorRequest) {
 				endHeight = altEndHeight
 			}
 		}
-		logs := make([]*validatorserver.LogInfo, 0)
-		if startHeight >= int64(len(tr.assertionInfo)) {
-			request.resultChan <- logs
-			break
-		}
-		assertions := tr.assertionInfo[startHeight:endHeight]
-
-		for i, assertion := range assertions {
-			assertionLogs := assertion.FindLogs(request.address, request.topics)
-			for j, evmLog := range assertionLogs {
-				addressBytes := evmLog.Log.ContractID.ToBytes()
-				topicStrings := make([]string, 0, len(evmLog.Log.Topics))
-				for _, topic := range evmLog.Log.Topics {
-					topicStrings = append(topicStrings, hexutil.Encode(topic[:]))
-				}
-
-				logs = append(logs, &validatorserver.LogInfo{
-					Address:          hexutil.Encode(addressBytes[12:]),
-					BlockHash:        hexutil.Encode(evmLog.Msg.TxHash[:]),
-					BlockNumber:      "0x" + strconv.FormatInt(startHeight+int64(i), 16),
-					Data:             hexutil.Encode(evmLog.Log.Data[:]),
-					LogIndex:         "0x" + strconv.FormatInt(int64(j), 16),
-					Topics:           topicStrings,
-					TransactionIndex: "0x0",
-					TransactionHash:  hexutil.Encode(evmLog.Msg.TxHash[:]),
-				})
+		logs := make([]logResponse, 0)
+		for i := startHeight; i < endHeight; i++ {
+			assertion, err := tr.getAssertionInfo(uint64(i))
+			if err != nil {
+				log.Println("Error looking up assertion", err)
+				break
+			}
+			assertionLogs := assertion.FindLogs(request.addresses, request.topics)
+			for _, evmLog := range assertionLogs {
+				evmLog.AssertionIndex = i
+				logs = append(logs, evmLog)
 			}
 		}
 		request.resultChan <- logs
 	}
 }
 
-func (tr *txTracker) handleTxResults(completedCalls chan rollup.FinalizedAssertion) {
+func (tr *txTracker) handleTxResults(
+	completedCalls chan rollup.FinalizedAssertion,
+	rollbacks chan uint64,
+) {
 	for {
 		select {
 		case finalizedAssertion := <-completedCalls:
 			tr.processFinalizedAssertion(finalizedAssertion)
+		case nodeDepth := <-rollbacks:
+			if err := tr.db.RollBack(nodeDepth); err != nil {
+				log.Println("Failed to roll back finalized assertions", err)
+			}
 		case request := <-tr.requests:
 			tr.processRequest(request)
 		}
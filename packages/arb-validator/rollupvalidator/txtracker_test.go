@@ -0,0 +1,87 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rollupvalidator
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSubscribeAssertions(t *testing.T) {
+	tracker, assertions, _ := newTestTracker(t)
+	sub := tracker.SubscribeAssertions(2)
+
+	result, txHash := returnResult(t, 1)
+	assertions <- finalizedAssertion(1, result)
+	select {
+	case ev := <-sub.Events():
+		if ev.index != 0 || len(ev.record.txHashes) != 1 || ev.record.txHashes[0] != txHash {
+			t.Error("wrong event", ev.index, ev.record.txHashes)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no event for finalized assertion")
+	}
+
+	sub.Unsubscribe()
+	sub.Unsubscribe()
+	assertions <- finalizedAssertion(2)
+	// The tracker has handled the assertion once it answers a later request
+	<-tracker.AssertionCount()
+	select {
+	case ev := <-sub.Events():
+		t.Error("event sent after unsubscribing", ev.index)
+	default:
+	}
+}
+
+func TestSlowSubscriberIsDropped(t *testing.T) {
+	tracker, assertions, _ := newTestTracker(t)
+	slow := tracker.SubscribeAssertions(2)
+	fast := tracker.SubscribeAssertions(2)
+
+	received := 0
+	for depth := uint64(1); depth <= 4; depth++ {
+		select {
+		case assertions <- finalizedAssertion(depth):
+		case <-time.After(time.Second):
+			t.Fatal("tracker blocked on a slow subscriber")
+		}
+		<-tracker.AssertionCount()
+		select {
+		case <-fast.Events():
+			received++
+		default:
+		}
+	}
+	if received != 4 {
+		t.Error("subscriber which kept up got", received, "events")
+	}
+
+	select {
+	case <-slow.Dropped():
+	default:
+		t.Fatal("slow subscriber wasn't dropped")
+	}
+	select {
+	case <-fast.Dropped():
+		t.Error("subscriber which kept up was dropped")
+	default:
+	}
+	if len(slow.Events()) != 2 {
+		t.Error("slow subscriber should keep its buffered events", len(slow.Events()))
+	}
+}
@@ -0,0 +1,51 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package testmachine
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-cpp/cmachine"
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/fuzz"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/machine"
+)
+
+// CppEngine runs fuzzed programs on the C++ AVM
+type CppEngine struct{}
+
+func (CppEngine) Name() string {
+	return "cpp"
+}
+
+// NewMachine writes the program to a temporary file since the C++ AVM only
+// loads programs from files
+func (CppEngine) NewMachine(c *fuzz.Case) (machine.Machine, error) {
+	f, err := ioutil.TempFile("", "fuzz-*.ao")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(f.Name())
+	if err := c.AO.Write(f); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if err := f.Close(); err != nil {
+		return nil, err
+	}
+	return cmachine.New(f.Name())
+}
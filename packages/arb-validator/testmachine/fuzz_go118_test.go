@@ -0,0 +1,56 @@
+//go:build go1.18
+// +build go1.18
+
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package testmachine
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/fuzz"
+)
+
+// The fuzz target needs testing.F, which was added in go 1.18. TestRepros
+// still runs with older toolchains
+
+// FuzzDifferential runs generated programs on the Go and C++ AVMs and checks
+// that they agree at every step. A divergence is minimized and written to
+// testdata/repro, where TestRepros runs it from then on
+func FuzzDifferential(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte("arbitrum"))
+	f.Add([]byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c})
+	f.Add([]byte{0x37, 0xc1, 0x05, 0x37, 0xc1, 0x05, 0x37, 0xc1, 0x05, 0x37, 0xc1, 0x05})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		minimized, d, err := fuzz.Check(engines, fuzz.Generate(data), fuzzConfig(t))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d == nil {
+			return
+		}
+		sum := sha256.Sum256(data)
+		name := hex.EncodeToString(sum[:8])
+		if err := fuzz.WriteRepro(reproDir, name, minimized.Build(), d); err != nil {
+			t.Fatal(err)
+		}
+		t.Fatalf("%v\nminimized program written to %v/%v.ao", d, reproDir, name)
+	})
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package testmachine
+
+import (
+	"context"
+	"flag"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/fuzz"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/ethbridge"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/test"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator/proofmachine"
+)
+
+var checkOSP = flag.Bool("osp", false, "check every proof with a one step proof contract deployed to the test node")
+
+const reproDir = "testdata/repro"
+
+var engines = []fuzz.Engine{fuzz.GoEngine{}, CppEngine{}}
+
+var (
+	proverOnce sync.Once
+	prover     fuzz.ProofChecker
+	proverErr  error
+)
+
+func deployProver() {
+	auth, err := test.SetupAuth("9af1e691e3db692cc9cad4e87b6490e099eb291e3b434a0d3f014dfd2bb747cc")
+	if err != nil {
+		proverErr = err
+		return
+	}
+	ethclint, err := ethclient.Dial(test.GetEthUrl())
+	if err != nil {
+		proverErr = err
+		return
+	}
+	client := ethbridge.NewEthAuthClient(ethclint, auth)
+	osp, err := client.DeployOneStepProof(context.Background())
+	if err != nil {
+		proverErr = err
+		return
+	}
+	prover = proofmachine.NewProofChecker(osp)
+}
+
+// fuzzConfig compares the proofs of every step, and checks them with the one
+// step proof contract if -osp is given
+func fuzzConfig(t *testing.T) fuzz.Config {
+	cfg := fuzz.DefaultConfig()
+	cfg.Proofs = true
+	if !*checkOSP {
+		return cfg
+	}
+	proverOnce.Do(deployProver)
+	if proverErr != nil {
+		t.Fatal(proverErr)
+	}
+	cfg.Prover = prover
+	return cfg
+}
+
+// TestRepros checks that the engines agree on every divergence which
+// FuzzDifferential has found
+func TestRepros(t *testing.T) {
+	names, err := fuzz.ReproNames(reproDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range names {
+		name := name
+		t.Run(name, func(t *testing.T) {
+			c, err := fuzz.ReadRepro(reproDir, name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			d, err := fuzz.Run(engines, c, fuzzConfig(t))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d != nil {
+				t.Error(d)
+			}
+		})
+	}
+}